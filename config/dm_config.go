@@ -2,14 +2,68 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type DecisionMakerConfig struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	Logging LoggingConfig `mapstructure:"logging"`
-	Token   TokenConfig   `mapstructure:"token"`
+	Server   ServerConfig   `mapstructure:"server"`
+	Logging  LoggingConfig  `mapstructure:"logging"`
+	Token    TokenConfig    `mapstructure:"token"`
+	Store    StoreConfig    `mapstructure:"store"`
+	Resolver ResolverConfig `mapstructure:"resolver"`
+	// UnixSocket serves the scheduler facing routes to local processes without JWT authentication
+	UnixSocket UnixSocketConfig `mapstructure:"unix_socket"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	Watchdog   WatchdogConfig   `mapstructure:"watchdog"`
+	SchedExt   SchedExtConfig   `mapstructure:"sched_ext"`
+}
+
+// SchedExtConfig locates the sched_ext state in sysfs
+type SchedExtConfig struct {
+	SysfsRoot string `mapstructure:"sysfs_root"` // read from <sysfs_root>/kernel/sched_ext, "/sys" by default
+}
+
+// WatchdogConfig controls the detection of a stalled scheduler, which is stalled when it did not push metrics
+// or its last run timestamp did not advance for longer than StaleThreshold
+type WatchdogConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	StaleThreshold time.Duration `mapstructure:"stale_threshold"`
+	CheckInterval  time.Duration `mapstructure:"check_interval"`
+}
+
+// MetricsConfig bounds the per-task series the decision maker exports from the statistics pushed by the scheduler
+type MetricsConfig struct {
+	PerPID    bool `mapstructure:"per_pid"`    // label task series with the PID instead of aggregating them per pod
+	MaxSeries int  `mapstructure:"max_series"` // label sets beyond the limit are folded into a single overflow series
+	// HistoryRetention and HistoryMaxPoints bound the in-memory history of the node metrics
+	HistoryRetention time.Duration `mapstructure:"history_retention"`
+	HistoryMaxPoints int           `mapstructure:"history_max_points"`
+}
+
+// UnixSocketConfig controls the optional Unix socket listener. Peers are authenticated by the file mode of the
+// socket and by their SO_PEERCRED credentials, empty allow lists accept every peer able to open the socket.
+type UnixSocketConfig struct {
+	Enabled     bool     `mapstructure:"enabled"`
+	Path        string   `mapstructure:"path"`
+	Mode        string   `mapstructure:"mode"` // octal file mode of the socket, e.g. "0660"
+	AllowedUIDs []uint32 `mapstructure:"allowed_uids"`
+	AllowedGIDs []uint32 `mapstructure:"allowed_gids"`
+}
+
+// ResolverConfig controls the loop that keeps the PIDs covered by each pod-level intent up to date
+type ResolverConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval"`
+	ProcConnector bool          `mapstructure:"proc_connector"` // also rescan on fork/exec/exit events of the Linux proc connector
+	Debounce      time.Duration `mapstructure:"debounce"`
+}
+
+// StoreConfig controls the on-disk snapshot of the intent set, so that intents survive restarts of the decision maker
+type StoreConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"` // snapshot file, usually on a hostPath volume
 }
 
 var (
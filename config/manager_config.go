@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -30,12 +31,18 @@ type LoggingConfig struct {
 }
 
 type ManageConfig struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	Logging LoggingConfig `mapstructure:"logging"`
-	MongoDB MongoDBConfig `mapstructure:"mongodb"`
-	Key     KeyConfig     `mapstructure:"key"`
-	Account AccountConfig `mapstructure:"account"`
-	K8S     K8SConfig     `mapstructure:"k8s"`
+	Server        ServerConfig                 `mapstructure:"server"`
+	Logging       LoggingConfig                `mapstructure:"logging"`
+	MongoDB       MongoDBConfig                `mapstructure:"mongodb"`
+	Key           KeyConfig                    `mapstructure:"key"`
+	Account       AccountConfig                `mapstructure:"account"`
+	K8S           K8SConfig                    `mapstructure:"k8s"`
+	Reconciler    ReconcilerConfig             `mapstructure:"reconciler"`
+	Outbox        OutboxConfig                 `mapstructure:"outbox"`
+	NodeMetrics   NodeMetricsConfig            `mapstructure:"node_metrics"`
+	NodeRegistry  NodeRegistryConfig           `mapstructure:"node_registry"`
+	DecisionMaker DecisionMakerDiscoveryConfig `mapstructure:"decision_maker"`
+	Strategy      StrategyConfig               `mapstructure:"strategy"`
 }
 
 type MongoDBConfig struct {
@@ -69,6 +76,56 @@ type K8SConfig struct {
 	IsInCluster    bool   `mapstructure:"in_cluster"`
 }
 
+// ReconcilerConfig controls the loop that keeps schedule intents in sync with the pods selected by each strategy
+type ReconcilerConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	Debounce       time.Duration `mapstructure:"debounce"`
+	ResyncInterval time.Duration `mapstructure:"resync_interval"`
+}
+
+// OutboxConfig controls the worker that delivers pending schedule intents to the decision makers
+type OutboxConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	// MaxRetractAttempts caps the delivery attempts of a retracting intent, it is marked failed afterwards
+	MaxRetractAttempts int `mapstructure:"max_retract_attempts"`
+}
+
+// NodeMetricsConfig controls the loop that scrapes the scheduler metrics from the decision maker of every node
+type NodeMetricsConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	ScrapeInterval time.Duration `mapstructure:"scrape_interval"`
+	ScrapeTimeout  time.Duration `mapstructure:"scrape_timeout"`
+}
+
+// NodeRegistryConfig controls the loop that records the nodes running a decision maker and probes their health
+type NodeRegistryConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+	ProbeTimeout  time.Duration `mapstructure:"probe_timeout"`
+}
+
+// StrategyConfig controls how schedule strategies selecting the same pods are handled
+type StrategyConfig struct {
+	// RejectConflicts refuses to create a strategy selecting pods already selected by another strategy
+	RejectConflicts bool `mapstructure:"reject_conflicts"`
+}
+
+// DecisionMakerDiscoveryConfig controls how the manager finds the decision maker running on every node
+type DecisionMakerDiscoveryConfig struct {
+	// Namespace the decision makers run in, every namespace is searched when empty
+	Namespace string `mapstructure:"namespace"`
+	// LabelSelector selects the decision maker pods, e.g. "app=decisionmaker,tier=node"
+	LabelSelector string `mapstructure:"label_selector"`
+	// PortName is the named container port of the decision maker API, the first port is taken when empty
+	PortName string `mapstructure:"port_name"`
+	// UseEndpointSlices resolves the decision makers through the EndpointSlices of ServiceName instead of their pods
+	UseEndpointSlices bool   `mapstructure:"use_endpoint_slices"`
+	ServiceName       string `mapstructure:"service_name"`
+}
+
 var (
 	managerCfg *ManageConfig
 )
@@ -19,6 +19,24 @@ func ConfigModule(cfg config.DecisionMakerConfig) (fx.Option, error) {
 		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.TokenConfig {
 			return dmCfg.Token
 		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.StoreConfig {
+			return dmCfg.Store
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.ResolverConfig {
+			return dmCfg.Resolver
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.UnixSocketConfig {
+			return dmCfg.UnixSocket
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.MetricsConfig {
+			return dmCfg.Metrics
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.WatchdogConfig {
+			return dmCfg.Watchdog
+		}),
+		fx.Provide(func(dmCfg config.DecisionMakerConfig) config.SchedExtConfig {
+			return dmCfg.SchedExt
+		}),
 	), nil
 }
 
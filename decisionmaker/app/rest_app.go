@@ -5,6 +5,7 @@ import (
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/decisionmaker/rest"
+	"github.com/Gthulhu/api/decisionmaker/service"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
@@ -27,15 +28,38 @@ func NewRestApp(configName string, configDirPath string) (*fx.App, error) {
 
 	app := fx.New(
 		handlerModule,
+		fx.Invoke(RestoreIntentSet),
+		fx.Invoke(StartIntentResolver),
+		fx.Invoke(StartSchedulerWatchdog),
 		fx.Invoke(StartRestApp),
 	)
 	return app, nil
 }
 
-func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, handler *rest.Handler) error {
+// RestoreIntentSet reloads the persisted intent set before the server starts accepting requests
+func RestoreIntentSet(lc fx.Lifecycle, svc service.Service) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			err := svc.RestoreIntentSet(ctx)
+			if err != nil {
+				logger.Logger(ctx).Error().Err(err).Msg("failed to restore intent set")
+			}
+			return nil
+		},
+	})
+}
+
+func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, socketCfg config.UnixSocketConfig, handler *rest.Handler) error {
 	engine := echo.New()
 	handler.SetupRoutes(engine)
 
+	if socketCfg.Enabled {
+		err := startUnixSocketServer(lc, socketCfg, handler)
+		if err != nil {
+			return err
+		}
+	}
+
 	// TODO: setup middleware, logging, etc.
 
 	lc.Append(fx.Hook{
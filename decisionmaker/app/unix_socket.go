@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/rest"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+)
+
+const defaultUnixSocketMode = 0o660
+
+// startUnixSocketServer serves the scheduler facing routes on a Unix socket for the lifetime of the app
+func startUnixSocketServer(lc fx.Lifecycle, cfg config.UnixSocketConfig, handler *rest.Handler) error {
+	if cfg.Path == "" {
+		return errors.New("unix socket path is required")
+	}
+	mode := os.FileMode(defaultUnixSocketMode)
+	if cfg.Mode != "" {
+		m, err := strconv.ParseUint(cfg.Mode, 8, 32)
+		if err != nil {
+			return fmt.Errorf("parse unix socket mode %q: %w", cfg.Mode, err)
+		}
+		mode = os.FileMode(m)
+	}
+
+	engine := echo.New()
+	handler.SetupUnixSocketRoutes(engine)
+	server := &http.Server{
+		Handler:     engine,
+		ConnContext: rest.PeerCredConnContext,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := listenUnixSocket(cfg.Path, mode)
+			if err != nil {
+				return err
+			}
+			go func() {
+				logger.Logger(ctx).Info().Msgf("starting dm server on unix socket %s", cfg.Path)
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Logger(ctx).Fatal().Err(err).Msgf("serve unix socket %s fail", cfg.Path)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Logger(ctx).Info().Msg("shutting down dm unix socket server")
+			err := server.Shutdown(ctx)
+			_ = os.Remove(cfg.Path)
+			return err
+		},
+	})
+	return nil
+}
+
+// listenUnixSocket replaces a socket left behind by a previous run and restricts access with mode
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create unix socket directory: %w", err)
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a unix socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale unix socket: %w", err)
+		}
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on unix socket %s: %w", path, err)
+	}
+	err = os.Chmod(path, mode)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("chmod unix socket %s: %w", path, err)
+	}
+	return listener, nil
+}
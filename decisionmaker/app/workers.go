@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/service"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+// StartIntentResolver keeps the PIDs covered by the intents up to date for the lifetime of the app
+func StartIntentResolver(lc fx.Lifecycle, cfg config.ResolverConfig, svc service.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "intent resolver", svc.RunIntentResolver)
+	return nil
+}
+
+// StartSchedulerWatchdog watches the metric pushes of the scheduler for the lifetime of the app
+func StartSchedulerWatchdog(lc fx.Lifecycle, cfg config.WatchdogConfig, svc service.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "scheduler watchdog", svc.RunSchedulerWatchdog)
+	return nil
+}
+
+// runWorker starts run in the background when the app starts and cancels it when the app stops
+func runWorker(lc fx.Lifecycle, name string, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil {
+					logger.Logger(ctx).Error().Err(err).Msgf("%s stopped", name)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			logger.Logger(stopCtx).Info().Msgf("shutting down %s", name)
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
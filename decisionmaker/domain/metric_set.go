@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type MetricSet struct {
 	UserSchedLastRunAt uint64
 	NrQueued           uint64
@@ -12,4 +14,40 @@ type MetricSet struct {
 	NrBounceDispatches uint64
 	NrFailedDispatches uint64
 	NrSchedCongested   uint64
+	Tasks              []TaskMetric
+}
+
+// TaskMetric holds the scheduler statistics of a single task
+type TaskMetric struct {
+	PID          int
+	RuntimeNs    uint64 // CPU time consumed since the task was first seen by the scheduler
+	WaitTimeNs   uint64 // time spent runnable but waiting to be dispatched
+	NrDispatches uint64
+	Vtime        uint64
+}
+
+// TaskMetricOwner is the pod a task belongs to, with the strategy whose intent covers it if any
+type TaskMetricOwner struct {
+	PodUID       string
+	K8sNamespace string
+	PodName      string
+	StrategyID   string
+}
+
+// MetricSample is the node metrics at one point in time. Counters include the values of previous
+// scheduler runs, gauges and rates are averages when the sample was downsampled.
+type MetricSample struct {
+	Timestamp           time.Time
+	NrQueued            float64
+	NrScheduled         float64
+	NrRunning           float64
+	NrOnlineCPUs        float64
+	NrUserDispatches    uint64
+	NrKernelDispatches  uint64
+	NrCancelDispatches  uint64
+	NrBounceDispatches  uint64
+	NrFailedDispatches  uint64
+	NrSchedCongested    uint64
+	DispatchRate        float64
+	FailedDispatchRatio float64
 }
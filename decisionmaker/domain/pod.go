@@ -4,6 +4,7 @@ package domain
 type PodProcess struct {
 	PID         int    `json:"pid"`
 	Command     string `json:"command"`
+	Cmdline     string `json:"cmdline,omitempty"` // arguments of /proc/<pid>/cmdline joined by spaces
 	PPID        int    `json:"ppid,omitempty"`
 	ContainerID string `json:"container_id,omitempty"`
 }
@@ -15,7 +16,28 @@ type PodInfo struct {
 	Processes []PodProcess `json:"processes"`
 }
 
+// PodProcessFilter narrows down discovered pods, empty fields match everything
+type PodProcessFilter struct {
+	PodUIDs      []string
+	ContainerIDs []string // full IDs or prefixes, such as the 12 character IDs shown by container runtimes
+}
+
+// PodProcesses describes a pod discovered on this node and the intent declared for it
+type PodProcesses struct {
+	PodUID    string
+	Intent    *Intent // nil when no intent targets the pod
+	Processes []ProcessIntents
+}
+
+// ProcessIntents is a process of a pod with the scheduling intents currently applied to its PID
+type ProcessIntents struct {
+	PodProcess
+	SchedulingIntents []*SchedulingIntents
+}
+
 type Intent struct {
+	ID            string            `json:"id,omitempty"`
+	StrategyID    string            `json:"strategyID,omitempty"`
 	PodName       string            `json:"podName,omitempty"`
 	PodID         string            `json:"podID,omitempty"`
 	NodeID        string            `json:"nodeID,omitempty"`
@@ -26,6 +48,58 @@ type Intent struct {
 	PodLabels     map[string]string `json:"podLabels,omitempty"`
 }
 
+// IntentSet is the complete set of intents declared for a node together with the generation it was synced at
+type IntentSet struct {
+	Generation int64     `json:"generation"`
+	Intents    []*Intent `json:"intents"`
+}
+
+type IntentResultStatus string
+
+const (
+	IntentResultApplied     IntentResultStatus = "applied"
+	IntentResultFailed      IntentResultStatus = "failed"
+	IntentResultPodNotFound IntentResultStatus = "pod_not_found"
+)
+
+// IntentResult reports how an intent was applied on this node
+type IntentResult struct {
+	IntentID     string             `json:"intentID,omitempty"`
+	PodID        string             `json:"podID"`
+	Status       IntentResultStatus `json:"status"`
+	PIDCount     int                `json:"pidCount"`     // processes matching the intent, which it was applied to
+	ProcessCount int                `json:"processCount"` // schedulable processes of the pod, matching or not
+	Reason       string             `json:"reason,omitempty"`
+}
+
+type SchedulingIntentEventType string
+
+const (
+	SchedulingIntentAdded    SchedulingIntentEventType = "ADDED"
+	SchedulingIntentModified SchedulingIntentEventType = "MODIFIED"
+	SchedulingIntentDeleted  SchedulingIntentEventType = "DELETED"
+	// SchedulingIntentBookmark carries no intent, it marks the end of the initial events of a watch
+	SchedulingIntentBookmark SchedulingIntentEventType = "BOOKMARK"
+)
+
+// SchedulingIntentEvent describes a change of the scheduling intent stored for a process.
+// ResourceVersion increases with every change, a watch resumed from it receives all later events.
+type SchedulingIntentEvent struct {
+	Type            SchedulingIntentEventType
+	ResourceVersion int64
+	Key             string
+	Intent          *SchedulingIntents
+}
+
+// SchedulingIntentsDelta holds the scheduling intents changed after a resource version.
+// Delta is false when Changed is the complete list, e.g. when the version is no longer retained.
+type SchedulingIntentsDelta struct {
+	ResourceVersion int64
+	Delta           bool
+	Changed         []*SchedulingIntents
+	RemovedPIDs     []int
+}
+
 type SchedulingIntents struct {
 	Priority      bool            `json:"priority"`                // If true, set vtime to minimum vtime
 	ExecutionTime uint64          `json:"execution_time"`          // Time slice for this process in nanoseconds
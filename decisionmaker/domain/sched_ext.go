@@ -0,0 +1,29 @@
+package domain
+
+// SchedExtState is the content of /sys/kernel/sched_ext/state
+type SchedExtState string
+
+const (
+	SchedExtStateEnabled   SchedExtState = "enabled"
+	SchedExtStateEnabling  SchedExtState = "enabling"
+	SchedExtStateDisabled  SchedExtState = "disabled"
+	SchedExtStateDisabling SchedExtState = "disabling"
+	// SchedExtStateUnsupported is reported when the kernel does not expose sched_ext
+	SchedExtStateUnsupported SchedExtState = "unsupported"
+)
+
+// SchedExtStatus describes the sched_ext state of the node as exposed in sysfs.
+// Fields that the running kernel does not expose are left empty.
+type SchedExtStatus struct {
+	State SchedExtState
+	// Ops is the name of the loaded BPF scheduler, empty when none is loaded
+	Ops string
+	// EnableSeq counts how many times a BPF scheduler was enabled since boot
+	EnableSeq uint64
+	// SwitchAll is true when every task is switched to sched_ext, not only SCHED_EXT tasks
+	SwitchAll bool
+	// NrRejected counts tasks whose switch to sched_ext was rejected
+	NrRejected uint64
+	// Events holds the event counters of the root scheduler, e.g. SCX_EV_SELECT_CPU_FALLBACK
+	Events map[string]uint64
+}
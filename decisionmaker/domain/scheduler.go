@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// SchedulerState is the state of the sched_ext scheduler of the node as seen by the watchdog
+type SchedulerState string
+
+const (
+	// SchedulerStateUnknown is reported until the scheduler pushed metrics once or the watchdog is disabled
+	SchedulerStateUnknown SchedulerState = "unknown"
+	SchedulerStateRunning SchedulerState = "running"
+	// SchedulerStateStalled means metrics stopped arriving or the scheduler stopped running,
+	// the kernel has most likely fallen back to the default scheduling class
+	SchedulerStateStalled SchedulerState = "stalled"
+)
+
+// SchedulerHealth is the verdict of the watchdog on the scheduler of the node
+type SchedulerHealth struct {
+	State              SchedulerState
+	Reason             string
+	LastMetricsAt      time.Time
+	UserSchedLastRunAt uint64
+	LastRunAdvancedAt  time.Time
+}
+
+type SchedulerEventType string
+
+const (
+	SchedulerEventStalled   SchedulerEventType = "stalled"
+	SchedulerEventRecovered SchedulerEventType = "recovered"
+)
+
+// SchedulerEvent records a transition of the scheduler state
+type SchedulerEvent struct {
+	Type      SchedulerEventType
+	Reason    string
+	Timestamp time.Time
+}
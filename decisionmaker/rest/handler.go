@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
 	"github.com/Gthulhu/api/decisionmaker/service"
 	"github.com/Gthulhu/api/manager/errs"
 	"github.com/Gthulhu/api/pkg/logger"
@@ -34,9 +35,11 @@ type VersionResponse struct {
 
 // HealthResponse describes the health check payload.
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
-	Service   string `json:"service"`
+	Status    string           `json:"status"`
+	Timestamp string           `json:"timestamp"`
+	Service   string           `json:"service"`
+	Scheduler *SchedulerHealth `json:"scheduler,omitempty"`
+	SchedExt  *SchedExtStatus  `json:"sched_ext,omitempty"`
 }
 
 func NewSuccessResponse[T any](data *T) SuccessResponse[T] {
@@ -56,20 +59,23 @@ type SuccessResponse[T any] struct {
 
 type Params struct {
 	fx.In
-	Service     service.Service
-	TokenConfig config.TokenConfig
+	Service          service.Service
+	TokenConfig      config.TokenConfig
+	UnixSocketConfig config.UnixSocketConfig
 }
 
 func NewHandler(params Params) (*Handler, error) {
 	return &Handler{
-		Service:     params.Service,
-		TokenConfig: params.TokenConfig,
+		Service:          params.Service,
+		TokenConfig:      params.TokenConfig,
+		UnixSocketConfig: params.UnixSocketConfig,
 	}, nil
 }
 
 type Handler struct {
-	Service     service.Service
-	TokenConfig config.TokenConfig
+	Service          service.Service
+	TokenConfig      config.TokenConfig
+	UnixSocketConfig config.UnixSocketConfig
 }
 
 func (h *Handler) JSONResponse(ctx context.Context, w http.ResponseWriter, status int, data any) {
@@ -126,23 +132,36 @@ func (h *Handler) Version(w http.ResponseWriter, r *http.Request) {
 	response := VersionResponse{
 		Message:   "BSS Metrics API Server",
 		Version:   "1.0.0",
-		Endpoints: "/health, /version, POST_/api/v1/intents, GET_/api/v1/scheduling/strategies",
+		Endpoints: "/health, /ready, /version, POST_/api/v1/intents, DELETE_/api/v1/intents, PUT_/api/v1/intents, GET_/api/v1/intents, GET_/api/v1/scheduling/strategies, GET_/api/v1/scheduling/strategies/watch, GET_/api/v1/pods/pids, GET_/api/v1/metrics/history, GET_/api/v1/scheduler/status, GET_/api/v1/scheduler/events",
 	}
 	h.JSONResponse(r.Context(), w, http.StatusOK, response)
 }
 
 // HealthCheck godoc
 // @Summary Health check
-// @Description Basic health check for readiness probes.
+// @Description Liveness check, reports "degraded" without failing while the scheduler of the node is stalled or no BPF scheduler is loaded.
 // @Tags System
 // @Produce json
 // @Success 200 {object} HealthResponse
 // @Router /health [get]
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	health := h.Service.SchedulerHealth(r.Context())
 	response := HealthResponse{
 		Status:    "healthy",
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Service:   "BSS Metrics API Server",
+		Scheduler: convertDomainSchedulerHealth(health),
+	}
+	schedExt, err := h.Service.SchedExtStatus(r.Context())
+	if err != nil {
+		logger.Logger(r.Context()).Warn().Err(err).Msg("failed to read sched_ext status")
+	} else {
+		response.SchedExt = convertDomainSchedExtStatus(schedExt)
+	}
+	// restarting the decision maker brings neither the scheduler nor sched_ext back, so liveness keeps passing
+	if health.State == domain.SchedulerStateStalled ||
+		schedExt != nil && (schedExt.State == domain.SchedExtStateDisabled || schedExt.State == domain.SchedExtStateUnsupported) {
+		response.Status = "degraded"
 	}
 	h.JSONResponse(r.Context(), w, http.StatusOK, response)
 }
@@ -154,6 +173,7 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) error {
 	}
 
 	engine.GET("/health", h.echoHandler(h.HealthCheck))
+	engine.GET("/ready", h.echoHandler(h.Readiness))
 	engine.GET("/version", h.echoHandler(h.Version))
 
 	api := engine.Group("/api", echo.WrapMiddleware(middleware.LoggerMiddleware))
@@ -162,8 +182,16 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) error {
 		apiV1 := api.Group("/v1")
 		// auth routes
 		apiV1.POST("/intents", h.echoHandler(h.HandleIntents), echo.WrapMiddleware(authMiddleware))
+		apiV1.DELETE("/intents", h.echoHandler(h.DeleteIntents), echo.WrapMiddleware(authMiddleware))
+		apiV1.PUT("/intents", h.echoHandler(h.SyncIntents), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/intents", h.echoHandler(h.GetIntentSet), echo.WrapMiddleware(authMiddleware))
 		apiV1.GET("/scheduling/strategies", h.echoHandler(h.ListIntents), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/scheduling/strategies/watch", h.echoHandler(h.WatchIntents), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/pods/pids", h.echoHandler(h.ListPodPIDs), echo.WrapMiddleware(authMiddleware))
 		apiV1.POST("/metrics", h.echoHandler(h.UpdateMetrics), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/metrics/history", h.echoHandler(h.GetMetricsHistory), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/scheduler/status", h.echoHandler(h.GetSchedulerStatus), echo.WrapMiddleware(authMiddleware))
+		apiV1.GET("/scheduler/events", h.echoHandler(h.ListSchedulerEvents), echo.WrapMiddleware(authMiddleware))
 		// token routes
 		apiV1.POST("/auth/token", h.echoHandler(h.GenTokenHandler))
 	}
@@ -175,6 +203,23 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) error {
 	return nil
 }
 
+// SetupUnixSocketRoutes registers the routes used by the local scheduler on the engine serving the Unix socket,
+// peers are authenticated by their SO_PEERCRED credentials instead of a JWT
+func (h *Handler) SetupUnixSocketRoutes(engine *echo.Echo) {
+	peerCredMiddleware := GetPeerCredMiddleware(h.UnixSocketConfig)
+
+	engine.GET("/health", h.echoHandler(h.HealthCheck))
+
+	api := engine.Group("/api", echo.WrapMiddleware(middleware.LoggerMiddleware), echo.WrapMiddleware(peerCredMiddleware))
+	// v1 routes
+	{
+		apiV1 := api.Group("/v1")
+		apiV1.GET("/scheduling/strategies", h.echoHandler(h.ListIntents))
+		apiV1.GET("/scheduling/strategies/watch", h.echoHandler(h.WatchIntents))
+		apiV1.POST("/metrics", h.echoHandler(h.UpdateMetrics))
+	}
+}
+
 func (h *Handler) echoHandler(handlerFunc func(w http.ResponseWriter, r *http.Request)) echo.HandlerFunc {
 	return echo.WrapHandler(http.HandlerFunc(handlerFunc))
 }
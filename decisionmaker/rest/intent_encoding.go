@@ -0,0 +1,177 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// Binary encoding of the scheduling intents, negotiated with
+//
+//	Accept: application/vnd.gthulhu.scheduling-intents+binary; version=2
+//
+// Version 2 is a 24 byte header followed by one fixed 16 byte record per PID, all little endian.
+// Version 1 had a 16 byte header without the resource version and is no longer served.
+// The record mirrors the per-PID entry the scheduler stores in its BPF map, label selectors and
+// command regexes are only needed for matching and are left out.
+//
+//	header: magic "GTSI" [4]byte | version uint16 | record size uint16 | record count uint32 | flags uint32 |
+//	        resource version int64
+//	record: pid int32 | flags uint32 | execution time (ns) uint64
+//
+// A payload with SchedulingIntentsFlagDelta only holds the PIDs changed since the requested version,
+// removed PIDs are records with SchedulingIntentFlagRemoved. The resource version in the header is the
+// one to send as since= on the next request, the same value the ETag carries.
+const (
+	SchedulingIntentsBinaryMediaType = "application/vnd.gthulhu.scheduling-intents+binary"
+	SchedulingIntentsBinaryVersion   = 2
+
+	schedulingIntentsBinaryMagic      = "GTSI"
+	schedulingIntentsBinaryHeaderSize = 24
+	schedulingIntentsBinaryRecordSize = 16
+)
+
+// SchedulingIntentsFlagDelta marks a payload that only holds the changes since the requested version
+const SchedulingIntentsFlagDelta uint32 = 1 << 0
+
+const (
+	// SchedulingIntentFlagPriority marks a record whose process gets the minimum vtime
+	SchedulingIntentFlagPriority uint32 = 1 << 0
+	// SchedulingIntentFlagRemoved marks a record whose PID no longer has a scheduling intent
+	SchedulingIntentFlagRemoved uint32 = 1 << 1
+)
+
+// SchedulingIntentsBinary is a decoded payload of the binary encoding
+type SchedulingIntentsBinary struct {
+	Flags uint32
+	// ResourceVersion is the version of the payload, pass it as since= to receive only later changes
+	ResourceVersion int64
+	Records         []SchedulingIntentRecord
+}
+
+// SchedulingIntentRecord is a decoded record of the binary encoding
+type SchedulingIntentRecord struct {
+	PID           int32
+	Flags         uint32
+	ExecutionTime uint64
+}
+
+// EncodeSchedulingIntentsBinary encodes the scheduling intents in the current version of the binary encoding
+func EncodeSchedulingIntentsBinary(intents *domain.SchedulingIntentsDelta) []byte {
+	count := len(intents.Changed) + len(intents.RemovedPIDs)
+	buf := make([]byte, schedulingIntentsBinaryHeaderSize, schedulingIntentsBinaryHeaderSize+count*schedulingIntentsBinaryRecordSize)
+	copy(buf, schedulingIntentsBinaryMagic)
+	binary.LittleEndian.PutUint16(buf[4:], SchedulingIntentsBinaryVersion)
+	binary.LittleEndian.PutUint16(buf[6:], schedulingIntentsBinaryRecordSize)
+	binary.LittleEndian.PutUint32(buf[8:], uint32(count))
+	if intents.Delta {
+		binary.LittleEndian.PutUint32(buf[12:], SchedulingIntentsFlagDelta)
+	}
+	binary.LittleEndian.PutUint64(buf[16:], uint64(intents.ResourceVersion))
+
+	for _, intent := range intents.Changed {
+		var flags uint32
+		if intent.Priority {
+			flags |= SchedulingIntentFlagPriority
+		}
+		buf = appendSchedulingIntentRecord(buf, intent.PID, flags, intent.ExecutionTime)
+	}
+	for _, pid := range intents.RemovedPIDs {
+		buf = appendSchedulingIntentRecord(buf, pid, SchedulingIntentFlagRemoved, 0)
+	}
+	return buf
+}
+
+func appendSchedulingIntentRecord(buf []byte, pid int, flags uint32, executionTime uint64) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(int32(pid)))
+	buf = binary.LittleEndian.AppendUint32(buf, flags)
+	return binary.LittleEndian.AppendUint64(buf, executionTime)
+}
+
+// DecodeSchedulingIntentsBinary decodes the binary encoding. Records larger than the current layout
+// are accepted so that fields appended by a later version can be skipped by older readers.
+func DecodeSchedulingIntentsBinary(data []byte) (*SchedulingIntentsBinary, error) {
+	if len(data) < schedulingIntentsBinaryHeaderSize {
+		return nil, errors.New("scheduling intents payload is shorter than its header")
+	}
+	if !bytes.Equal(data[:4], []byte(schedulingIntentsBinaryMagic)) {
+		return nil, errors.New("scheduling intents payload has an invalid magic")
+	}
+	version := binary.LittleEndian.Uint16(data[4:])
+	if version != SchedulingIntentsBinaryVersion {
+		return nil, fmt.Errorf("unsupported scheduling intents encoding version %d", version)
+	}
+	recordSize := int(binary.LittleEndian.Uint16(data[6:]))
+	if recordSize < schedulingIntentsBinaryRecordSize {
+		return nil, fmt.Errorf("scheduling intents record size %d is too small", recordSize)
+	}
+	count := int(binary.LittleEndian.Uint32(data[8:]))
+	body := data[schedulingIntentsBinaryHeaderSize:]
+	if len(body) != count*recordSize {
+		return nil, fmt.Errorf("scheduling intents payload holds %d bytes, expected %d records of %d bytes", len(body), count, recordSize)
+	}
+
+	payload := &SchedulingIntentsBinary{
+		Flags:           binary.LittleEndian.Uint32(data[12:]),
+		ResourceVersion: int64(binary.LittleEndian.Uint64(data[16:])),
+		Records:         make([]SchedulingIntentRecord, 0, count),
+	}
+	for offset := 0; offset < len(body); offset += recordSize {
+		record := body[offset : offset+recordSize]
+		payload.Records = append(payload.Records, SchedulingIntentRecord{
+			PID:           int32(binary.LittleEndian.Uint32(record[0:])),
+			Flags:         binary.LittleEndian.Uint32(record[4:]),
+			ExecutionTime: binary.LittleEndian.Uint64(record[8:]),
+		})
+	}
+	return payload, nil
+}
+
+// acceptsSchedulingIntentsBinary reports whether the Accept header prefers a supported version of the
+// binary encoding over JSON. JSON stays the default for a missing header and ties with application/json,
+// the explicit binary media type wins ties with wildcards.
+func acceptsSchedulingIntentsBinary(accept string) bool {
+	binaryQuality, jsonQuality, wildcardQuality := -1.0, -1.0, -1.0
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case SchedulingIntentsBinaryMediaType:
+			if version, ok := params["version"]; ok && version != strconv.Itoa(SchedulingIntentsBinaryVersion) {
+				continue
+			}
+			binaryQuality = max(binaryQuality, quality)
+		case "application/json":
+			jsonQuality = max(jsonQuality, quality)
+		case "application/*", "*/*":
+			wildcardQuality = max(wildcardQuality, quality)
+		}
+	}
+	return binaryQuality > 0 && binaryQuality > jsonQuality && binaryQuality >= wildcardQuality
+}
+
+func (h *Handler) schedulingIntentsBinaryResponse(ctx context.Context, w http.ResponseWriter, intents *domain.SchedulingIntentsDelta) {
+	w.Header().Set("Content-Type", fmt.Sprintf("%s; version=%d", SchedulingIntentsBinaryMediaType, SchedulingIntentsBinaryVersion))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(EncodeSchedulingIntentsBinary(intents)); err != nil {
+		logger.Logger(ctx).Error().Err(err).Msg("Failed to write binary scheduling intents response")
+	}
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchedulingIntentsBinaryEncoding tests that the binary encoding round trips the per-PID fields
+func TestSchedulingIntentsBinaryEncoding(t *testing.T) {
+	intents := &domain.SchedulingIntentsDelta{
+		ResourceVersion: 42,
+		Changed: []*domain.SchedulingIntents{
+			{PID: 1234, Priority: true, ExecutionTime: 20000000, Selectors: []domain.LabelSelector{{Key: "app", Value: "nginx"}}, CommandRegex: "nginx"},
+			{PID: 5678, ExecutionTime: 5000000},
+		},
+	}
+	data := EncodeSchedulingIntentsBinary(intents)
+	assert.Len(t, data, schedulingIntentsBinaryHeaderSize+2*schedulingIntentsBinaryRecordSize, "unexpected payload size")
+
+	payload, err := DecodeSchedulingIntentsBinary(data)
+	require.NoError(t, err, "decode should not return error")
+	assert.Zero(t, payload.Flags, "a full list should not be flagged as delta")
+	assert.Equal(t, int64(42), payload.ResourceVersion, "the header should carry the resource version")
+	assert.Equal(t, []SchedulingIntentRecord{
+		{PID: 1234, Flags: SchedulingIntentFlagPriority, ExecutionTime: 20000000},
+		{PID: 5678, ExecutionTime: 5000000},
+	}, payload.Records)
+
+	delta, err := DecodeSchedulingIntentsBinary(EncodeSchedulingIntentsBinary(&domain.SchedulingIntentsDelta{
+		ResourceVersion: 43,
+		Delta:           true,
+		Changed:         []*domain.SchedulingIntents{{PID: 5678, ExecutionTime: 5000000}},
+		RemovedPIDs:     []int{1234},
+	}))
+	require.NoError(t, err, "decode of a delta should not return error")
+	assert.Equal(t, SchedulingIntentsFlagDelta, delta.Flags, "a delta should be flagged")
+	assert.Equal(t, int64(43), delta.ResourceVersion, "the header should carry the resource version")
+	assert.Equal(t, []SchedulingIntentRecord{
+		{PID: 5678, ExecutionTime: 5000000},
+		{PID: 1234, Flags: SchedulingIntentFlagRemoved},
+	}, delta.Records)
+
+	empty, err := DecodeSchedulingIntentsBinary(EncodeSchedulingIntentsBinary(&domain.SchedulingIntentsDelta{}))
+	require.NoError(t, err, "decode of an empty list should not return error")
+	assert.Empty(t, empty.Records)
+
+	_, err = DecodeSchedulingIntentsBinary(data[:len(data)-1])
+	assert.Error(t, err, "truncated payload should be rejected")
+	corrupted := append([]byte{}, data...)
+	corrupted[4] = 1
+	_, err = DecodeSchedulingIntentsBinary(corrupted)
+	assert.Error(t, err, "unknown version should be rejected")
+}
+
+// TestAcceptsSchedulingIntentsBinary tests the content negotiation of the scheduling intents
+func TestAcceptsSchedulingIntentsBinary(t *testing.T) {
+	testCases := []struct {
+		accept   string
+		expected bool
+	}{
+		{accept: "", expected: false},
+		{accept: "*/*", expected: false},
+		{accept: "application/json", expected: false},
+		{accept: SchedulingIntentsBinaryMediaType, expected: true},
+		{accept: SchedulingIntentsBinaryMediaType + "; version=2", expected: true},
+		{accept: SchedulingIntentsBinaryMediaType + "; version=1", expected: false},
+		{accept: SchedulingIntentsBinaryMediaType + ", */*;q=0.5", expected: true},
+		{accept: SchedulingIntentsBinaryMediaType + ", application/json", expected: false},
+		{accept: SchedulingIntentsBinaryMediaType + ";q=0.9, application/json;q=0.5", expected: true},
+		{accept: SchedulingIntentsBinaryMediaType + ";q=0", expected: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, acceptsSchedulingIntentsBinary(tc.accept), "unexpected negotiation for %q", tc.accept)
+	}
+}
+
+// TestSchedulingIntentsETag tests that the entity tags of the two representations never match each other
+func TestSchedulingIntentsETag(t *testing.T) {
+	jsonTag := schedulingIntentsETag(42, false)
+	binaryTag := schedulingIntentsETag(42, true)
+	assert.Equal(t, `W/"42"`, jsonTag)
+	assert.Equal(t, `W/"42-bin"`, binaryTag)
+
+	assert.True(t, etagMatches(jsonTag, jsonTag), "json tag should match itself")
+	assert.True(t, etagMatches(`"42-bin"`, binaryTag), "weak comparison should ignore the W/ prefix")
+	assert.False(t, etagMatches(jsonTag, binaryTag), "json tag should not revalidate the binary representation")
+	assert.False(t, etagMatches(binaryTag, jsonTag), "binary tag should not revalidate the json representation")
+	assert.True(t, etagMatches(`W/"41", `+binaryTag, binaryTag), "any tag of the list may match")
+}
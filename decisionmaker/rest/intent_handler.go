@@ -1,10 +1,14 @@
 package rest
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/manager/errs"
 )
 
 type HandleIntentsRequest struct {
@@ -12,6 +16,8 @@ type HandleIntentsRequest struct {
 }
 
 type Intent struct {
+	ID            string            `json:"id,omitempty"`
+	StrategyID    string            `json:"strategyID,omitempty"`
 	PodName       string            `json:"podName,omitempty"`
 	PodID         string            `json:"podID,omitempty"`
 	NodeID        string            `json:"nodeID,omitempty"`
@@ -22,6 +28,21 @@ type Intent struct {
 	PodLabels     map[string]string `json:"podLabels,omitempty"`
 }
 
+type HandleIntentsResponse struct {
+	Results []IntentResult `json:"results"`
+}
+
+// IntentResult reports whether an intent was applied, with the number of PIDs matching its command regex
+// out of the schedulable processes of the pod, or why it failed ("failed" or "pod_not_found")
+type IntentResult struct {
+	IntentID     string `json:"intentID,omitempty"`
+	PodID        string `json:"podID"`
+	Status       string `json:"status"`
+	PIDCount     int    `json:"pidCount"`
+	ProcessCount int    `json:"processCount"`
+	Reason       string `json:"reason,omitempty"`
+}
+
 func (h *Handler) HandleIntents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	var req HandleIntentsRequest
@@ -30,9 +51,86 @@ func (h *Handler) HandleIntents(w http.ResponseWriter, r *http.Request) {
 		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
-	intents := make([]*domain.Intent, 0, len(req.Intents))
-	for _, intent := range req.Intents {
+	results, err := h.Service.ProcessIntents(r.Context(), convertIntentsToDomain(req.Intents))
+	if err != nil {
+		if _, ok := errs.IsHTTPStatusError(err); ok {
+			h.HandleError(ctx, w, err)
+			return
+		}
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to process intents", err)
+		return
+	}
+	resp := HandleIntentsResponse{
+		Results: convertDomainIntentResults(results),
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// SyncIntentsRequest carries the complete intent set of the node, generation must not be older than the current one
+type SyncIntentsRequest struct {
+	Generation int64    `json:"generation"`
+	Intents    []Intent `json:"intents"`
+}
+
+type SyncIntentsResponse struct {
+	Generation int64          `json:"generation"`
+	Results    []IntentResult `json:"results"`
+}
+
+// SyncIntents replaces every intent held by this node with the intents of the request
+func (h *Handler) SyncIntents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req SyncIntentsRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request payload", err)
+		return
+	}
+	if req.Generation < 0 {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Generation must not be negative", nil)
+		return
+	}
+	results, err := h.Service.SyncIntents(ctx, req.Generation, convertIntentsToDomain(req.Intents))
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	resp := SyncIntentsResponse{
+		Generation: req.Generation,
+		Results:    convertDomainIntentResults(results),
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+type GetIntentSetResponse struct {
+	Generation int64    `json:"generation"`
+	Intents    []Intent `json:"intents"`
+}
+
+// GetIntentSet returns the generation of the last full sync and the intents this node currently holds
+func (h *Handler) GetIntentSet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	set, err := h.Service.GetIntentSet(ctx)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	resp := GetIntentSetResponse{
+		Generation: set.Generation,
+		Intents:    make([]Intent, 0, len(set.Intents)),
+	}
+	for _, intent := range set.Intents {
+		resp.Intents = append(resp.Intents, convertDomainIntent(intent))
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+func convertIntentsToDomain(reqIntents []Intent) []*domain.Intent {
+	intents := make([]*domain.Intent, 0, len(reqIntents))
+	for _, intent := range reqIntents {
 		intents = append(intents, &domain.Intent{
+			ID:            intent.ID,
+			StrategyID:    intent.StrategyID,
 			PodName:       intent.PodName,
 			PodID:         intent.PodID,
 			NodeID:        intent.NodeID,
@@ -43,12 +141,61 @@ func (h *Handler) HandleIntents(w http.ResponseWriter, r *http.Request) {
 			PodLabels:     intent.PodLabels,
 		})
 	}
-	err = h.Service.ProcessIntents(r.Context(), intents)
+	return intents
+}
+
+func convertDomainIntent(intent *domain.Intent) Intent {
+	return Intent{
+		ID:            intent.ID,
+		StrategyID:    intent.StrategyID,
+		PodName:       intent.PodName,
+		PodID:         intent.PodID,
+		NodeID:        intent.NodeID,
+		K8sNamespace:  intent.K8sNamespace,
+		CommandRegex:  intent.CommandRegex,
+		Priority:      intent.Priority,
+		ExecutionTime: intent.ExecutionTime,
+		PodLabels:     intent.PodLabels,
+	}
+}
+
+func convertDomainIntentResults(results []*domain.IntentResult) []IntentResult {
+	respResults := make([]IntentResult, 0, len(results))
+	for _, result := range results {
+		respResults = append(respResults, IntentResult{
+			IntentID:     result.IntentID,
+			PodID:        result.PodID,
+			Status:       string(result.Status),
+			PIDCount:     result.PIDCount,
+			ProcessCount: result.ProcessCount,
+			Reason:       result.Reason,
+		})
+	}
+	return respResults
+}
+
+type DeleteIntentsRequest struct {
+	PodIDs []string `json:"podIDs"`
+}
+
+type DeleteIntentsResponse struct {
+	Removed int `json:"removed"`
+}
+
+func (h *Handler) DeleteIntents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req DeleteIntentsRequest
+	err := h.JSONBind(r, &req)
 	if err != nil {
-		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to process intents", err)
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request payload", err)
 		return
 	}
-	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse[EmptyResponse](nil))
+	removed, err := h.Service.RemoveIntents(ctx, req.PodIDs)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to delete intents", err)
+		return
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&DeleteIntentsResponse{Removed: removed}))
 }
 
 // SchedulingStrategy represents a strategy for process scheduling
@@ -66,41 +213,102 @@ type LabelSelector struct {
 	Value string `json:"value"` // Label value
 }
 
+// ListIntentsResponse holds every scheduling intent, or with delta set only the intents changed
+// since the requested version and the PIDs whose intent was removed
 type ListIntentsResponse struct {
-	Success    bool                 `json:"success"`
-	Message    string               `json:"message"`
-	Timestamp  string               `json:"timestamp"`
-	Scheduling []*SchedulingIntents `json:"scheduling"`
+	Success         bool                 `json:"success"`
+	Message         string               `json:"message"`
+	Timestamp       string               `json:"timestamp"`
+	ResourceVersion int64                `json:"resource_version"`
+	Delta           bool                 `json:"delta,omitempty"`
+	Scheduling      []*SchedulingIntents `json:"scheduling"`
+	RemovedPIDs     []int                `json:"removed_pids,omitempty"`
 }
 
+// ListIntents returns the scheduling intents of every process as JSON, or in the compact binary
+// encoding when the Accept header asks for SchedulingIntentsBinaryMediaType.
+// With ?since=<resource version> only the changes after that version are listed, the ETag carries the
+// current resource version and the representation so an unchanged list is answered with 304 Not Modified.
 func (h *Handler) ListIntents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	intents, err := h.Service.ListAllSchedulingIntents(ctx)
+	var since int64
+	if value := r.URL.Query().Get("since"); value != "" {
+		var err error
+		since, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid since resource version", err)
+			return
+		}
+	}
+	intents, err := h.Service.ListSchedulingIntentsSince(ctx, since)
 	if err != nil {
 		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to list scheduling intents", err)
 		return
 	}
 
-	schedulingIntents := make([]*SchedulingIntents, 0, len(intents))
-	for _, intent := range intents {
-		schedulingIntents = append(schedulingIntents, &SchedulingIntents{
-			Priority:      intent.Priority,
-			ExecutionTime: intent.ExecutionTime,
-			PID:           intent.PID,
-			Selectors:     convertMapToLabelSelectors(intent.Selectors),
-			CommandRegex:  intent.CommandRegex,
-		})
+	binaryEncoding := acceptsSchedulingIntentsBinary(r.Header.Get("Accept"))
+	etag := schedulingIntentsETag(intents.ResourceVersion, binaryEncoding)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Accept")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if binaryEncoding {
+		h.schedulingIntentsBinaryResponse(ctx, w, intents)
+		return
+	}
+
+	schedulingIntents := make([]*SchedulingIntents, 0, len(intents.Changed))
+	for _, intent := range intents.Changed {
+		schedulingIntents = append(schedulingIntents, convertDomainSchedulingIntents(intent))
 	}
 
 	response := ListIntentsResponse{
-		Success:    true,
-		Message:    "Scheduling intents retrieved successfully",
-		Timestamp:  time.Now().UTC().Format(time.RFC3339), // You can set the current timestamp here if needed
-		Scheduling: schedulingIntents,
+		Success:         true,
+		Message:         "Scheduling intents retrieved successfully",
+		Timestamp:       time.Now().UTC().Format(time.RFC3339), // You can set the current timestamp here if needed
+		ResourceVersion: intents.ResourceVersion,
+		Delta:           intents.Delta,
+		Scheduling:      schedulingIntents,
+		RemovedPIDs:     intents.RemovedPIDs,
 	}
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
 
+// schedulingIntentsETag tags the scheduling intents of a resource version, the binary encoding gets a tag of its own
+// so that revalidating after switching the Accept header never matches a body the client did not receive
+func schedulingIntentsETag(resourceVersion int64, binaryEncoding bool) string {
+	if binaryEncoding {
+		return fmt.Sprintf(`W/"%d-bin"`, resourceVersion)
+	}
+	return fmt.Sprintf(`W/"%d"`, resourceVersion)
+}
+
+// etagMatches applies the weak comparison of If-None-Match to a list of entity tags
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func convertDomainSchedulingIntents(intent *domain.SchedulingIntents) *SchedulingIntents {
+	return &SchedulingIntents{
+		Priority:      intent.Priority,
+		ExecutionTime: intent.ExecutionTime,
+		PID:           intent.PID,
+		Selectors:     convertMapToLabelSelectors(intent.Selectors),
+		CommandRegex:  intent.CommandRegex,
+	}
+}
+
 func convertMapToLabelSelectors(selectorMap []domain.LabelSelector) []LabelSelector {
 	labelSelectors := make([]LabelSelector, 0, len(selectorMap))
 	for _, sel := range selectorMap {
@@ -2,23 +2,35 @@ package rest
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Gthulhu/api/decisionmaker/domain"
 )
 
 // UpdateMetricsRequest represents the payload for updating metrics.
 type UpdateMetricsRequest struct {
-	Usersched_last_run_at uint64 `json:"usersched_last_run_at"` // The PID of the userspace scheduler
-	Nr_queued             uint64 `json:"nr_queued"`             // Number of tasks queued in the userspace scheduler
-	Nr_scheduled          uint64 `json:"nr_scheduled"`          // Number of tasks scheduled by the userspace scheduler
-	Nr_running            uint64 `json:"nr_running"`            // Number of tasks currently running in the userspace scheduler
-	Nr_online_cpus        uint64 `json:"nr_online_cpus"`        // Number of online CPUs in the system
-	Nr_user_dispatches    uint64 `json:"nr_user_dispatches"`    // Number of user-space dispatches
-	Nr_kernel_dispatches  uint64 `json:"nr_kernel_dispatches"`  // Number of kernel-space dispatches
-	Nr_cancel_dispatches  uint64 `json:"nr_cancel_dispatches"`  // Number of cancelled dispatches
-	Nr_bounce_dispatches  uint64 `json:"nr_bounce_dispatches"`  // Number of bounce dispatches
-	Nr_failed_dispatches  uint64 `json:"nr_failed_dispatches"`  // Number of failed dispatches
-	Nr_sched_congested    uint64 `json:"nr_sched_congested"`    // Number of times the scheduler was congested
+	Usersched_last_run_at uint64       `json:"usersched_last_run_at"` // The PID of the userspace scheduler
+	Nr_queued             uint64       `json:"nr_queued"`             // Number of tasks queued in the userspace scheduler
+	Nr_scheduled          uint64       `json:"nr_scheduled"`          // Number of tasks scheduled by the userspace scheduler
+	Nr_running            uint64       `json:"nr_running"`            // Number of tasks currently running in the userspace scheduler
+	Nr_online_cpus        uint64       `json:"nr_online_cpus"`        // Number of online CPUs in the system
+	Nr_user_dispatches    uint64       `json:"nr_user_dispatches"`    // Number of user-space dispatches
+	Nr_kernel_dispatches  uint64       `json:"nr_kernel_dispatches"`  // Number of kernel-space dispatches
+	Nr_cancel_dispatches  uint64       `json:"nr_cancel_dispatches"`  // Number of cancelled dispatches
+	Nr_bounce_dispatches  uint64       `json:"nr_bounce_dispatches"`  // Number of bounce dispatches
+	Nr_failed_dispatches  uint64       `json:"nr_failed_dispatches"`  // Number of failed dispatches
+	Nr_sched_congested    uint64       `json:"nr_sched_congested"`    // Number of times the scheduler was congested
+	Tasks                 []TaskMetric `json:"tasks,omitempty"`       // Per-task statistics, joined with the pods of the node
+}
+
+// TaskMetric holds the statistics of a single task as tracked by the scheduler
+type TaskMetric struct {
+	PID          int    `json:"pid"`
+	RuntimeNs    uint64 `json:"runtime_ns"`    // CPU time consumed by the task in nanoseconds
+	WaitTimeNs   uint64 `json:"wait_time_ns"`  // Time spent runnable but waiting to be dispatched in nanoseconds
+	NrDispatches uint64 `json:"nr_dispatches"` // Number of times the task was dispatched
+	Vtime        uint64 `json:"vtime"`         // Current virtual time of the task
 }
 
 // UpdateMetrics handles the updating of metrics via a REST endpoint.
@@ -42,7 +54,126 @@ func (h *Handler) UpdateMetrics(w http.ResponseWriter, r *http.Request) {
 		NrBounceDispatches: req.Nr_bounce_dispatches,
 		NrFailedDispatches: req.Nr_failed_dispatches,
 		NrSchedCongested:   req.Nr_sched_congested,
+		Tasks:              make([]domain.TaskMetric, 0, len(req.Tasks)),
+	}
+	for _, task := range req.Tasks {
+		newMetricSet.Tasks = append(newMetricSet.Tasks, domain.TaskMetric{
+			PID:          task.PID,
+			RuntimeNs:    task.RuntimeNs,
+			WaitTimeNs:   task.WaitTimeNs,
+			NrDispatches: task.NrDispatches,
+			Vtime:        task.Vtime,
+		})
 	}
 	h.Service.UpdateMetrics(r.Context(), newMetricSet)
 	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse[EmptyResponse](nil))
 }
+
+// MetricSample is the node metrics at one point in time, counters include previous scheduler runs
+type MetricSample struct {
+	Timestamp           string  `json:"timestamp"`
+	NrQueued            float64 `json:"nr_queued"`
+	NrScheduled         float64 `json:"nr_scheduled"`
+	NrRunning           float64 `json:"nr_running"`
+	NrOnlineCPUs        float64 `json:"nr_online_cpus"`
+	NrUserDispatches    uint64  `json:"nr_user_dispatches"`
+	NrKernelDispatches  uint64  `json:"nr_kernel_dispatches"`
+	NrCancelDispatches  uint64  `json:"nr_cancel_dispatches"`
+	NrBounceDispatches  uint64  `json:"nr_bounce_dispatches"`
+	NrFailedDispatches  uint64  `json:"nr_failed_dispatches"`
+	NrSchedCongested    uint64  `json:"nr_sched_congested"`
+	DispatchRate        float64 `json:"dispatch_rate"`
+	FailedDispatchRatio float64 `json:"failed_dispatch_ratio"`
+}
+
+type MetricsHistoryResponse struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Step    string         `json:"step,omitempty"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// defaultMetricsHistoryRange is the range returned when from is omitted
+const defaultMetricsHistoryRange = time.Hour
+
+// GetMetricsHistory returns the pushed node metrics between ?from= and ?to= (RFC3339 or unix seconds, the last hour
+// by default), downsampled to ?step= (a duration such as 30s, or seconds) when given
+func (h *Handler) GetMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	to := time.Now()
+	if value := query.Get("to"); value != "" {
+		var err error
+		to, err = parseTimeParam(value)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid to", err)
+			return
+		}
+	}
+	from := to.Add(-defaultMetricsHistoryRange)
+	if value := query.Get("from"); value != "" {
+		var err error
+		from, err = parseTimeParam(value)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid from", err)
+			return
+		}
+	}
+	var step time.Duration
+	if value := query.Get("step"); value != "" {
+		var err error
+		step, err = parseStepParam(value)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid step", err)
+			return
+		}
+	}
+
+	samples, err := h.Service.ListMetricsHistory(ctx, from, to, step)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	resp := MetricsHistoryResponse{
+		From:    from.UTC().Format(time.RFC3339),
+		To:      to.UTC().Format(time.RFC3339),
+		Samples: make([]MetricSample, 0, len(samples)),
+	}
+	if step > 0 {
+		resp.Step = step.String()
+	}
+	for _, sample := range samples {
+		resp.Samples = append(resp.Samples, MetricSample{
+			Timestamp:           sample.Timestamp.UTC().Format(time.RFC3339Nano),
+			NrQueued:            sample.NrQueued,
+			NrScheduled:         sample.NrScheduled,
+			NrRunning:           sample.NrRunning,
+			NrOnlineCPUs:        sample.NrOnlineCPUs,
+			NrUserDispatches:    sample.NrUserDispatches,
+			NrKernelDispatches:  sample.NrKernelDispatches,
+			NrCancelDispatches:  sample.NrCancelDispatches,
+			NrBounceDispatches:  sample.NrBounceDispatches,
+			NrFailedDispatches:  sample.NrFailedDispatches,
+			NrSchedCongested:    sample.NrSchedCongested,
+			DispatchRate:        sample.DispatchRate,
+			FailedDispatchRatio: sample.FailedDispatchRatio,
+		})
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// parseTimeParam accepts RFC3339 timestamps and unix seconds
+func parseTimeParam(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseStepParam accepts durations such as 30s and plain seconds
+func parseStepParam(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,76 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// PeerCred holds the credentials of the process on the other end of a Unix socket connection
+type PeerCred struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+type peerCredContextKey struct{}
+
+// PeerCredConnContext is meant for http.Server.ConnContext, it attaches the SO_PEERCRED credentials
+// of a Unix socket connection to the context of every request served on it
+func PeerCredConnContext(ctx context.Context, conn net.Conn) context.Context {
+	cred, err := peerCred(conn)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msg("failed to read peer credentials")
+		return ctx
+	}
+	return context.WithValue(ctx, peerCredContextKey{}, cred)
+}
+
+// PeerCredFromContext returns the peer credentials attached by PeerCredConnContext
+func PeerCredFromContext(ctx context.Context) (*PeerCred, bool) {
+	cred, ok := ctx.Value(peerCredContextKey{}).(*PeerCred)
+	return cred, ok
+}
+
+// GetPeerCredMiddleware only lets peers through whose uid or gid is allowed by cfg,
+// every peer is allowed when both allow lists are empty
+func GetPeerCredMiddleware(cfg config.UnixSocketConfig) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cred, ok := PeerCredFromContext(r.Context())
+			if !ok {
+				writePeerCredError(w, r, http.StatusUnauthorized, "Peer credentials are unavailable")
+				return
+			}
+			if !peerAllowed(cfg, cred) {
+				writePeerCredError(w, r, http.StatusForbidden, "Peer is not allowed")
+				return
+			}
+			logger.Logger(r.Context()).Debug().Int32("peer_pid", cred.PID).Uint32("peer_uid", cred.UID).Msg("peer credentials validated successfully")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func peerAllowed(cfg config.UnixSocketConfig, cred *PeerCred) bool {
+	if len(cfg.AllowedUIDs) == 0 && len(cfg.AllowedGIDs) == 0 {
+		return true
+	}
+	return slices.Contains(cfg.AllowedUIDs, cred.UID) || slices.Contains(cfg.AllowedGIDs, cred.GID)
+}
+
+func writePeerCredError(w http.ResponseWriter, r *http.Request, status int, errMsg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(ErrorResponse{
+		Success: false,
+		Error:   errMsg,
+	}); err != nil {
+		logger.Logger(r.Context()).Error().Err(err).Msg("Failed to write peer credential error response")
+	}
+}
@@ -0,0 +1,75 @@
+//go:build linux
+
+package rest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerCredMiddleware tests that requests over a Unix socket are authorized by the uid and gid of the peer
+func TestPeerCredMiddleware(t *testing.T) {
+	logger.InitLogger()
+	uid, gid := uint32(os.Getuid()), uint32(os.Getgid())
+	testCases := []struct {
+		name           string
+		cfg            config.UnixSocketConfig
+		expectedStatus int
+	}{
+		{name: "no allow list", cfg: config.UnixSocketConfig{}, expectedStatus: http.StatusOK},
+		{name: "allowed uid", cfg: config.UnixSocketConfig{AllowedUIDs: []uint32{uid}}, expectedStatus: http.StatusOK},
+		{name: "allowed gid", cfg: config.UnixSocketConfig{AllowedUIDs: []uint32{uid + 1}, AllowedGIDs: []uint32{gid}}, expectedStatus: http.StatusOK},
+		{name: "denied", cfg: config.UnixSocketConfig{AllowedUIDs: []uint32{uid + 1}}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			socketPath := filepath.Join(t.TempDir(), "dm.sock")
+			listener, err := net.Listen("unix", socketPath)
+			require.NoError(t, err, "listen on unix socket should not return error")
+			var peer *PeerCred
+			server := &http.Server{
+				Handler: GetPeerCredMiddleware(tc.cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					peer, _ = PeerCredFromContext(r.Context())
+					w.WriteHeader(http.StatusOK)
+				})),
+				ConnContext: PeerCredConnContext,
+			}
+			go server.Serve(listener)
+			defer server.Close()
+
+			client := &http.Client{Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+				},
+			}}
+			resp, err := client.Get("http://unix/")
+			require.NoError(t, err, "request should not return error")
+			resp.Body.Close()
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode, "unexpected status code")
+			if tc.expectedStatus == http.StatusOK {
+				require.NotNil(t, peer, "peer credentials should be attached")
+				assert.Equal(t, int32(os.Getpid()), peer.PID, "unexpected peer pid")
+				assert.Equal(t, uid, peer.UID, "unexpected peer uid")
+			}
+		})
+	}
+
+	t.Run("tcp connection", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		GetPeerCredMiddleware(config.UnixSocketConfig{})(http.NotFoundHandler()).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, "requests without peer credentials should be rejected")
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package rest
+
+import (
+	"errors"
+	"net"
+)
+
+// peerCred is only supported on linux
+func peerCred(conn net.Conn) (*PeerCred, error) {
+	return nil, errors.New("SO_PEERCRED is only supported on linux")
+}
@@ -0,0 +1,88 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+type ListPodPIDsResponse struct {
+	Pods []PodPIDs `json:"pods"`
+}
+
+// PodPIDs is a pod discovered on the node, intent is omitted when no intent targets the pod
+type PodPIDs struct {
+	PodUID    string       `json:"podUID"`
+	Intent    *Intent      `json:"intent,omitempty"`
+	Processes []ProcessPID `json:"processes"`
+}
+
+// ProcessPID is a process of a pod with the scheduling intents currently applied to its PID
+type ProcessPID struct {
+	PID               int                  `json:"pid"`
+	PPID              int                  `json:"ppid,omitempty"`
+	Command           string               `json:"command"`
+	Cmdline           string               `json:"cmdline,omitempty"`
+	ContainerID       string               `json:"containerID,omitempty"`
+	SchedulingIntents []*SchedulingIntents `json:"schedulingIntents"`
+}
+
+// ListPodPIDs returns the pods discovered on this node with their processes,
+// filtered by the podUID and containerID query parameters (comma separated or repeated)
+func (h *Handler) ListPodPIDs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter := domain.PodProcessFilter{
+		PodUIDs:      queryValues(r, "podUID"),
+		ContainerIDs: queryValues(r, "containerID"),
+	}
+	pods, err := h.Service.ListPodProcesses(ctx, filter)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to list pod processes", err)
+		return
+	}
+
+	resp := ListPodPIDsResponse{
+		Pods: make([]PodPIDs, 0, len(pods)),
+	}
+	for _, pod := range pods {
+		respPod := PodPIDs{
+			PodUID:    pod.PodUID,
+			Processes: make([]ProcessPID, 0, len(pod.Processes)),
+		}
+		if pod.Intent != nil {
+			intent := convertDomainIntent(pod.Intent)
+			respPod.Intent = &intent
+		}
+		for _, process := range pod.Processes {
+			respProcess := ProcessPID{
+				PID:               process.PID,
+				PPID:              process.PPID,
+				Command:           process.Command,
+				Cmdline:           process.Cmdline,
+				ContainerID:       process.ContainerID,
+				SchedulingIntents: make([]*SchedulingIntents, 0, len(process.SchedulingIntents)),
+			}
+			for _, schedulingIntent := range process.SchedulingIntents {
+				respProcess.SchedulingIntents = append(respProcess.SchedulingIntents, convertDomainSchedulingIntents(schedulingIntent))
+			}
+			respPod.Processes = append(respPod.Processes, respProcess)
+		}
+		resp.Pods = append(resp.Pods, respPod)
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// queryValues collects the values of a query parameter given either repeatedly or comma separated
+func queryValues(r *http.Request, key string) []string {
+	var values []string
+	for _, value := range r.URL.Query()[key] {
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
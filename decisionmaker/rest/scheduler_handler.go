@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+// SchedulerHealth is the watchdog verdict on the scheduler of the node
+type SchedulerHealth struct {
+	State              string `json:"state"`
+	Reason             string `json:"reason,omitempty"`
+	LastMetricsAt      string `json:"last_metrics_at,omitempty"`
+	UserSchedLastRunAt uint64 `json:"usersched_last_run_at,omitempty"`
+	LastRunAdvancedAt  string `json:"last_run_advanced_at,omitempty"`
+}
+
+// SchedulerEvent is a transition of the scheduler state
+type SchedulerEvent struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// SchedExtStatus is the sched_ext state of the node read from sysfs
+type SchedExtStatus struct {
+	State      string            `json:"state"`
+	Ops        string            `json:"ops,omitempty"` // name of the loaded BPF scheduler
+	EnableSeq  uint64            `json:"enable_seq"`
+	SwitchAll  bool              `json:"switch_all"`
+	NrRejected uint64            `json:"nr_rejected"`
+	Events     map[string]uint64 `json:"events,omitempty"`
+}
+
+// SchedulerStatusResponse tells which BPF scheduler runs on the node and whether it is alive
+type SchedulerStatusResponse struct {
+	SchedExt  *SchedExtStatus  `json:"sched_ext"`
+	Scheduler *SchedulerHealth `json:"scheduler"`
+}
+
+type ListSchedulerEventsResponse struct {
+	Events []SchedulerEvent `json:"events"`
+}
+
+// Readiness godoc
+// @Summary Readiness check
+// @Description Fails with 503 while the watchdog considers the scheduler of the node stalled.
+// @Tags System
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Failure 503 {object} HealthResponse
+// @Router /ready [get]
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	health := h.Service.SchedulerHealth(ctx)
+	response := HealthResponse{
+		Status:    "ready",
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Service:   "BSS Metrics API Server",
+		Scheduler: convertDomainSchedulerHealth(health),
+	}
+	status := http.StatusOK
+	if health.State == domain.SchedulerStateStalled {
+		response.Status = "not ready"
+		status = http.StatusServiceUnavailable
+	}
+	h.JSONResponse(ctx, w, status, response)
+}
+
+// GetSchedulerStatus returns the sched_ext state with the loaded BPF scheduler and the watchdog verdict
+func (h *Handler) GetSchedulerStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status, err := h.Service.SchedExtStatus(ctx)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusInternalServerError, "Failed to read sched_ext status", err)
+		return
+	}
+	resp := SchedulerStatusResponse{
+		SchedExt:  convertDomainSchedExtStatus(status),
+		Scheduler: convertDomainSchedulerHealth(h.Service.SchedulerHealth(ctx)),
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+// ListSchedulerEvents returns the stalled and recovered transitions of the scheduler, optionally after ?since=<RFC3339>
+func (h *Handler) ListSchedulerEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid since timestamp", err)
+			return
+		}
+	}
+	events := h.Service.ListSchedulerEvents(ctx, since)
+	resp := ListSchedulerEventsResponse{
+		Events: make([]SchedulerEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, SchedulerEvent{
+			Type:      string(event.Type),
+			Reason:    event.Reason,
+			Timestamp: event.Timestamp.UTC().Format(time.RFC3339),
+		})
+	}
+	h.JSONResponse(ctx, w, http.StatusOK, NewSuccessResponse(&resp))
+}
+
+func convertDomainSchedExtStatus(status *domain.SchedExtStatus) *SchedExtStatus {
+	return &SchedExtStatus{
+		State:      string(status.State),
+		Ops:        status.Ops,
+		EnableSeq:  status.EnableSeq,
+		SwitchAll:  status.SwitchAll,
+		NrRejected: status.NrRejected,
+		Events:     status.Events,
+	}
+}
+
+func convertDomainSchedulerHealth(health domain.SchedulerHealth) *SchedulerHealth {
+	resp := &SchedulerHealth{
+		State:              string(health.State),
+		Reason:             health.Reason,
+		UserSchedLastRunAt: health.UserSchedLastRunAt,
+	}
+	if !health.LastMetricsAt.IsZero() {
+		resp.LastMetricsAt = health.LastMetricsAt.UTC().Format(time.RFC3339)
+	}
+	if !health.LastRunAdvancedAt.IsZero() {
+		resp.LastRunAdvancedAt = health.LastRunAdvancedAt.UTC().Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// watchHeartbeatInterval keeps idle streams alive through proxies and lets clients detect dead connections
+const watchHeartbeatInterval = 15 * time.Second
+
+// SchedulingIntentEvent is the data of a server-sent event of the scheduling intents watch
+type SchedulingIntentEvent struct {
+	Type            string             `json:"type"`
+	ResourceVersion int64              `json:"resource_version"`
+	Key             string             `json:"key,omitempty"`
+	Intent          *SchedulingIntents `json:"intent,omitempty"`
+}
+
+// WatchIntents streams add, modify and delete events of the scheduling intents as server-sent events.
+// The event ID is the resource version, a client resumes with ?resourceVersion= or the Last-Event-ID header.
+func (h *Handler) WatchIntents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	resourceVersion, err := watchResourceVersion(r)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid resource version", err)
+		return
+	}
+	initial, events, err := h.Service.WatchSchedulingIntents(ctx, resourceVersion)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	for _, event := range initial {
+		if err := writeSchedulingIntentEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		logger.Logger(ctx).Error().Err(err).Msg("streaming is not supported by the response writer")
+		return
+	}
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// dropped for falling behind, the client resumes from its last event ID
+				return
+			}
+			if err := writeSchedulingIntentEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func watchResourceVersion(r *http.Request) (int64, error) {
+	value := r.URL.Query().Get("resourceVersion")
+	if value == "" {
+		value = r.Header.Get("Last-Event-ID")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func writeSchedulingIntentEvent(w http.ResponseWriter, event domain.SchedulingIntentEvent) error {
+	data := SchedulingIntentEvent{
+		Type:            string(event.Type),
+		ResourceVersion: event.ResourceVersion,
+		Key:             event.Key,
+	}
+	if event.Intent != nil {
+		data.Intent = convertDomainSchedulingIntents(event.Intent)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ResourceVersion, event.Type, payload)
+	return err
+}
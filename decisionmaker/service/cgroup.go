@@ -0,0 +1,108 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CgroupParser extracts the pod UID and container ID from the cgroup path of a process.
+// ok is false when the path does not belong to a pod in the layout the parser understands.
+type CgroupParser interface {
+	Name() string
+	Parse(cgroupPath string) (podUID string, containerID string, ok bool)
+}
+
+// DefaultCgroupParsers understands the systemd and cgroupfs cgroup drivers of kubelet
+// combined with containerd, cri-o and docker, on both cgroup v1 and v2
+func DefaultCgroupParsers() []CgroupParser {
+	return []CgroupParser{
+		systemdCgroupParser{},
+		cgroupfsCgroupParser{},
+	}
+}
+
+var (
+	// kubepods-burstable-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice
+	systemdPodSliceRegex = regexp.MustCompile(`^(?:[a-z0-9_]+-)*pod([0-9a-fA-F_]+)\.slice$`)
+	// cri-containerd-<id>.scope, crio-<id>.scope, docker-<id>.scope
+	systemdContainerScopeRegex = regexp.MustCompile(`^(?:cri-containerd|crio|docker)-([0-9a-fA-F]+)\.scope$`)
+	// pod20da609e-6973-4463-a1f9-2db9bcc5becc
+	cgroupfsPodRegex = regexp.MustCompile(`^pod([0-9a-fA-F-]+)$`)
+	// <id>, optionally carrying the runtime prefix cri-o and docker use in some setups
+	cgroupfsContainerRegex = regexp.MustCompile(`^(?:cri-containerd-|crio-|docker-)?([0-9a-fA-F]{12,})(?:\.scope)?$`)
+)
+
+// systemdCgroupParser handles paths created by the systemd cgroup driver, e.g.
+// /kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope
+type systemdCgroupParser struct{}
+
+func (systemdCgroupParser) Name() string {
+	return "systemd"
+}
+
+func (systemdCgroupParser) Parse(cgroupPath string) (string, string, bool) {
+	var podUID, containerID string
+	for _, part := range strings.Split(cgroupPath, "/") {
+		if m := systemdPodSliceRegex.FindStringSubmatch(part); m != nil {
+			podUID = strings.ReplaceAll(m[1], "_", "-")
+			continue
+		}
+		if podUID == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "crio-conmon-") {
+			// the cri-o container monitor is not part of the workload
+			return "", "", false
+		}
+		if m := systemdContainerScopeRegex.FindStringSubmatch(part); m != nil {
+			containerID = m[1]
+		}
+	}
+	if podUID == "" {
+		return "", "", false
+	}
+	return podUID, containerID, true
+}
+
+// cgroupfsCgroupParser handles paths created by the cgroupfs cgroup driver, e.g.
+// /kubepods/burstable/pod<uid>/<id>
+type cgroupfsCgroupParser struct{}
+
+func (cgroupfsCgroupParser) Name() string {
+	return "cgroupfs"
+}
+
+func (cgroupfsCgroupParser) Parse(cgroupPath string) (string, string, bool) {
+	parts := strings.Split(cgroupPath, "/")
+	inKubepods := false
+	for i, part := range parts {
+		if part == "kubepods" {
+			inKubepods = true
+			continue
+		}
+		if !inKubepods {
+			continue
+		}
+		m := cgroupfsPodRegex.FindStringSubmatch(part)
+		if m == nil {
+			continue
+		}
+		var containerID string
+		if i+1 < len(parts) {
+			if cm := cgroupfsContainerRegex.FindStringSubmatch(parts[i+1]); cm != nil {
+				containerID = cm[1]
+			}
+		}
+		return m[1], containerID, true
+	}
+	return "", "", false
+}
+
+// parseCgroupLine splits a /proc/<pid>/cgroup line ("hierarchy-ID:controllers:path") and returns its path
+func parseCgroupLine(line string) (string, bool) {
+	parts := strings.SplitN(line, ":", 3)
+	if len(parts) < 3 {
+		return "", false
+	}
+	return parts[2], true
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fixturePodUID      = "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	fixturePodSliceUID = "20da609e_6973_4463_a1f9_2db9bcc5becc"
+	fixtureContainerID = "10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635"
+)
+
+type procFixture struct {
+	comm   string
+	cgroup string
+}
+
+// writeProcFixture creates a fake /proc tree holding the given processes
+func writeProcFixture(t *testing.T, procs map[int]procFixture) string {
+	root := t.TempDir()
+	for pid, proc := range procs {
+		pidDir := filepath.Join(root, strconv.Itoa(pid))
+		require.NoError(t, os.Mkdir(pidDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(pidDir, "cgroup"), []byte(proc.cgroup), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(pidDir, "comm"), []byte(proc.comm+"\n"), 0644))
+	}
+	return root
+}
+
+// TestFindPodInfoFromCgroupLayouts tests pod discovery across container runtimes, cgroup drivers and cgroup versions
+func TestFindPodInfoFromCgroupLayouts(t *testing.T) {
+	logger.InitLogger()
+	testCases := []struct {
+		name                string
+		procs               map[int]procFixture
+		expectedPIDs        []int
+		expectedContainerID string
+	}{
+		{
+			name: "containerd systemd cgroup v2",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + fixturePodSliceUID + ".slice/cri-containerd-" + fixtureContainerID + ".scope\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "containerd systemd cgroup v2 under kubelet slice",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod" + fixturePodSliceUID + ".slice/cri-containerd-" + fixtureContainerID + ".scope\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "cri-o systemd cgroup v2 ignores conmon",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod" + fixturePodSliceUID + ".slice/crio-" + fixtureContainerID + ".scope\n"},
+				101: {comm: "conmon", cgroup: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod" + fixturePodSliceUID + ".slice/crio-conmon-" + fixtureContainerID + ".scope\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "docker systemd cgroup v2",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubepods.slice/kubepods-pod" + fixturePodSliceUID + ".slice/docker-" + fixtureContainerID + ".scope\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "containerd cgroupfs cgroup v2",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubepods/burstable/pod" + fixturePodUID + "/" + fixtureContainerID + "\n"},
+				101: {comm: "worker", cgroup: "0::/kubepods/burstable/pod" + fixturePodUID + "/" + fixtureContainerID + "\n"},
+			},
+			expectedPIDs:        []int{100, 101},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "guaranteed pod cgroupfs cgroup v2",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "0::/kubepods/pod" + fixturePodUID + "/" + fixtureContainerID + "\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "docker cgroupfs cgroup v1",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "12:pids:/kubepods/besteffort/pod" + fixturePodUID + "/" + fixtureContainerID + "\n" +
+					"11:cpu,cpuacct:/kubepods/besteffort/pod" + fixturePodUID + "/" + fixtureContainerID + "\n" +
+					"10:memory:/kubepods/besteffort/pod" + fixturePodUID + "/" + fixtureContainerID + "\n" +
+					"1:name=systemd:/kubepods/besteffort/pod" + fixturePodUID + "/" + fixtureContainerID + "\n" +
+					"0::/\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "containerd systemd cgroup v1",
+			procs: map[int]procFixture{
+				100: {comm: "nginx", cgroup: "12:cpuset:/\n" +
+					"11:memory:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + fixturePodSliceUID + ".slice/cri-containerd-" + fixtureContainerID + ".scope\n" +
+					"10:cpu,cpuacct:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + fixturePodSliceUID + ".slice/cri-containerd-" + fixtureContainerID + ".scope\n" +
+					"1:name=systemd:/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + fixturePodSliceUID + ".slice/cri-containerd-" + fixtureContainerID + ".scope\n"},
+			},
+			expectedPIDs:        []int{100},
+			expectedContainerID: fixtureContainerID,
+		},
+		{
+			name: "processes outside of pods",
+			procs: map[int]procFixture{
+				1:   {comm: "systemd", cgroup: "0::/init.scope\n"},
+				200: {comm: "sshd", cgroup: "0::/system.slice/ssh.service\n"},
+				300: {comm: "kubelet", cgroup: "0::/kubepods.slice\n"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &Service{}
+			pods, err := svc.FindPodInfoFrom(context.Background(), writeProcFixture(t, tc.procs))
+			require.NoError(t, err, "FindPodInfoFrom should not return error")
+			if len(tc.expectedPIDs) == 0 {
+				assert.Empty(t, pods, "should not find any pod")
+				return
+			}
+			require.Len(t, pods, 1, "should find one pod")
+			pod := pods[fixturePodUID]
+			require.NotNil(t, pod, "pod info should not be nil")
+			pids := []int{}
+			for _, process := range pod.Processes {
+				pids = append(pids, process.PID)
+				assert.Equal(t, tc.expectedContainerID, process.ContainerID, "unexpected containerID")
+			}
+			assert.ElementsMatch(t, tc.expectedPIDs, pids, "unexpected pids")
+		})
+	}
+}
+
+type staticCgroupParser struct{}
+
+func (staticCgroupParser) Name() string {
+	return "static"
+}
+
+func (staticCgroupParser) Parse(cgroupPath string) (string, string, bool) {
+	if cgroupPath != "/custom/workload" {
+		return "", "", false
+	}
+	return fixturePodUID, "custom", true
+}
+
+// TestCustomCgroupParser tests that additional cgroup layouts can be plugged in
+func TestCustomCgroupParser(t *testing.T) {
+	logger.InitLogger()
+	svc := &Service{cgroupParsers: append(DefaultCgroupParsers(), staticCgroupParser{})}
+	pods, err := svc.FindPodInfoFrom(context.Background(), writeProcFixture(t, map[int]procFixture{
+		100: {comm: "app", cgroup: "0::/custom/workload\n"},
+	}))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	require.NotNil(t, pods[fixturePodUID], "pod info should not be nil")
+	assert.Equal(t, "custom", pods[fixturePodUID].Processes[0].ContainerID, "unexpected containerID")
+}
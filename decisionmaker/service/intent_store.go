@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+// RestoreIntentSet reloads the intent set snapshot written before the last shutdown and
+// re-resolves its intents against the processes currently found in /proc
+func (svc *Service) RestoreIntentSet(ctx context.Context) error {
+	if svc.storePath == "" {
+		return nil
+	}
+	set, err := svc.loadIntentSet()
+	if err != nil {
+		return err
+	}
+	if set == nil {
+		logger.Logger(ctx).Info().Msgf("no intent snapshot found at %s", svc.storePath)
+		return nil
+	}
+	results, err := svc.SyncIntents(ctx, set.Generation, set.Intents)
+	if err != nil {
+		return fmt.Errorf("restore intent snapshot %s: %w", svc.storePath, err)
+	}
+	applied := 0
+	for _, result := range results {
+		if result.Status == domain.IntentResultApplied {
+			applied++
+		}
+	}
+	logger.Logger(ctx).Info().Msgf("restored %d intents of generation %d from %s, %d applied", len(set.Intents), set.Generation, svc.storePath, applied)
+	return nil
+}
+
+// loadIntentSet reads the snapshot file, a missing file yields a nil set
+func (svc *Service) loadIntentSet() (*domain.IntentSet, error) {
+	data, err := os.ReadFile(svc.storePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read intent snapshot %s: %w", svc.storePath, err)
+	}
+	var set domain.IntentSet
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("decode intent snapshot %s: %w", svc.storePath, err)
+	}
+	return &set, nil
+}
+
+// persistIntentSet snapshots the current intent set, the caller must hold intentSet.mu.
+// Failures are only logged since the in-memory set stays authoritative.
+func (svc *Service) persistIntentSet(ctx context.Context) {
+	if svc.storePath == "" {
+		return
+	}
+	set := &domain.IntentSet{
+		Generation: svc.intentSet.generation,
+		Intents:    svc.intentSet.list(),
+	}
+	err := writeFileAtomic(svc.storePath, set)
+	if err != nil {
+		logger.Logger(ctx).Error().Err(err).Msgf("failed to write intent snapshot %s", svc.storePath)
+	}
+}
+
+// writeFileAtomic encodes v as JSON into a temporary file and renames it over path,
+// so a crash never leaves a truncated snapshot behind
+func writeFileAtomic(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/decisionmaker/domain"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -11,6 +14,20 @@ var (
 	_ prometheus.Collector = (*MetricCollector)(nil)
 )
 
+// metricNamespace prefixes every metric exported by the decision maker
+const metricNamespace = "gthulhu"
+
+// schedulerCounters lists the monotonically increasing fields of domain.MetricSet. They restart
+// from zero together with the scheduler, so a decrease of any of them is taken as a restart.
+var schedulerCounters = []func(*domain.MetricSet) *uint64{
+	func(m *domain.MetricSet) *uint64 { return &m.NrUserDispatches },
+	func(m *domain.MetricSet) *uint64 { return &m.NrKernelDispatches },
+	func(m *domain.MetricSet) *uint64 { return &m.NrCancelDispatches },
+	func(m *domain.MetricSet) *uint64 { return &m.NrBounceDispatches },
+	func(m *domain.MetricSet) *uint64 { return &m.NrFailedDispatches },
+	func(m *domain.MetricSet) *uint64 { return &m.NrSchedCongested },
+}
+
 // MetricCollector
 type MetricCollector struct {
 	machineID string
@@ -27,80 +44,219 @@ type MetricCollector struct {
 	NrFailedDispatchesMetric *prometheus.Desc
 	NrSchedCongestedMetric   *prometheus.Desc
 
-	metricSet atomic.Pointer[domain.MetricSet]
+	// derived from consecutive pushes, so they survive restarts of the scheduler
+	SchedulerRestartsMetric   *prometheus.Desc
+	DispatchRateMetric        *prometheus.Desc
+	FailedDispatchRatioMetric *prometheus.Desc
+	// set by the watchdog
+	SchedulerStalledMetric *prometheus.Desc
+
+	// read from sysfs on every scrape
+	SchedExtInfoMetric       *prometheus.Desc
+	SchedExtEnabledMetric    *prometheus.Desc
+	SchedExtEnableSeqMetric  *prometheus.Desc
+	SchedExtNrRejectedMetric *prometheus.Desc
+	SchedExtEventsMetric     *prometheus.Desc
+
+	// per pod (or per PID) series of the task statistics pushed by the scheduler
+	TaskRuntimeMetric    *prometheus.Desc
+	TaskWaitTimeMetric   *prometheus.Desc
+	TaskDispatchesMetric *prometheus.Desc
+	TaskVtimeMetric      *prometheus.Desc
+	TasksMetric          *prometheus.Desc
+
+	metricsConfig config.MetricsConfig
+	now           func() time.Time
+	watchdog      *schedulerWatchdog
+	schedExt      *schedExtReader
+
+	// mu serializes pushes, which compare the new metric set with the previous one
+	mu         sync.Mutex
+	lastPush   *domain.MetricSet
+	lastPushAt time.Time
+	offsets    domain.MetricSet // counter values accumulated before the last scheduler restart
+
+	snapshot   atomic.Pointer[metricSnapshot]
+	taskSeries atomic.Pointer[[]*taskSeries]
+	history    *metricHistory
+}
+
+// metricSnapshot is what Collect exports, computed once per push
+type metricSnapshot struct {
+	metricSet *domain.MetricSet
+	// counters holds the counter fields of metricSet including the values of previous scheduler runs
+	counters            domain.MetricSet
+	restarts            uint64
+	hasRates            bool
+	dispatchRate        float64
+	failedDispatchRatio float64
 }
 
 // // NewMetricCollector creates a new MetricCollector for a specific game server.
-func NewMetricCollector(machineID string) *MetricCollector {
+func NewMetricCollector(machineID string, metricsConfig config.MetricsConfig) *MetricCollector {
 	constantLabels := prometheus.Labels{"machine_id": machineID}
+	taskLabels := []string{"pod_uid", "namespace", "pod_name", "strategy_id"}
+	if metricsConfig.PerPID {
+		taskLabels = append(taskLabels, "pid")
+	}
+	name := func(name string) string {
+		return prometheus.BuildFQName(metricNamespace, "", name)
+	}
 	return &MetricCollector{
-		machineID: machineID,
+		machineID:     machineID,
+		metricsConfig: metricsConfig,
+		now:           time.Now,
+		history:       newMetricHistory(metricsConfig.HistoryRetention, metricsConfig.HistoryMaxPoints),
 		UserSchedLastRunAtMetric: prometheus.NewDesc(
-			"user_sched_last_run_at",
+			name("user_sched_last_run_at"),
 			"the timestamp of the last user scheduling run",
 			nil,
 			constantLabels,
 		),
 		NrQueuedMetric: prometheus.NewDesc(
-			"nr_queued",
+			name("nr_queued"),
 			"number of tasks queued in the userspace scheduler",
 			nil,
 			constantLabels,
 		),
 		NrScheduledMetric: prometheus.NewDesc(
-			"nr_scheduled",
+			name("nr_scheduled"),
 			"number of tasks scheduled by the userspace scheduler",
 			nil,
 			constantLabels,
 		),
 		NrRunningMetric: prometheus.NewDesc(
-			"nr_running",
+			name("nr_running"),
 			"number of tasks currently running in the userspace scheduler",
 			nil,
 			constantLabels,
 		),
 		NrOnlineCPUsMetric: prometheus.NewDesc(
-			"nr_online_cpus",
+			name("nr_online_cpus"),
 			"number of online CPUs in the system",
 			nil,
 			constantLabels,
 		),
 		NrUserDispatchesMetric: prometheus.NewDesc(
-			"nr_user_dispatches",
+			name("user_dispatches_total"),
 			"number of user-space dispatches",
 			nil,
 			constantLabels,
 		),
 		NrKernelDispatchesMetric: prometheus.NewDesc(
-			"nr_kernel_dispatches",
+			name("kernel_dispatches_total"),
 			"number of kernel-space dispatches",
 			nil,
 			constantLabels,
 		),
 		NrCancelDispatchesMetric: prometheus.NewDesc(
-			"nr_cancel_dispatches",
+			name("cancel_dispatches_total"),
 			"number of canceled dispatches",
 			nil,
 			constantLabels,
 		),
 		NrBounceDispatchesMetric: prometheus.NewDesc(
-			"nr_bounce_dispatches",
+			name("bounce_dispatches_total"),
 			"number of bounced dispatches",
 			nil,
 			constantLabels,
 		),
 		NrFailedDispatchesMetric: prometheus.NewDesc(
-			"nr_failed_dispatches",
+			name("failed_dispatches_total"),
 			"number of failed dispatches",
 			nil,
 			constantLabels,
 		),
 		NrSchedCongestedMetric: prometheus.NewDesc(
-			"nr_sched_congested",
+			name("sched_congested_total"),
 			"number of times the scheduler was congested",
 			nil,
 			constantLabels,
 		),
+		SchedulerRestartsMetric: prometheus.NewDesc(
+			name("scheduler_restarts_total"),
+			"number of scheduler restarts detected from decreasing counters",
+			nil,
+			constantLabels,
+		),
+		DispatchRateMetric: prometheus.NewDesc(
+			name("dispatches_per_second"),
+			"user-space and kernel-space dispatches per second between the last two pushes",
+			nil,
+			constantLabels,
+		),
+		FailedDispatchRatioMetric: prometheus.NewDesc(
+			name("failed_dispatch_ratio"),
+			"share of the dispatch attempts between the last two pushes that failed",
+			nil,
+			constantLabels,
+		),
+		SchedulerStalledMetric: prometheus.NewDesc(
+			name("scheduler_stalled"),
+			"1 when the scheduler stopped pushing metrics or running, 0 otherwise",
+			nil,
+			constantLabels,
+		),
+		SchedExtInfoMetric: prometheus.NewDesc(
+			name("sched_ext_info"),
+			"sched_ext state and the name of the loaded BPF scheduler, always 1",
+			[]string{"state", "ops"},
+			constantLabels,
+		),
+		SchedExtEnabledMetric: prometheus.NewDesc(
+			name("sched_ext_enabled"),
+			"1 when a BPF scheduler is enabled, 0 when the kernel runs its default scheduler",
+			nil,
+			constantLabels,
+		),
+		SchedExtEnableSeqMetric: prometheus.NewDesc(
+			name("sched_ext_enable_seq_total"),
+			"number of times a BPF scheduler was enabled since boot",
+			nil,
+			constantLabels,
+		),
+		SchedExtNrRejectedMetric: prometheus.NewDesc(
+			name("sched_ext_rejected_total"),
+			"number of tasks whose switch to sched_ext was rejected",
+			nil,
+			constantLabels,
+		),
+		SchedExtEventsMetric: prometheus.NewDesc(
+			name("sched_ext_events_total"),
+			"event counters of the loaded BPF scheduler",
+			[]string{"event"},
+			constantLabels,
+		),
+		TaskRuntimeMetric: prometheus.NewDesc(
+			name("task_runtime_seconds"),
+			"CPU time consumed by the tasks",
+			taskLabels,
+			constantLabels,
+		),
+		TaskWaitTimeMetric: prometheus.NewDesc(
+			name("task_wait_time_seconds"),
+			"time the tasks spent runnable but waiting to be dispatched",
+			taskLabels,
+			constantLabels,
+		),
+		TaskDispatchesMetric: prometheus.NewDesc(
+			name("task_dispatches"),
+			"number of dispatches of the tasks",
+			taskLabels,
+			constantLabels,
+		),
+		TaskVtimeMetric: prometheus.NewDesc(
+			name("task_vtime"),
+			"average vtime of the tasks",
+			taskLabels,
+			constantLabels,
+		),
+		TasksMetric: prometheus.NewDesc(
+			name("tasks"),
+			"number of tasks reported by the scheduler",
+			taskLabels,
+			constantLabels,
+		),
 	}
 }
 
@@ -117,28 +273,156 @@ func (collector *MetricCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.NrBounceDispatchesMetric
 	ch <- collector.NrFailedDispatchesMetric
 	ch <- collector.NrSchedCongestedMetric
+	ch <- collector.SchedulerRestartsMetric
+	ch <- collector.DispatchRateMetric
+	ch <- collector.FailedDispatchRatioMetric
+	ch <- collector.SchedulerStalledMetric
+	ch <- collector.SchedExtInfoMetric
+	ch <- collector.SchedExtEnabledMetric
+	ch <- collector.SchedExtEnableSeqMetric
+	ch <- collector.SchedExtNrRejectedMetric
+	ch <- collector.SchedExtEventsMetric
+	ch <- collector.TaskRuntimeMetric
+	ch <- collector.TaskWaitTimeMetric
+	ch <- collector.TaskDispatchesMetric
+	ch <- collector.TaskVtimeMetric
+	ch <- collector.TasksMetric
 }
 
 // Collect is called by the Prometheus registry when collecting metrics.
 func (collector *MetricCollector) Collect(ch chan<- prometheus.Metric) {
-	metricSet := collector.metricSet.Load()
-	if metricSet == nil {
+	if collector.watchdog != nil {
+		stalled := 0.0
+		if collector.watchdog.check(collector.now()).State == domain.SchedulerStateStalled {
+			stalled = 1
+		}
+		ch <- prometheus.MustNewConstMetric(collector.SchedulerStalledMetric, prometheus.GaugeValue, stalled)
+	}
+	collector.collectSchedExt(ch)
+
+	snapshot := collector.snapshot.Load()
+	if snapshot == nil {
 		return
 	}
+	metricSet, counters := snapshot.metricSet, snapshot.counters
 
 	ch <- prometheus.MustNewConstMetric(collector.UserSchedLastRunAtMetric, prometheus.GaugeValue, float64(metricSet.UserSchedLastRunAt))
 	ch <- prometheus.MustNewConstMetric(collector.NrQueuedMetric, prometheus.GaugeValue, float64(metricSet.NrQueued))
 	ch <- prometheus.MustNewConstMetric(collector.NrScheduledMetric, prometheus.GaugeValue, float64(metricSet.NrScheduled))
 	ch <- prometheus.MustNewConstMetric(collector.NrRunningMetric, prometheus.GaugeValue, float64(metricSet.NrRunning))
 	ch <- prometheus.MustNewConstMetric(collector.NrOnlineCPUsMetric, prometheus.GaugeValue, float64(metricSet.NrOnlineCPUs))
-	ch <- prometheus.MustNewConstMetric(collector.NrUserDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrUserDispatches))
-	ch <- prometheus.MustNewConstMetric(collector.NrKernelDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrKernelDispatches))
-	ch <- prometheus.MustNewConstMetric(collector.NrCancelDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrCancelDispatches))
-	ch <- prometheus.MustNewConstMetric(collector.NrBounceDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrBounceDispatches))
-	ch <- prometheus.MustNewConstMetric(collector.NrFailedDispatchesMetric, prometheus.GaugeValue, float64(metricSet.NrFailedDispatches))
-	ch <- prometheus.MustNewConstMetric(collector.NrSchedCongestedMetric, prometheus.GaugeValue, float64(metricSet.NrSchedCongested))
+	ch <- prometheus.MustNewConstMetric(collector.NrUserDispatchesMetric, prometheus.CounterValue, float64(counters.NrUserDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.NrKernelDispatchesMetric, prometheus.CounterValue, float64(counters.NrKernelDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.NrCancelDispatchesMetric, prometheus.CounterValue, float64(counters.NrCancelDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.NrBounceDispatchesMetric, prometheus.CounterValue, float64(counters.NrBounceDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.NrFailedDispatchesMetric, prometheus.CounterValue, float64(counters.NrFailedDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.NrSchedCongestedMetric, prometheus.CounterValue, float64(counters.NrSchedCongested))
+	ch <- prometheus.MustNewConstMetric(collector.SchedulerRestartsMetric, prometheus.CounterValue, float64(snapshot.restarts))
+	if snapshot.hasRates {
+		ch <- prometheus.MustNewConstMetric(collector.DispatchRateMetric, prometheus.GaugeValue, snapshot.dispatchRate)
+		ch <- prometheus.MustNewConstMetric(collector.FailedDispatchRatioMetric, prometheus.GaugeValue, snapshot.failedDispatchRatio)
+	}
+
+	taskSeries := collector.taskSeries.Load()
+	if taskSeries == nil {
+		return
+	}
+	for _, series := range *taskSeries {
+		ch <- prometheus.MustNewConstMetric(collector.TaskRuntimeMetric, prometheus.GaugeValue, float64(series.runtimeNs)/1e9, series.labels...)
+		ch <- prometheus.MustNewConstMetric(collector.TaskWaitTimeMetric, prometheus.GaugeValue, float64(series.waitTimeNs)/1e9, series.labels...)
+		ch <- prometheus.MustNewConstMetric(collector.TaskDispatchesMetric, prometheus.GaugeValue, float64(series.dispatches), series.labels...)
+		ch <- prometheus.MustNewConstMetric(collector.TaskVtimeMetric, prometheus.GaugeValue, series.vtimeSum/float64(series.tasks), series.labels...)
+		ch <- prometheus.MustNewConstMetric(collector.TasksMetric, prometheus.GaugeValue, float64(series.tasks), series.labels...)
+	}
 }
 
+func (collector *MetricCollector) collectSchedExt(ch chan<- prometheus.Metric) {
+	if collector.schedExt == nil {
+		return
+	}
+	status, err := collector.schedExt.read()
+	if err != nil || status.State == domain.SchedExtStateUnsupported {
+		return
+	}
+	enabled := 0.0
+	if status.State == domain.SchedExtStateEnabled {
+		enabled = 1
+	}
+	ch <- prometheus.MustNewConstMetric(collector.SchedExtInfoMetric, prometheus.GaugeValue, 1, string(status.State), status.Ops)
+	ch <- prometheus.MustNewConstMetric(collector.SchedExtEnabledMetric, prometheus.GaugeValue, enabled)
+	ch <- prometheus.MustNewConstMetric(collector.SchedExtEnableSeqMetric, prometheus.CounterValue, float64(status.EnableSeq))
+	ch <- prometheus.MustNewConstMetric(collector.SchedExtNrRejectedMetric, prometheus.CounterValue, float64(status.NrRejected))
+	for event, value := range status.Events {
+		ch <- prometheus.MustNewConstMetric(collector.SchedExtEventsMetric, prometheus.CounterValue, float64(value), event)
+	}
+}
+
+// UpdateMetrics stores the metric set pushed by the scheduler. When a counter is lower than in the previous push
+// the scheduler restarted, the previous values are carried over so that the exported counters never decrease.
 func (collector *MetricCollector) UpdateMetrics(newMetricSet *domain.MetricSet) {
-	collector.metricSet.Store(newMetricSet)
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	now := collector.now()
+	previous := collector.snapshot.Load()
+	snapshot := &metricSnapshot{metricSet: newMetricSet}
+	if previous != nil {
+		snapshot.restarts = previous.restarts
+	}
+
+	if last := collector.lastPush; last != nil {
+		restarted := false
+		for _, counter := range schedulerCounters {
+			if *counter(newMetricSet) < *counter(last) {
+				restarted = true
+				break
+			}
+		}
+		if restarted {
+			snapshot.restarts++
+			for _, counter := range schedulerCounters {
+				*counter(&collector.offsets) += *counter(last)
+			}
+		}
+	}
+	for _, counter := range schedulerCounters {
+		*counter(&snapshot.counters) = *counter(&collector.offsets) + *counter(newMetricSet)
+	}
+
+	if previous != nil {
+		if elapsed := now.Sub(collector.lastPushAt).Seconds(); elapsed > 0 {
+			dispatched := float64(snapshot.counters.NrUserDispatches+snapshot.counters.NrKernelDispatches) -
+				float64(previous.counters.NrUserDispatches+previous.counters.NrKernelDispatches)
+			failed := float64(snapshot.counters.NrFailedDispatches) - float64(previous.counters.NrFailedDispatches)
+			snapshot.hasRates = true
+			snapshot.dispatchRate = dispatched / elapsed
+			if attempts := dispatched + failed; attempts > 0 {
+				snapshot.failedDispatchRatio = failed / attempts
+			}
+		}
+	}
+
+	collector.lastPush = newMetricSet
+	collector.lastPushAt = now
+	collector.snapshot.Store(snapshot)
+	collector.history.add(domain.MetricSample{
+		Timestamp:           now,
+		NrQueued:            float64(newMetricSet.NrQueued),
+		NrScheduled:         float64(newMetricSet.NrScheduled),
+		NrRunning:           float64(newMetricSet.NrRunning),
+		NrOnlineCPUs:        float64(newMetricSet.NrOnlineCPUs),
+		NrUserDispatches:    snapshot.counters.NrUserDispatches,
+		NrKernelDispatches:  snapshot.counters.NrKernelDispatches,
+		NrCancelDispatches:  snapshot.counters.NrCancelDispatches,
+		NrBounceDispatches:  snapshot.counters.NrBounceDispatches,
+		NrFailedDispatches:  snapshot.counters.NrFailedDispatches,
+		NrSchedCongested:    snapshot.counters.NrSchedCongested,
+		DispatchRate:        snapshot.dispatchRate,
+		FailedDispatchRatio: snapshot.failedDispatchRatio,
+	})
+}
+
+// UpdateTaskMetrics replaces the task series, tasks missing from the latest push disappear with it
+func (collector *MetricCollector) UpdateTaskMetrics(series []*taskSeries) {
+	collector.taskSeries.Store(&series)
 }
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricCollectorCounters tests that counters never decrease across scheduler restarts and that rates are derived
+func TestMetricCollectorCounters(t *testing.T) {
+	collector := NewMetricCollector("machine-1", config.MetricsConfig{})
+	now := time.Unix(1700000000, 0)
+	collector.now = func() time.Time { return now }
+
+	collector.UpdateMetrics(&domain.MetricSet{NrQueued: 3, NrUserDispatches: 100, NrKernelDispatches: 100, NrFailedDispatches: 10})
+	now = now.Add(10 * time.Second)
+	collector.UpdateMetrics(&domain.MetricSet{NrQueued: 1, NrUserDispatches: 150, NrKernelDispatches: 140, NrFailedDispatches: 20})
+	now = now.Add(10 * time.Second)
+	// the scheduler restarted, its counters start over
+	collector.UpdateMetrics(&domain.MetricSet{NrQueued: 2, NrUserDispatches: 40, NrKernelDispatches: 60, NrFailedDispatches: 0})
+
+	expected := `
+# HELP gthulhu_user_dispatches_total number of user-space dispatches
+# TYPE gthulhu_user_dispatches_total counter
+gthulhu_user_dispatches_total{machine_id="machine-1"} 190
+# HELP gthulhu_failed_dispatches_total number of failed dispatches
+# TYPE gthulhu_failed_dispatches_total counter
+gthulhu_failed_dispatches_total{machine_id="machine-1"} 20
+# HELP gthulhu_nr_queued number of tasks queued in the userspace scheduler
+# TYPE gthulhu_nr_queued gauge
+gthulhu_nr_queued{machine_id="machine-1"} 2
+# HELP gthulhu_scheduler_restarts_total number of scheduler restarts detected from decreasing counters
+# TYPE gthulhu_scheduler_restarts_total counter
+gthulhu_scheduler_restarts_total{machine_id="machine-1"} 1
+# HELP gthulhu_dispatches_per_second user-space and kernel-space dispatches per second between the last two pushes
+# TYPE gthulhu_dispatches_per_second gauge
+gthulhu_dispatches_per_second{machine_id="machine-1"} 10
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"gthulhu_user_dispatches_total",
+		"gthulhu_failed_dispatches_total",
+		"gthulhu_nr_queued",
+		"gthulhu_scheduler_restarts_total",
+		"gthulhu_dispatches_per_second",
+	)
+	require.NoError(t, err, "unexpected metrics")
+
+	now = now.Add(10 * time.Second)
+	collector.UpdateMetrics(&domain.MetricSet{NrUserDispatches: 70, NrKernelDispatches: 90, NrFailedDispatches: 15})
+	assert.InDelta(t, 0.2, collector.snapshot.Load().failedDispatchRatio, 1e-9, "unexpected failed dispatch ratio")
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/manager/errs"
+)
+
+const (
+	defaultHistoryRetention = time.Hour
+	// defaultHistoryMaxPoints holds an hour of pushes every second
+	defaultHistoryMaxPoints = 3600
+)
+
+// metricHistory is a ring buffer of the pushed node metrics, bounded both by age and by number of samples
+type metricHistory struct {
+	mu        sync.RWMutex
+	retention time.Duration
+	samples   []domain.MetricSample
+	next      int // index the next sample is written to
+	full      bool
+}
+
+func newMetricHistory(retention time.Duration, maxPoints int) *metricHistory {
+	if retention <= 0 {
+		retention = defaultHistoryRetention
+	}
+	if maxPoints <= 0 {
+		maxPoints = defaultHistoryMaxPoints
+	}
+	return &metricHistory{
+		retention: retention,
+		samples:   make([]domain.MetricSample, maxPoints),
+	}
+}
+
+func (history *metricHistory) add(sample domain.MetricSample) {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	history.samples[history.next] = sample
+	history.next = (history.next + 1) % len(history.samples)
+	if history.next == 0 {
+		history.full = true
+	}
+}
+
+// query returns the samples within [from, to] that are not older than the retention, oldest first.
+// A positive step downsamples them into buckets of step starting at from.
+func (history *metricHistory) query(from, to time.Time, step time.Duration, now time.Time) []domain.MetricSample {
+	history.mu.RLock()
+	defer history.mu.RUnlock()
+
+	if oldest := now.Add(-history.retention); from.Before(oldest) {
+		from = oldest
+	}
+	start, count := 0, history.next
+	if history.full {
+		start, count = history.next, len(history.samples)
+	}
+	samples := []domain.MetricSample{}
+	for i := range count {
+		sample := history.samples[(start+i)%len(history.samples)]
+		if sample.Timestamp.Before(from) || sample.Timestamp.After(to) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	if step <= 0 {
+		return samples
+	}
+	return downsampleMetrics(samples, from, step)
+}
+
+// downsampleMetrics merges the samples of every bucket of step: gauges and rates are averaged,
+// counters keep their last value. Buckets without samples are left out.
+func downsampleMetrics(samples []domain.MetricSample, from time.Time, step time.Duration) []domain.MetricSample {
+	downsampled := []domain.MetricSample{}
+	var bucket domain.MetricSample
+	var n float64
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		bucket.NrQueued /= n
+		bucket.NrScheduled /= n
+		bucket.NrRunning /= n
+		bucket.NrOnlineCPUs /= n
+		bucket.DispatchRate /= n
+		bucket.FailedDispatchRatio /= n
+		downsampled = append(downsampled, bucket)
+		n = 0
+	}
+	for _, sample := range samples {
+		bucketStart := from.Add(sample.Timestamp.Sub(from).Truncate(step))
+		if n > 0 && !bucketStart.Equal(bucket.Timestamp) {
+			flush()
+		}
+		if n == 0 {
+			bucket = domain.MetricSample{Timestamp: bucketStart}
+		}
+		n++
+		bucket.NrQueued += sample.NrQueued
+		bucket.NrScheduled += sample.NrScheduled
+		bucket.NrRunning += sample.NrRunning
+		bucket.NrOnlineCPUs += sample.NrOnlineCPUs
+		bucket.DispatchRate += sample.DispatchRate
+		bucket.FailedDispatchRatio += sample.FailedDispatchRatio
+		bucket.NrUserDispatches = sample.NrUserDispatches
+		bucket.NrKernelDispatches = sample.NrKernelDispatches
+		bucket.NrCancelDispatches = sample.NrCancelDispatches
+		bucket.NrBounceDispatches = sample.NrBounceDispatches
+		bucket.NrFailedDispatches = sample.NrFailedDispatches
+		bucket.NrSchedCongested = sample.NrSchedCongested
+	}
+	flush()
+	return downsampled
+}
+
+// ListMetricsHistory returns the node metrics pushed between from and to, downsampled to step when it is positive
+func (svc *Service) ListMetricsHistory(ctx context.Context, from, to time.Time, step time.Duration) ([]domain.MetricSample, error) {
+	if to.Before(from) {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("to %s is before from %s", to.Format(time.RFC3339), from.Format(time.RFC3339)), nil)
+	}
+	if step < 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "step must not be negative", nil)
+	}
+	return svc.metricCollector.history.query(from, to, step, time.Now()), nil
+}
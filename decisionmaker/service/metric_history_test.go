@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetricHistory tests the ring buffer, the retention and the downsampling of the metrics history
+func TestMetricHistory(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	history := newMetricHistory(time.Minute, 5)
+	for i := range 8 {
+		history.add(domain.MetricSample{
+			Timestamp:        start.Add(time.Duration(i) * 10 * time.Second),
+			NrQueued:         float64(i),
+			NrUserDispatches: uint64(i * 100),
+			DispatchRate:     float64(i * 10),
+		})
+	}
+
+	now := start.Add(70 * time.Second)
+	samples := history.query(time.Time{}, now, 0, now)
+	require.Len(t, samples, 5, "only the last max points should be kept")
+	assert.Equal(t, start.Add(30*time.Second), samples[0].Timestamp, "oldest kept sample")
+	assert.Equal(t, start.Add(70*time.Second), samples[4].Timestamp, "samples should be ordered oldest first")
+
+	samples = history.query(time.Time{}, now, 0, start.Add(110*time.Second))
+	require.Len(t, samples, 3, "samples older than the retention should be left out")
+	assert.Equal(t, start.Add(50*time.Second), samples[0].Timestamp, "oldest sample within the retention")
+
+	samples = history.query(start.Add(40*time.Second), start.Add(60*time.Second), 0, now)
+	assert.Len(t, samples, 3, "from and to should be inclusive")
+
+	samples = history.query(start.Add(30*time.Second), now, 20*time.Second, now)
+	require.Len(t, samples, 3, "samples should be merged into steps")
+	assert.Equal(t, start.Add(30*time.Second), samples[0].Timestamp, "steps should start at from")
+	assert.Equal(t, 3.5, samples[0].NrQueued, "gauges should be averaged")
+	assert.Equal(t, 35.0, samples[0].DispatchRate, "rates should be averaged")
+	assert.Equal(t, uint64(400), samples[0].NrUserDispatches, "counters should keep the last value")
+	assert.Equal(t, 7.0, samples[2].NrQueued, "a step with one sample should be kept as is")
+}
@@ -0,0 +1,123 @@
+//go:build linux
+
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Gthulhu/api/pkg/logger"
+	"golang.org/x/sys/unix"
+)
+
+// constants of the kernel proc connector, see include/uapi/linux/cn_proc.h and connector.h
+const (
+	cnIdxProc         = 0x1
+	cnValProc         = 0x1
+	procCnMcastListen = 0x1
+
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	nlMsgHdrLen = 16
+	cnMsgHdrLen = 20
+)
+
+// procConnectorRecvTimeout bounds every read of the proc connector socket so that the reader notices a cancelled
+// context, netlink sockets do not support shutdown
+const procConnectorRecvTimeout = 500 * time.Millisecond
+
+// subscribeProcEvents listens to the proc connector and signals whenever a process forks, execs or exits.
+// Signals are coalesced, the channel is closed once ctx is done or the socket fails.
+func subscribeProcEvents(ctx context.Context) (<-chan struct{}, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, fmt.Errorf("open proc connector socket: %w", err)
+	}
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc, Pid: uint32(os.Getpid())})
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("bind proc connector socket: %w", err)
+	}
+	timeout := unix.NsecToTimeval(procConnectorRecvTimeout.Nanoseconds())
+	err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout)
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("set proc connector socket timeout: %w", err)
+	}
+	err = unix.Sendto(fd, procConnectorListenMessage(), 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("subscribe proc connector: %w", err)
+	}
+
+	events := make(chan struct{}, 1)
+	go func() {
+		// the reader owns the socket, it is closed once ctx is done or the socket fails
+		defer close(events)
+		defer unix.Close(fd)
+		buf := make([]byte, os.Getpagesize())
+		for {
+			n, _, err := unix.Recvfrom(fd, buf, 0)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				if errors.Is(err, unix.EAGAIN) {
+					// read timed out, ctx is checked again on the next iteration
+					continue
+				}
+				if errors.Is(err, unix.EINTR) || errors.Is(err, unix.ENOBUFS) {
+					// ENOBUFS means events were dropped, a rescan picks them up anyway
+					notifyProcEvent(events)
+					continue
+				}
+				logger.Logger(ctx).Warn().Err(err).Msg("read proc connector socket")
+				return
+			}
+			if isProcLifecycleEvent(buf[:n]) {
+				notifyProcEvent(events)
+			}
+		}
+	}()
+	return events, nil
+}
+
+func notifyProcEvent(events chan<- struct{}) {
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
+
+// procConnectorListenMessage builds nlmsghdr + cn_msg + PROC_CN_MCAST_LISTEN
+func procConnectorListenMessage() []byte {
+	msg := make([]byte, nlMsgHdrLen+cnMsgHdrLen+4)
+	binary.NativeEndian.PutUint32(msg[0:], uint32(len(msg)))
+	binary.NativeEndian.PutUint16(msg[4:], unix.NLMSG_DONE)
+	binary.NativeEndian.PutUint32(msg[12:], uint32(os.Getpid()))
+	binary.NativeEndian.PutUint32(msg[16:], cnIdxProc)
+	binary.NativeEndian.PutUint32(msg[20:], cnValProc)
+	binary.NativeEndian.PutUint16(msg[32:], 4)
+	binary.NativeEndian.PutUint32(msg[36:], procCnMcastListen)
+	return msg
+}
+
+// isProcLifecycleEvent reports whether the netlink message carries a fork, exec or exit proc_event
+func isProcLifecycleEvent(msg []byte) bool {
+	if len(msg) < nlMsgHdrLen+cnMsgHdrLen+4 {
+		return false
+	}
+	what := binary.NativeEndian.Uint32(msg[nlMsgHdrLen+cnMsgHdrLen:])
+	switch what {
+	case procEventFork, procEventExec, procEventExit:
+		return true
+	default:
+		return false
+	}
+}
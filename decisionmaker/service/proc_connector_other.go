@@ -0,0 +1,13 @@
+//go:build !linux
+
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// subscribeProcEvents is only supported on linux
+func subscribeProcEvents(ctx context.Context) (<-chan struct{}, error) {
+	return nil, errors.New("proc connector is only supported on linux")
+}
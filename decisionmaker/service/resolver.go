@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+const (
+	defaultResolveInterval = 10 * time.Second
+	defaultResolveDebounce = 500 * time.Millisecond
+)
+
+// ResolveIntents expands every declared intent to the processes its pod currently runs,
+// covering processes started since the intent arrived and dropping the ones that exited.
+func (svc *Service) ResolveIntents(ctx context.Context) error {
+	podInfos, err := svc.GetAllPodInfos(ctx)
+	if err != nil {
+		return fmt.Errorf("scan pod processes: %w", err)
+	}
+	added, removed := svc.resolveIntents(podInfos)
+	if added > 0 || removed > 0 {
+		logger.Logger(ctx).Info().Msgf("resolved intents, %d processes added, %d exited processes removed", added, removed)
+	}
+	return nil
+}
+
+// resolveIntents syncs schedulingIntentsMap with the expansion of the intent set against podInfos
+// and returns how many entries were added and removed
+func (svc *Service) resolveIntents(podInfos map[string]*domain.PodInfo) (added int, removed int) {
+	svc.intentSet.mu.Lock()
+	defer svc.intentSet.mu.Unlock()
+
+	desired := make(map[string]*domain.SchedulingIntents)
+	for podID, intent := range svc.intentSet.intents {
+		schedulingIntents, _ := expandIntent(intent, podInfos[podID])
+		maps.Copy(desired, schedulingIntents)
+	}
+	return svc.syncSchedulingIntents(desired, func(string) bool { return true })
+}
+
+// RunIntentResolver resolves the intents periodically and, if enabled, after fork/exec/exit events
+// of the proc connector. Bursts of events are coalesced into a single pass. It blocks until ctx is done.
+func (svc *Service) RunIntentResolver(ctx context.Context) error {
+	interval := svc.resolverConfig.Interval
+	if interval <= 0 {
+		interval = defaultResolveInterval
+	}
+	debounce := svc.resolverConfig.Debounce
+	if debounce <= 0 {
+		debounce = defaultResolveDebounce
+	}
+
+	var events <-chan struct{}
+	if svc.resolverConfig.ProcConnector {
+		var err error
+		events, err = subscribeProcEvents(ctx)
+		if err != nil {
+			// periodic rescans still keep the intents up to date, just with more latency
+			logger.Logger(ctx).Warn().Err(err).Msg("subscribe proc connector events, falling back to periodic rescans")
+		}
+	}
+
+	resolve := func() {
+		if err := svc.ResolveIntents(ctx); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msg("resolve intents")
+		}
+	}
+
+	logger.Logger(ctx).Info().Msgf("starting intent resolver, interval:%s proc connector:%t", interval, events != nil)
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if !pending {
+				pending = true
+				timer.Reset(debounce)
+			}
+		case <-timer.C:
+			pending = false
+			resolve()
+			timer.Reset(interval)
+		}
+	}
+}
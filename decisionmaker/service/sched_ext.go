@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+const defaultSysfsRoot = "/sys"
+
+// schedExtReader reads the sched_ext state from sysfs under root, which is configurable so that
+// a fake tree can be used in tests or the host sysfs can be mounted elsewhere
+type schedExtReader struct {
+	root string
+}
+
+func newSchedExtReader(sysfsRoot string) schedExtReader {
+	if sysfsRoot == "" {
+		sysfsRoot = defaultSysfsRoot
+	}
+	return schedExtReader{root: sysfsRoot}
+}
+
+// read returns the sched_ext status, state is unsupported when the kernel has no sched_ext
+func (reader schedExtReader) read() (*domain.SchedExtStatus, error) {
+	dir := filepath.Join(reader.root, "kernel", "sched_ext")
+	state, err := readSysfsString(filepath.Join(dir, "state"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &domain.SchedExtStatus{State: domain.SchedExtStateUnsupported}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.SchedExtStatus{State: domain.SchedExtState(state)}
+	// the root directory only exists while a BPF scheduler is loaded
+	status.Ops, err = readSysfsString(filepath.Join(dir, "root", "ops"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	status.EnableSeq, err = readSysfsUint(filepath.Join(dir, "enable_seq"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	status.NrRejected, err = readSysfsUint(filepath.Join(dir, "nr_rejected"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	switchAll, err := readSysfsUint(filepath.Join(dir, "switch_all"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	status.SwitchAll = switchAll == 1
+	status.Events, err = readSchedExtEvents(filepath.Join(dir, "root", "events"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return status, nil
+}
+
+// readSchedExtEvents parses the "NAME value" lines of the events file of a scheduler
+func readSchedExtEvents(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		events[fields[0]] = value
+	}
+	return events, nil
+}
+
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readSysfsUint(path string) (uint64, error) {
+	value, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return n, nil
+}
+
+// SchedExtStatus reads which BPF scheduler is loaded on the node and the sched_ext counters
+func (svc *Service) SchedExtStatus(ctx context.Context) (*domain.SchedExtStatus, error) {
+	return svc.schedExt.read()
+}
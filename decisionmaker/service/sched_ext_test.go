@@ -0,0 +1,83 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSysfsFixture creates a fake sysfs tree holding the given files relative to kernel/sched_ext
+func writeSysfsFixture(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, "kernel", "sched_ext", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
+
+// TestSchedExtStatus tests reading the sched_ext state from sysfs
+func TestSchedExtStatus(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected *domain.SchedExtStatus
+	}{
+		{
+			name: "scheduler loaded",
+			files: map[string]string{
+				"state":       "enabled\n",
+				"enable_seq":  "3\n",
+				"switch_all":  "1\n",
+				"nr_rejected": "0\n",
+				"root/ops":    "gthulhu_1.0.0_x86_64\n",
+				"root/events": "SCX_EV_SELECT_CPU_FALLBACK 12\nSCX_EV_DISPATCH_KEEP_LAST 4\n",
+			},
+			expected: &domain.SchedExtStatus{
+				State:     domain.SchedExtStateEnabled,
+				Ops:       "gthulhu_1.0.0_x86_64",
+				EnableSeq: 3,
+				SwitchAll: true,
+				Events: map[string]uint64{
+					"SCX_EV_SELECT_CPU_FALLBACK": 12,
+					"SCX_EV_DISPATCH_KEEP_LAST":  4,
+				},
+			},
+		},
+		{
+			name: "fell back to the default scheduler",
+			files: map[string]string{
+				"state":       "disabled\n",
+				"enable_seq":  "4\n",
+				"switch_all":  "0\n",
+				"nr_rejected": "2\n",
+			},
+			expected: &domain.SchedExtStatus{
+				State:      domain.SchedExtStateDisabled,
+				EnableSeq:  4,
+				NrRejected: 2,
+			},
+		},
+		{
+			name:     "kernel without sched_ext",
+			files:    map[string]string{},
+			expected: &domain.SchedExtStatus{State: domain.SchedExtStateUnsupported},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := newSchedExtReader(writeSysfsFixture(t, tc.files)).read()
+			require.NoError(t, err, "read should not return error")
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+
+	_, err := newSchedExtReader(writeSysfsFixture(t, map[string]string{"state": "enabled", "enable_seq": "x"})).read()
+	assert.Error(t, err, "malformed counters should be reported")
+}
@@ -5,13 +5,20 @@ import (
 	"context"
 	"crypto/rsa"
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/manager/errs"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/Gthulhu/api/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,7 +27,12 @@ import (
 
 type Params struct {
 	fx.In
-	TokenConfig config.TokenConfig
+	TokenConfig    config.TokenConfig
+	StoreConfig    config.StoreConfig
+	ResolverConfig config.ResolverConfig
+	MetricsConfig  config.MetricsConfig
+	WatchdogConfig config.WatchdogConfig
+	SchedExtConfig config.SchedExtConfig
 }
 
 func NewService(params Params) (Service, error) {
@@ -28,10 +40,25 @@ func NewService(params Params) (Service, error) {
 	if err != nil {
 		return Service{}, fmt.Errorf("failed to initialize JWT private key: %v", err)
 	}
+	watchdog := newSchedulerWatchdog(params.WatchdogConfig, time.Now())
+	metricCollector := NewMetricCollector(util.GetMachineID(), params.MetricsConfig)
+	schedExt := newSchedExtReader(params.SchedExtConfig.SysfsRoot)
+	metricCollector.watchdog = watchdog
+	metricCollector.schedExt = &schedExt
 	svc := Service{
 		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
-		metricCollector:      NewMetricCollector(util.GetMachineID()),
+		intentSet:            newIntentSet(),
+		intentWatchers:       newIntentWatchHub(),
+		metricCollector:      metricCollector,
+		watchdog:             watchdog,
+		watchdogConfig:       params.WatchdogConfig,
+		schedExt:             schedExt,
 		jwtPrivateKey:        privateKey,
+		resolverConfig:       params.ResolverConfig,
+		cgroupParsers:        DefaultCgroupParsers(),
+	}
+	if params.StoreConfig.Enabled {
+		svc.storePath = params.StoreConfig.Path
 	}
 
 	err = prometheus.Register(svc.metricCollector)
@@ -43,11 +70,45 @@ func NewService(params Params) (Service, error) {
 
 type Service struct {
 	schedulingIntentsMap *util.GenericMap[string, []*domain.SchedulingIntents]
+	intentSet            *intentSet
+	intentWatchers       *intentWatchHub
+	storePath            string
+	resolverConfig       config.ResolverConfig
+	cgroupParsers        []CgroupParser
 	metricCollector      *MetricCollector
+	watchdog             *schedulerWatchdog
+	watchdogConfig       config.WatchdogConfig
+	schedExt             schedExtReader
 	jwtPrivateKey        *rsa.PrivateKey
 	tokenConfig          config.TokenConfig
 }
 
+// intentSet holds the intents declared for this node, keyed by pod ID, and the generation of the last full sync.
+// mu also guards schedulingIntentsMap against partially applied updates.
+type intentSet struct {
+	mu         sync.RWMutex
+	generation int64
+	intents    map[string]*domain.Intent
+}
+
+func newIntentSet() *intentSet {
+	return &intentSet{
+		intents: make(map[string]*domain.Intent),
+	}
+}
+
+// list returns the declared intents ordered by pod ID, the caller must hold mu
+func (set *intentSet) list() []*domain.Intent {
+	intents := make([]*domain.Intent, 0, len(set.intents))
+	for _, intent := range set.intents {
+		intents = append(intents, intent)
+	}
+	sort.Slice(intents, func(i, j int) bool {
+		return intents[i].PodID < intents[j].PodID
+	})
+	return intents
+}
+
 const (
 	procDir      = "/proc"
 	pauseCommand = "pause"
@@ -55,6 +116,9 @@ const (
 
 // ListAllSchedulingIntents retrieves all stored scheduling intents
 func (svc *Service) ListAllSchedulingIntents(ctx context.Context) ([]*domain.SchedulingIntents, error) {
+	svc.intentSet.mu.RLock()
+	defer svc.intentSet.mu.RUnlock()
+
 	intents := []*domain.SchedulingIntents{}
 	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
 		intents = append(intents, value...)
@@ -63,43 +127,272 @@ func (svc *Service) ListAllSchedulingIntents(ctx context.Context) ([]*domain.Sch
 	return intents, nil
 }
 
-// ProcessIntents processes a list of scheduling intents and updates the internal map
-func (svc *Service) ProcessIntents(ctx context.Context, intents []*domain.Intent) error {
+// ProcessIntents processes a list of scheduling intents, updates the internal map and reports the result of every intent
+func (svc *Service) ProcessIntents(ctx context.Context, intents []*domain.Intent) ([]*domain.IntentResult, error) {
+	err := validateIntents(intents)
+	if err != nil {
+		return nil, err
+	}
 	podInfos, err := svc.GetAllPodInfos(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	svc.intentSet.mu.Lock()
+	defer svc.intentSet.mu.Unlock()
+
+	results := make([]*domain.IntentResult, 0, len(intents))
 	for _, intent := range intents {
-		podInfo := podInfos[intent.PodID]
-		logger.Logger(ctx).Info().Msgf("Processing intent for PodName:%s PodID: %s on NodeID: %s, Process:%+v", intent.PodName, intent.PodID, intent.NodeID, podInfo)
-		labels := []domain.LabelSelector{}
-		for key, value := range intent.PodLabels {
-			labels = append(labels, domain.LabelSelector{
-				Key:   key,
-				Value: value,
-			})
+		svc.intentSet.intents[intent.PodID] = intent
+		result, schedulingIntents := evaluateIntent(ctx, intent, podInfos[intent.PodID])
+		prefix := intent.PodID + "-"
+		svc.syncSchedulingIntents(schedulingIntents, func(key string) bool {
+			return strings.HasPrefix(key, prefix)
+		})
+		results = append(results, result)
+	}
+	svc.persistIntentSet(ctx)
+	logger.Logger(ctx).Info().Msgf("Discovered pods: %+v", podInfos)
+	return results, nil
+}
+
+// SyncIntents replaces the complete intent set of this node with the given intents of the given generation
+func (svc *Service) SyncIntents(ctx context.Context, generation int64, intents []*domain.Intent) ([]*domain.IntentResult, error) {
+	err := validateIntents(intents)
+	if err != nil {
+		return nil, err
+	}
+	podInfos, err := svc.GetAllPodInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.replaceIntents(ctx, generation, intents, podInfos)
+}
+
+// replaceIntents atomically swaps the intent set for the given one, readers never observe a partially applied set
+func (svc *Service) replaceIntents(ctx context.Context, generation int64, intents []*domain.Intent, podInfos map[string]*domain.PodInfo) ([]*domain.IntentResult, error) {
+	svc.intentSet.mu.Lock()
+	defer svc.intentSet.mu.Unlock()
+
+	if generation < svc.intentSet.generation {
+		return nil, errs.NewHTTPStatusError(http.StatusConflict,
+			fmt.Sprintf("generation %d is older than the current generation %d", generation, svc.intentSet.generation), nil)
+	}
+
+	svc.intentSet.intents = make(map[string]*domain.Intent, len(intents))
+	desired := make(map[string]*domain.SchedulingIntents)
+	results := make([]*domain.IntentResult, 0, len(intents))
+	for _, intent := range intents {
+		svc.intentSet.intents[intent.PodID] = intent
+		result, schedulingIntents := evaluateIntent(ctx, intent, podInfos[intent.PodID])
+		maps.Copy(desired, schedulingIntents)
+		results = append(results, result)
+	}
+	svc.syncSchedulingIntents(desired, func(string) bool { return true })
+	svc.intentSet.generation = generation
+	svc.persistIntentSet(ctx)
+	logger.Logger(ctx).Info().Msgf("Synced %d intents of generation %d", len(intents), generation)
+	return results, nil
+}
+
+// GetIntentSet returns the current generation and the intents declared for this node
+func (svc *Service) GetIntentSet(ctx context.Context) (*domain.IntentSet, error) {
+	svc.intentSet.mu.RLock()
+	defer svc.intentSet.mu.RUnlock()
+
+	set := &domain.IntentSet{
+		Generation: svc.intentSet.generation,
+		Intents:    svc.intentSet.list(),
+	}
+	return set, nil
+}
+
+// evaluateIntent expands the intent to the processes of the pod and reports the outcome,
+// it returns the scheduling intents to store keyed by schedulingIntentKey
+func evaluateIntent(ctx context.Context, intent *domain.Intent, podInfo *domain.PodInfo) (*domain.IntentResult, map[string]*domain.SchedulingIntents) {
+	logger.Logger(ctx).Info().Msgf("Processing intent for PodName:%s PodID: %s on NodeID: %s, Process:%+v", intent.PodName, intent.PodID, intent.NodeID, podInfo)
+	result := &domain.IntentResult{
+		IntentID: intent.ID,
+		PodID:    intent.PodID,
+	}
+	schedulingIntents, processCount := expandIntent(intent, podInfo)
+	if podInfo == nil {
+		result.Status = domain.IntentResultPodNotFound
+		result.Reason = fmt.Sprintf("no process of pod %s found in %s", intent.PodID, procDir)
+		return result, schedulingIntents
+	}
+
+	result.ProcessCount = processCount
+	for _, schedulingIntent := range schedulingIntents {
+		logger.Logger(ctx).Info().Msgf("Created SchedulingIntent: %+v for Process PID: %d", schedulingIntent, schedulingIntent.PID)
+		result.PIDCount++
+	}
+	if result.PIDCount == 0 {
+		result.Status = domain.IntentResultFailed
+		result.Reason = fmt.Sprintf("pod %s has no schedulable process", intent.PodID)
+		if intent.CommandRegex != "" && processCount > 0 {
+			result.Reason = fmt.Sprintf("none of the %d processes of pod %s matches command regex %q", processCount, intent.PodID, intent.CommandRegex)
 		}
-		if podInfo != nil && len(podInfo.Processes) > 0 {
-			for _, process := range podInfo.Processes {
-				if process.Command == pauseCommand {
-					continue
-				}
-				schedulingIntent := &domain.SchedulingIntents{
-					Priority:      intent.Priority > 0,
-					ExecutionTime: uint64(intent.ExecutionTime),
-					PID:           process.PID,
-					CommandRegex:  intent.CommandRegex,
-					Selectors:     labels,
-				}
-				logger.Logger(ctx).Info().Msgf("Created SchedulingIntent: %+v for Process PID: %d", schedulingIntent, process.PID)
-				svc.schedulingIntentsMap.Store(fmt.Sprintf("%s-%d", intent.PodID, process.PID), []*domain.SchedulingIntents{schedulingIntent})
-			}
+		return result, schedulingIntents
+	}
+	result.Status = domain.IntentResultApplied
+	return result, schedulingIntents
+}
+
+// expandIntent builds the scheduling intents of every schedulable process of the pod matching the command regex
+// of the intent, keyed by schedulingIntentKey, and returns how many schedulable processes the pod has
+func expandIntent(intent *domain.Intent, podInfo *domain.PodInfo) (map[string]*domain.SchedulingIntents, int) {
+	schedulingIntents := make(map[string]*domain.SchedulingIntents)
+	if podInfo == nil {
+		return schedulingIntents, 0
+	}
+	var cmdRegex *regexp.Regexp
+	if intent.CommandRegex != "" {
+		re, err := regexp.Compile(intent.CommandRegex)
+		if err != nil {
+			// rejected when the intent was received, only a hand edited snapshot gets here
+			return schedulingIntents, 0
+		}
+		cmdRegex = re
+	}
+	labels := []domain.LabelSelector{}
+	for _, key := range slices.Sorted(maps.Keys(intent.PodLabels)) {
+		labels = append(labels, domain.LabelSelector{
+			Key:   key,
+			Value: intent.PodLabels[key],
+		})
+	}
+	processCount := 0
+	for _, process := range podInfo.Processes {
+		if process.Command == pauseCommand {
+			continue
+		}
+		processCount++
+		if cmdRegex != nil && !processMatches(cmdRegex, process) {
+			continue
+		}
+		schedulingIntents[schedulingIntentKey(intent.PodID, process.PID)] = &domain.SchedulingIntents{
+			Priority:      intent.Priority > 0,
+			ExecutionTime: uint64(intent.ExecutionTime),
+			PID:           process.PID,
+			CommandRegex:  intent.CommandRegex,
+			Selectors:     labels,
+		}
+	}
+	return schedulingIntents, processCount
+}
+
+// processMatches reports whether the regex matches the comm name or the full command line of the process
+func processMatches(cmdRegex *regexp.Regexp, process domain.PodProcess) bool {
+	return cmdRegex.MatchString(process.Command) || (process.Cmdline != "" && cmdRegex.MatchString(process.Cmdline))
+}
+
+// validateIntents rejects intents whose command regex does not compile
+func validateIntents(intents []*domain.Intent) error {
+	for _, intent := range intents {
+		if intent.CommandRegex == "" {
+			continue
+		}
+		_, err := regexp.Compile(intent.CommandRegex)
+		if err != nil {
+			return errs.NewHTTPStatusError(http.StatusBadRequest,
+				fmt.Sprintf("invalid command regex %q of pod %s", intent.CommandRegex, intent.PodID), err)
 		}
 	}
-	logger.Logger(ctx).Info().Msgf("Discovered pods: %+v", podInfos)
 	return nil
 }
 
+func schedulingIntentKey(podID string, pid int) string {
+	return fmt.Sprintf("%s-%d", podID, pid)
+}
+
+// RemoveIntents drops the scheduling intents of every process belonging to the given pods
+// and returns how many scheduling intents were removed
+func (svc *Service) RemoveIntents(ctx context.Context, podIDs []string) (int, error) {
+	svc.intentSet.mu.Lock()
+	defer svc.intentSet.mu.Unlock()
+
+	removed := 0
+	for _, podID := range podIDs {
+		delete(svc.intentSet.intents, podID)
+		n := svc.deletePodSchedulingIntents(podID)
+		logger.Logger(ctx).Info().Msgf("Removed %d scheduling intents of PodID: %s", n, podID)
+		removed += n
+	}
+	svc.persistIntentSet(ctx)
+	return removed, nil
+}
+
+// deletePodSchedulingIntents removes all entries keyed by "<podID>-<pid>" for the given pod
+func (svc *Service) deletePodSchedulingIntents(podID string) int {
+	prefix := podID + "-"
+	removed := 0
+	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
+		if strings.HasPrefix(key, prefix) && svc.deleteSchedulingIntent(key) {
+			removed++
+		}
+		return true
+	})
+	return removed
+}
+
+// ListPodProcesses returns the pods discovered in /proc with their processes and the intents applied to each PID
+func (svc *Service) ListPodProcesses(ctx context.Context, filter domain.PodProcessFilter) ([]*domain.PodProcesses, error) {
+	podInfos, err := svc.GetAllPodInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.podProcessesFrom(podInfos, filter), nil
+}
+
+func (svc *Service) podProcessesFrom(podInfos map[string]*domain.PodInfo, filter domain.PodProcessFilter) []*domain.PodProcesses {
+	svc.intentSet.mu.RLock()
+	defer svc.intentSet.mu.RUnlock()
+
+	pods := make([]*domain.PodProcesses, 0, len(podInfos))
+	for podUID, podInfo := range podInfos {
+		if len(filter.PodUIDs) > 0 && !slices.Contains(filter.PodUIDs, podUID) {
+			continue
+		}
+		pod := &domain.PodProcesses{
+			PodUID: podUID,
+			Intent: svc.intentSet.intents[podUID],
+		}
+		for _, process := range podInfo.Processes {
+			if len(filter.ContainerIDs) > 0 && !containerIDMatches(filter.ContainerIDs, process.ContainerID) {
+				continue
+			}
+			schedulingIntents, _ := svc.schedulingIntentsMap.Load(schedulingIntentKey(podUID, process.PID))
+			pod.Processes = append(pod.Processes, domain.ProcessIntents{
+				PodProcess:        process,
+				SchedulingIntents: schedulingIntents,
+			})
+		}
+		if len(pod.Processes) == 0 {
+			continue
+		}
+		sort.Slice(pod.Processes, func(i, j int) bool {
+			return pod.Processes[i].PID < pod.Processes[j].PID
+		})
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].PodUID < pods[j].PodUID
+	})
+	return pods
+}
+
+func containerIDMatches(containerIDs []string, containerID string) bool {
+	if containerID == "" {
+		return false
+	}
+	for _, id := range containerIDs {
+		if strings.HasPrefix(containerID, id) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAllPodInfos retrieves all pod information by scanning the /proc filesystem
 func (svc *Service) GetAllPodInfos(ctx context.Context) (map[string]*domain.PodInfo, error) {
 	return svc.FindPodInfoFrom(ctx, procDir)
@@ -138,15 +431,18 @@ func (svc *Service) FindPodInfoFrom(ctx context.Context, rootDir string) (map[st
 		for scanner.Scan() {
 			line := scanner.Text()
 			logger.Logger(ctx).Debug().Msgf("cgroup line for pid %d: %s", pid, line)
-			if strings.Contains(line, "kubepods") {
-				err = svc.parseCgroupToPodInfo(rootDir, line, pid, podMap)
-				if err != nil {
-					logger.Logger(ctx).Warn().Err(err).Msgf("failed to parse cgroup line for pid %d, line:%s", pid, line)
-					break
-				}
+			// cgroup v1 lists one line per hierarchy, the first one placing the process in a pod is enough
+			found, err := svc.parseCgroupToPodInfo(rootDir, line, pid, podMap)
+			if err != nil {
+				logger.Logger(ctx).Warn().Err(err).Msgf("failed to parse cgroup line for pid %d, line:%s", pid, line)
+				break
+			}
+			if found {
+				break
 			}
 		}
 		if err := scanner.Err(); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("failed to read cgroup file for pid %d", pid)
 		}
 		_ = file.Close()
 	}
@@ -154,63 +450,52 @@ func (svc *Service) FindPodInfoFrom(ctx context.Context, rootDir string) (map[st
 	return podMap, nil
 }
 
-// parseCgroupToPodInfo parses a cgroup line (e.g // 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope) to extract pod info and updates the podInfoMap
-func (svc *Service) parseCgroupToPodInfo(rootDir string, line string, pid int, podInfoMap map[string]*domain.PodInfo) error {
-	parts := strings.Split(line, ":")
-	if len(parts) >= 3 {
-		cgroupHierarchy := parts[2]
+// parseCgroupToPodInfo parses a cgroup line (e.g // 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope)
+// to extract pod info and updates the podInfoMap, found reports whether the line belongs to a pod
+func (svc *Service) parseCgroupToPodInfo(rootDir string, line string, pid int, podInfoMap map[string]*domain.PodInfo) (found bool, err error) {
+	cgroupPath, ok := parseCgroupLine(line)
+	if !ok {
+		return false, nil
+	}
 
-		// Extract pod information
-		podUID, containerID, err := svc.getPodInfoFromCgroup(cgroupHierarchy)
-		if err != nil {
-			return err
-		}
+	// Extract pod information
+	podUID, containerID, ok := svc.getPodInfoFromCgroup(cgroupPath)
+	if !ok {
+		return false, nil
+	}
 
-		// Get process information
-		process, err := svc.getProcessInfo(rootDir, pid)
-		if err != nil {
-			return err
-		}
-		process.ContainerID = containerID
+	// Get process information
+	process, err := svc.getProcessInfo(rootDir, pid)
+	if err != nil {
+		return false, err
+	}
+	process.ContainerID = containerID
 
-		// Create or update pod info
-		if podInfo, exists := podInfoMap[podUID]; exists {
-			podInfo.Processes = append(podInfo.Processes, process)
-		} else {
-			podInfoMap[podUID] = &domain.PodInfo{
-				PodUID:    podUID,
-				Processes: []domain.PodProcess{process},
-			}
+	// Create or update pod info
+	if podInfo, exists := podInfoMap[podUID]; exists {
+		podInfo.Processes = append(podInfo.Processes, process)
+	} else {
+		podInfoMap[podUID] = &domain.PodInfo{
+			PodUID:    podUID,
+			Processes: []domain.PodProcess{process},
 		}
 	}
-	return nil
+	return true, nil
 }
 
-var (
-	podRegex = regexp.MustCompile(`pod([0-9a-fA-F_]+)(?:\.slice)?`)
-)
-
-// getPodInfoFromCgroup extracts pod information from cgroup path
-func (svc *Service) getPodInfoFromCgroup(cgroupPath string) (podUID string, containerID string, err error) {
-	// Parse cgroup path to extract pod information
-	// 0::/kubelet.slice/kubelet-kubepods.slice/kubelet-kubepods-pod20da609e_6973_4463_a1f9_2db9bcc5becc.slice/cri-containerd-10ec3c89629f71226b227e6510b2d465168b24005bbdcc5d7940517080830635.scope
-	parts := strings.Split(cgroupPath, "/")
-	for _, part := range parts {
-		if podRegex.MatchString(part) {
-			podUID = podRegex.FindStringSubmatch(part)[1]
-			podUID = strings.ReplaceAll(podUID, "_", "-")
-		}
-		if strings.HasPrefix(part, "cri-containerd-") && strings.HasSuffix(part, ".scope") {
-			containerID = strings.TrimPrefix(part, "cri-containerd-")
-			containerID = strings.TrimSuffix(containerID, ".scope")
-		}
+// getPodInfoFromCgroup extracts pod information from cgroup path with the first cgroup parser understanding it
+func (svc *Service) getPodInfoFromCgroup(cgroupPath string) (podUID string, containerID string, ok bool) {
+	parsers := svc.cgroupParsers
+	if len(parsers) == 0 {
+		parsers = DefaultCgroupParsers()
 	}
-
-	if podUID == "" {
-		return "", "", fmt.Errorf("pod UID not found in cgroup path")
+	for _, parser := range parsers {
+		podUID, containerID, ok = parser.Parse(cgroupPath)
+		if ok {
+			return podUID, containerID, true
+		}
 	}
-
-	return podUID, containerID, nil
+	return "", "", false
 }
 
 // getProcessInfo reads process information from /proc/<pid>/
@@ -223,6 +508,12 @@ func (svc *Service) getProcessInfo(rootDir string, pid int) (domain.PodProcess,
 		process.Command = strings.TrimSpace(string(data))
 	}
 
+	// Read the full command line from /proc/<pid>/cmdline, arguments are NUL separated
+	cmdlinePath := fmt.Sprintf("/%s/%d/cmdline", rootDir, pid)
+	if data, err := os.ReadFile(cmdlinePath); err == nil {
+		process.Cmdline = strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+	}
+
 	// Read PPID from /proc/<pid>/stat
 	statPath := fmt.Sprintf("/%s/%d/stat", rootDir, pid)
 	if data, err := os.ReadFile(statPath); err == nil {
@@ -239,4 +530,6 @@ func (svc *Service) getProcessInfo(rootDir string, pid int) (domain.PodProcess,
 
 func (svc *Service) UpdateMetrics(ctx context.Context, newMetricSet *domain.MetricSet) {
 	svc.metricCollector.UpdateMetrics(newMetricSet)
+	svc.watchdog.observe(newMetricSet, time.Now())
+	svc.updateTaskMetrics(newMetricSet.Tasks)
 }
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/manager/errs"
 	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +35,11 @@ func setupFakeProcDir(t *testing.T) string {
 		t.Fatal(err)
 	}
 
+	// cmdline
+	if err := os.WriteFile(filepath.Join(pidDir, "cmdline"), []byte("nginx: master process\x00-g\x00daemon off;\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	// stat
 	statLine := "1234 (nginx) S 1 2 3 4 5 0 0 0 0 0 0 0 0 0 0 0 0 0 0"
 	if err := os.WriteFile(filepath.Join(pidDir, "stat"), []byte(statLine), 0644); err != nil {
@@ -85,3 +94,345 @@ func TestFindPodInfoFrom(t *testing.T) {
 	require.Len(t, p2.Processes, 1, "should have one process")
 	assert.EqualValues(t, p2.Processes[0].Command, "busybox", "unexpected command")
 }
+
+// TestReplaceIntents tests that a full sync replaces the intent set and rejects stale generations
+func TestReplaceIntents(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	fakeProc := setupFakeProcDir(t)
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, fakeProc)
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+
+	nginxPod := &domain.Intent{ID: "intent-1", PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", ExecutionTime: 100}
+	busyboxPod := &domain.Intent{ID: "intent-2", PodID: "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413", ExecutionTime: 200}
+	missingPod := &domain.Intent{ID: "intent-3", PodID: "missing-pod"}
+
+	results, err := svc.replaceIntents(ctx, 1, []*domain.Intent{nginxPod, busyboxPod, missingPod}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	require.Len(t, results, 3, "should report one result per intent")
+	assert.Equal(t, domain.IntentResultApplied, results[0].Status, "unexpected status")
+	assert.Equal(t, 1, results[0].PIDCount, "unexpected pid count")
+	assert.Equal(t, domain.IntentResultPodNotFound, results[2].Status, "unexpected status")
+
+	schedulingIntents, err := svc.ListAllSchedulingIntents(ctx)
+	require.NoError(t, err)
+	require.Len(t, schedulingIntents, 2, "should hold the processes of both pods")
+
+	// a newer generation drops intents that are not part of it
+	_, err = svc.replaceIntents(ctx, 2, []*domain.Intent{busyboxPod}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	schedulingIntents, err = svc.ListAllSchedulingIntents(ctx)
+	require.NoError(t, err)
+	require.Len(t, schedulingIntents, 1, "should only hold the busybox process")
+	assert.Equal(t, 5678, schedulingIntents[0].PID, "unexpected pid")
+
+	set, err := svc.GetIntentSet(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, set.Generation, "unexpected generation")
+	require.Len(t, set.Intents, 1, "unexpected intent set")
+	assert.Equal(t, busyboxPod.PodID, set.Intents[0].PodID, "unexpected intent")
+
+	// an older generation is rejected and leaves the set untouched
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{nginxPod}, podInfos)
+	httpErr, ok := errs.IsHTTPStatusError(err)
+	require.True(t, ok, "should return an http status error")
+	assert.Equal(t, http.StatusConflict, httpErr.StatusCode, "unexpected status code")
+	set, err = svc.GetIntentSet(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, set.Generation, "generation should not change")
+}
+
+// TestIntentSetSnapshot tests that a persisted intent set is reloaded and re-resolved against the current processes
+func TestIntentSetSnapshot(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	storePath := filepath.Join(t.TempDir(), "state", "intents.json")
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+		storePath:            storePath,
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+
+	intent := &domain.Intent{ID: "intent-1", PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", ExecutionTime: 100}
+	_, err = svc.replaceIntents(ctx, 7, []*domain.Intent{intent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	require.FileExists(t, storePath, "snapshot should be written")
+
+	// the restarted service only knows the snapshot, the pod's process got a new PID meanwhile
+	restarted := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+		storePath:            storePath,
+	}
+	set, err := restarted.loadIntentSet()
+	require.NoError(t, err, "loadIntentSet should not return error")
+	require.NotNil(t, set, "snapshot should be loaded")
+	assert.EqualValues(t, 7, set.Generation, "unexpected generation")
+	require.Len(t, set.Intents, 1, "unexpected intents")
+	assert.Equal(t, intent.ID, set.Intents[0].ID, "unexpected intent")
+
+	podInfos[intent.PodID].Processes[0].PID = 4321
+	results, err := restarted.replaceIntents(ctx, set.Generation, set.Intents, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	require.Len(t, results, 1)
+	assert.Equal(t, domain.IntentResultApplied, results[0].Status, "unexpected status")
+	schedulingIntents, err := restarted.ListAllSchedulingIntents(ctx)
+	require.NoError(t, err)
+	require.Len(t, schedulingIntents, 1)
+	assert.Equal(t, 4321, schedulingIntents[0].PID, "PID should be re-resolved")
+
+	// no snapshot yields an empty set
+	empty := &Service{storePath: filepath.Join(t.TempDir(), "missing.json")}
+	set, err = empty.loadIntentSet()
+	require.NoError(t, err)
+	assert.Nil(t, set)
+}
+
+// TestResolveIntents tests that pod-level intents follow the processes of their pods
+func TestResolveIntents(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+
+	podID := "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	missingPodID := "3c8e1f0a-1111-2222-3333-444455556666"
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{
+		{ID: "intent-1", PodID: podID, ExecutionTime: 100},
+		{ID: "intent-2", PodID: missingPodID, ExecutionTime: 100},
+	}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+
+	// nothing changed
+	added, removed := svc.resolveIntents(podInfos)
+	assert.Zero(t, added, "unexpected added processes")
+	assert.Zero(t, removed, "unexpected removed processes")
+
+	// the original process exited, a worker was forked and the missing pod started
+	podInfos[podID].Processes = []domain.PodProcess{{PID: 2001, Command: "nginx"}, {PID: 2002, Command: "nginx"}}
+	podInfos[missingPodID] = &domain.PodInfo{PodUID: missingPodID, Processes: []domain.PodProcess{{PID: 3001, Command: "redis"}}}
+	added, removed = svc.resolveIntents(podInfos)
+	assert.Equal(t, 3, added, "unexpected added processes")
+	assert.Equal(t, 1, removed, "unexpected removed processes")
+
+	schedulingIntents, err := svc.ListAllSchedulingIntents(ctx)
+	require.NoError(t, err)
+	pids := []int{}
+	for _, schedulingIntent := range schedulingIntents {
+		pids = append(pids, schedulingIntent.PID)
+	}
+	assert.ElementsMatch(t, []int{2001, 2002, 3001}, pids, "unexpected pids")
+
+	// the whole pod is gone
+	delete(podInfos, podID)
+	added, removed = svc.resolveIntents(podInfos)
+	assert.Zero(t, added, "unexpected added processes")
+	assert.Equal(t, 2, removed, "unexpected removed processes")
+}
+
+// TestIntentCommandRegex tests that intents only cover processes whose comm or cmdline matches the command regex
+func TestIntentCommandRegex(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	nginxPodID := "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	busyboxPodID := "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413"
+	require.Equal(t, "nginx: master process -g daemon off;", podInfos[nginxPodID].Processes[0].Cmdline, "unexpected cmdline")
+
+	results, err := svc.replaceIntents(ctx, 1, []*domain.Intent{
+		{ID: "cmdline", PodID: nginxPodID, CommandRegex: "daemon off"},
+		{ID: "comm", PodID: busyboxPodID, CommandRegex: "^busy"},
+	}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	for _, result := range results {
+		assert.Equal(t, domain.IntentResultApplied, result.Status, "unexpected status of %s", result.IntentID)
+		assert.Equal(t, 1, result.PIDCount, "unexpected pid count of %s", result.IntentID)
+		assert.Equal(t, 1, result.ProcessCount, "unexpected process count of %s", result.IntentID)
+	}
+
+	results, err = svc.replaceIntents(ctx, 2, []*domain.Intent{
+		{ID: "none", PodID: nginxPodID, CommandRegex: "^redis"},
+	}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	require.Len(t, results, 1)
+	assert.Equal(t, domain.IntentResultFailed, results[0].Status, "unexpected status")
+	assert.Zero(t, results[0].PIDCount, "unexpected pid count")
+	assert.Equal(t, 1, results[0].ProcessCount, "unexpected process count")
+	schedulingIntents, err := svc.ListAllSchedulingIntents(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, schedulingIntents, "no process should be covered")
+
+	_, err = svc.ProcessIntents(ctx, []*domain.Intent{{PodID: nginxPodID, CommandRegex: "nginx("}})
+	httpErr, ok := errs.IsHTTPStatusError(err)
+	require.True(t, ok, "should return an http status error")
+	assert.Equal(t, http.StatusBadRequest, httpErr.StatusCode, "unexpected status code")
+}
+
+// TestPodProcesses tests listing discovered pods with the intents applied to their processes
+func TestPodProcesses(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	nginxPodID := "20da609e-6973-4463-a1f9-2db9bcc5becc"
+	busyboxPodID := "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413"
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{{ID: "intent-1", PodID: nginxPodID, ExecutionTime: 100}}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+
+	pods := svc.podProcessesFrom(podInfos, domain.PodProcessFilter{})
+	require.Len(t, pods, 2, "should list both pods")
+	assert.Equal(t, nginxPodID, pods[0].PodUID, "pods should be ordered by UID")
+	require.NotNil(t, pods[0].Intent, "nginx pod should have an intent")
+	require.Len(t, pods[0].Processes, 1)
+	assert.Equal(t, 1234, pods[0].Processes[0].PID, "unexpected pid")
+	assert.Equal(t, 1, pods[0].Processes[0].PPID, "unexpected ppid")
+	require.Len(t, pods[0].Processes[0].SchedulingIntents, 1, "intent should apply to the nginx process")
+	assert.EqualValues(t, 100, pods[0].Processes[0].SchedulingIntents[0].ExecutionTime, "unexpected execution time")
+	assert.Nil(t, pods[1].Intent, "busybox pod should not have an intent")
+	assert.Empty(t, pods[1].Processes[0].SchedulingIntents, "no intent should apply to the busybox process")
+
+	pods = svc.podProcessesFrom(podInfos, domain.PodProcessFilter{PodUIDs: []string{busyboxPodID}})
+	require.Len(t, pods, 1, "should filter by pod UID")
+	assert.Equal(t, busyboxPodID, pods[0].PodUID, "unexpected pod")
+
+	pods = svc.podProcessesFrom(podInfos, domain.PodProcessFilter{ContainerIDs: []string{"10ec3c89629f"}})
+	require.Len(t, pods, 1, "should filter by container ID prefix")
+	assert.Equal(t, nginxPodID, pods[0].PodUID, "unexpected pod")
+
+	pods = svc.podProcessesFrom(podInfos, domain.PodProcessFilter{ContainerIDs: []string{"deadbeef"}})
+	assert.Empty(t, pods, "should not match unknown containers")
+}
+
+// TestWatchSchedulingIntents tests that watchers receive a snapshot, every later change and can resume
+func TestWatchSchedulingIntents(t *testing.T) {
+	logger.InitLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+		intentWatchers:       newIntentWatchHub(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	nginxIntent := &domain.Intent{ID: "intent-1", PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", ExecutionTime: 100}
+	busyboxIntent := &domain.Intent{ID: "intent-2", PodID: "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413", ExecutionTime: 100}
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{nginxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+
+	initial, events, err := svc.WatchSchedulingIntents(ctx, 0)
+	require.NoError(t, err, "WatchSchedulingIntents should not return error")
+	require.Len(t, initial, 2, "should receive the snapshot and a bookmark")
+	assert.Equal(t, domain.SchedulingIntentAdded, initial[0].Type, "unexpected event type")
+	assert.Equal(t, 1234, initial[0].Intent.PID, "unexpected pid")
+	assert.Equal(t, domain.SchedulingIntentBookmark, initial[1].Type, "unexpected event type")
+	snapshotVersion := initial[1].ResourceVersion
+
+	// replacing nginx by busybox deletes one intent and adds another one, unchanged intents stay silent
+	_, err = svc.replaceIntents(ctx, 2, []*domain.Intent{busyboxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	_, err = svc.replaceIntents(ctx, 3, []*domain.Intent{busyboxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	received := map[domain.SchedulingIntentEventType]int{}
+	for range 2 {
+		event := <-events
+		assert.Greater(t, event.ResourceVersion, snapshotVersion, "resource version should increase")
+		received[event.Type] = event.Intent.PID
+	}
+	assert.Equal(t, map[domain.SchedulingIntentEventType]int{
+		domain.SchedulingIntentDeleted: 1234,
+		domain.SchedulingIntentAdded:   5678,
+	}, received, "unexpected events")
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+
+	modified := *busyboxIntent
+	modified.ExecutionTime = 500
+	_, err = svc.replaceIntents(ctx, 4, []*domain.Intent{&modified}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	event := <-events
+	assert.Equal(t, domain.SchedulingIntentModified, event.Type, "unexpected event type")
+	assert.EqualValues(t, 500, event.Intent.ExecutionTime, "unexpected execution time")
+
+	// a resumed watch replays the changes made after its resource version
+	resumed, _, err := svc.WatchSchedulingIntents(ctx, snapshotVersion)
+	require.NoError(t, err, "WatchSchedulingIntents should not return error")
+	require.Len(t, resumed, 4, "should replay three changes and a bookmark")
+	assert.Equal(t, domain.SchedulingIntentModified, resumed[2].Type, "unexpected event type")
+	assert.Equal(t, event.ResourceVersion, resumed[3].ResourceVersion, "bookmark should carry the current resource version")
+
+	_, _, err = svc.WatchSchedulingIntents(ctx, event.ResourceVersion+10)
+	httpErr, ok := errs.IsHTTPStatusError(err)
+	require.True(t, ok, "should return an http status error")
+	assert.Equal(t, http.StatusGone, httpErr.StatusCode, "unexpected status code")
+
+	cancel()
+	for range events {
+	}
+}
+
+// TestListSchedulingIntentsSince tests the delta listing of the scheduling intents
+func TestListSchedulingIntentsSince(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+		intentWatchers:       newIntentWatchHub(),
+	}
+	podInfos, err := svc.FindPodInfoFrom(ctx, setupFakeProcDir(t))
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	nginxIntent := &domain.Intent{ID: "intent-1", PodID: "20da609e-6973-4463-a1f9-2db9bcc5becc", ExecutionTime: 100}
+	busyboxIntent := &domain.Intent{ID: "intent-2", PodID: "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413", ExecutionTime: 100}
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{nginxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+
+	full, err := svc.ListSchedulingIntentsSince(ctx, 0)
+	require.NoError(t, err, "ListSchedulingIntentsSince should not return error")
+	assert.False(t, full.Delta, "without a version the full list should be returned")
+	require.Len(t, full.Changed, 1, "unexpected number of intents")
+
+	unchanged, err := svc.ListSchedulingIntentsSince(ctx, full.ResourceVersion)
+	require.NoError(t, err, "ListSchedulingIntentsSince should not return error")
+	assert.True(t, unchanged.Delta, "should return a delta")
+	assert.Equal(t, full.ResourceVersion, unchanged.ResourceVersion, "version should not change without mutations")
+	assert.Empty(t, unchanged.Changed, "nothing should have changed")
+	assert.Empty(t, unchanged.RemovedPIDs, "nothing should have been removed")
+
+	_, err = svc.replaceIntents(ctx, 2, []*domain.Intent{busyboxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+	delta, err := svc.ListSchedulingIntentsSince(ctx, full.ResourceVersion)
+	require.NoError(t, err, "ListSchedulingIntentsSince should not return error")
+	assert.True(t, delta.Delta, "should return a delta")
+	assert.Greater(t, delta.ResourceVersion, full.ResourceVersion, "version should increase with mutations")
+	require.Len(t, delta.Changed, 1, "unexpected number of changed intents")
+	assert.Equal(t, 5678, delta.Changed[0].PID, "unexpected changed pid")
+	assert.Equal(t, []int{1234}, delta.RemovedPIDs, "unexpected removed pids")
+
+	stale, err := svc.ListSchedulingIntentsSince(ctx, 1)
+	require.NoError(t, err, "ListSchedulingIntentsSince should not return error")
+	assert.False(t, stale.Delta, "a version no longer retained should fall back to the full list")
+	require.Len(t, stale.Changed, 1, "unexpected number of intents")
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+)
+
+const (
+	defaultMaxTaskSeries = 500
+	// overflowPodUID labels the series that aggregates every task beyond the series limit
+	overflowPodUID = "__overflow__"
+)
+
+// taskSeries is the aggregate of the tasks sharing one label set
+type taskSeries struct {
+	labels     []string // pod_uid, namespace, pod_name, strategy_id and, per PID, pid
+	tasks      int
+	runtimeNs  uint64
+	waitTimeNs uint64
+	dispatches uint64
+	vtimeSum   float64 // float, the sum of many vtimes can exceed uint64
+}
+
+// taskMetricOwners maps the PIDs of the pushed tasks to their pods. PIDs covered by a scheduling intent
+// take the pod and strategy of the intent, the others are looked up in their cgroup under rootDir.
+// Tasks outside of pods are left out.
+func (svc *Service) taskMetricOwners(rootDir string, tasks []domain.TaskMetric) map[int]domain.TaskMetricOwner {
+	owners := make(map[int]domain.TaskMetricOwner, len(tasks))
+	if len(tasks) == 0 {
+		return owners
+	}
+
+	svc.intentSet.mu.RLock()
+	defer svc.intentSet.mu.RUnlock()
+	svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
+		for _, schedulingIntent := range value {
+			podID := strings.TrimSuffix(key, "-"+strconv.Itoa(schedulingIntent.PID))
+			owner := domain.TaskMetricOwner{PodUID: podID}
+			if intent, ok := svc.intentSet.intents[podID]; ok {
+				owner.K8sNamespace = intent.K8sNamespace
+				owner.PodName = intent.PodName
+				owner.StrategyID = intent.StrategyID
+			}
+			owners[schedulingIntent.PID] = owner
+		}
+		return true
+	})
+
+	for _, task := range tasks {
+		if _, ok := owners[task.PID]; ok {
+			continue
+		}
+		podUID, ok := svc.podUIDOfProcess(rootDir, task.PID)
+		if !ok {
+			continue
+		}
+		owner := domain.TaskMetricOwner{PodUID: podUID}
+		if intent, ok := svc.intentSet.intents[podUID]; ok {
+			// the pod has an intent, but it does not cover this process
+			owner.K8sNamespace = intent.K8sNamespace
+			owner.PodName = intent.PodName
+		}
+		owners[task.PID] = owner
+	}
+	return owners
+}
+
+// podUIDOfProcess reads the cgroup of a process and returns the UID of the pod it runs in
+func (svc *Service) podUIDOfProcess(rootDir string, pid int) (string, bool) {
+	file, err := os.Open(fmt.Sprintf("%s/%d/cgroup", rootDir, pid))
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		cgroupPath, ok := parseCgroupLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if podUID, _, ok := svc.getPodInfoFromCgroup(cgroupPath); ok {
+			return podUID, true
+		}
+	}
+	return "", false
+}
+
+// aggregateTaskMetrics sums the statistics of the tasks per pod, or per PID when enabled. Tasks without an owner are
+// dropped. At most MaxSeries series are returned, series covered by a strategy are kept first and the remaining
+// tasks are folded into an overflow series so that totals stay correct.
+func aggregateTaskMetrics(tasks []domain.TaskMetric, owners map[int]domain.TaskMetricOwner, cfg config.MetricsConfig) []*taskSeries {
+	maxSeries := cfg.MaxSeries
+	if maxSeries <= 0 {
+		maxSeries = defaultMaxTaskSeries
+	}
+
+	seriesByKey := make(map[string]*taskSeries)
+	for _, task := range tasks {
+		owner, ok := owners[task.PID]
+		if !ok {
+			continue
+		}
+		labels := []string{owner.PodUID, owner.K8sNamespace, owner.PodName, owner.StrategyID}
+		if cfg.PerPID {
+			labels = append(labels, strconv.Itoa(task.PID))
+		}
+		key := strings.Join(labels, "\x00")
+		series, ok := seriesByKey[key]
+		if !ok {
+			series = &taskSeries{labels: labels}
+			seriesByKey[key] = series
+		}
+		series.add(task)
+	}
+
+	series := make([]*taskSeries, 0, len(seriesByKey))
+	for _, s := range seriesByKey {
+		series = append(series, s)
+	}
+	slices.SortFunc(series, func(a, b *taskSeries) int {
+		// series of pods covered by a strategy are the interesting ones, keep them when capping
+		if aCovered, bCovered := a.labels[3] != "", b.labels[3] != ""; aCovered != bCovered {
+			if aCovered {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.labels, b.labels)
+	})
+	if len(series) <= maxSeries {
+		return series
+	}
+
+	overflow := &taskSeries{labels: make([]string, len(series[0].labels))}
+	overflow.labels[0] = overflowPodUID
+	for _, s := range series[maxSeries-1:] {
+		overflow.merge(s)
+	}
+	return append(series[:maxSeries-1], overflow)
+}
+
+func (series *taskSeries) add(task domain.TaskMetric) {
+	series.tasks++
+	series.runtimeNs += task.RuntimeNs
+	series.waitTimeNs += task.WaitTimeNs
+	series.dispatches += task.NrDispatches
+	series.vtimeSum += float64(task.Vtime)
+}
+
+func (series *taskSeries) merge(other *taskSeries) {
+	series.tasks += other.tasks
+	series.runtimeNs += other.runtimeNs
+	series.waitTimeNs += other.waitTimeNs
+	series.dispatches += other.dispatches
+	series.vtimeSum += other.vtimeSum
+}
+
+// updateTaskMetrics joins the pushed task statistics against the pods on this node and hands them to the collector
+func (svc *Service) updateTaskMetrics(tasks []domain.TaskMetric) {
+	owners := svc.taskMetricOwners(procDir, tasks)
+	svc.metricCollector.UpdateTaskMetrics(aggregateTaskMetrics(tasks, owners, svc.metricCollector.metricsConfig))
+}
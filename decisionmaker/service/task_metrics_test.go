@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"github.com/Gthulhu/api/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskMetrics tests that pushed task statistics are joined with pods and strategies and capped in cardinality
+func TestTaskMetrics(t *testing.T) {
+	logger.InitLogger()
+	ctx := context.Background()
+	svc := &Service{
+		schedulingIntentsMap: util.NewGenericMap[string, []*domain.SchedulingIntents](),
+		intentSet:            newIntentSet(),
+	}
+	procRoot := setupFakeProcDir(t)
+	podInfos, err := svc.FindPodInfoFrom(ctx, procRoot)
+	require.NoError(t, err, "FindPodInfoFrom should not return error")
+	nginxIntent := &domain.Intent{
+		ID:           "intent-1",
+		StrategyID:   "strategy-1",
+		PodID:        "20da609e-6973-4463-a1f9-2db9bcc5becc",
+		PodName:      "nginx",
+		K8sNamespace: "default",
+	}
+	_, err = svc.replaceIntents(ctx, 1, []*domain.Intent{nginxIntent}, podInfos)
+	require.NoError(t, err, "replaceIntents should not return error")
+
+	tasks := []domain.TaskMetric{
+		{PID: 1234, RuntimeNs: 3e9, WaitTimeNs: 1e9, NrDispatches: 30, Vtime: 100},
+		{PID: 5678, RuntimeNs: 2e9, WaitTimeNs: 4e9, NrDispatches: 20, Vtime: 300},
+		{PID: 1, RuntimeNs: 1e9},
+	}
+	owners := svc.taskMetricOwners(procRoot, tasks)
+	assert.Equal(t, domain.TaskMetricOwner{
+		PodUID:       "20da609e-6973-4463-a1f9-2db9bcc5becc",
+		K8sNamespace: "default",
+		PodName:      "nginx",
+		StrategyID:   "strategy-1",
+	}, owners[1234], "pid covered by an intent should carry its strategy")
+	assert.Equal(t, domain.TaskMetricOwner{PodUID: "e52d4a2a-6e5f-44d9-a8b8-37ff3daa7413"}, owners[5678], "pid should be mapped by its cgroup")
+	assert.NotContains(t, owners, 1, "tasks outside of pods should be left out")
+
+	series := aggregateTaskMetrics(tasks, owners, config.MetricsConfig{})
+	require.Len(t, series, 2, "should aggregate per pod")
+	assert.Equal(t, []string{"20da609e-6973-4463-a1f9-2db9bcc5becc", "default", "nginx", "strategy-1"}, series[0].labels, "pods covered by a strategy should come first")
+	assert.EqualValues(t, 3e9, series[0].runtimeNs, "unexpected runtime")
+
+	perPID := aggregateTaskMetrics(tasks, owners, config.MetricsConfig{PerPID: true})
+	require.Len(t, perPID, 2, "should keep one series per pid")
+	assert.Equal(t, "1234", perPID[0].labels[4], "unexpected pid label")
+
+	capped := aggregateTaskMetrics(tasks, owners, config.MetricsConfig{PerPID: true, MaxSeries: 1})
+	require.Len(t, capped, 1, "should cap the number of series")
+	assert.Equal(t, overflowPodUID, capped[0].labels[0], "tasks beyond the cap should be folded into the overflow series")
+	assert.Equal(t, 2, capped[0].tasks, "overflow series should keep the totals")
+	assert.EqualValues(t, 5e9, capped[0].runtimeNs, "overflow series should keep the totals")
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/manager/errs"
+)
+
+const (
+	// watchHistorySize bounds how far back a watch can be resumed
+	watchHistorySize = 1024
+	// watchBufferSize is how many events a watcher may lag behind before it is dropped
+	watchBufferSize = 256
+)
+
+// intentWatchHub versions every change of schedulingIntentsMap and fans it out to the watchers.
+// Changes are published while holding intentSet.mu, so a watch registered under intentSet.mu
+// sees a consistent snapshot followed by every later change.
+type intentWatchHub struct {
+	mu              sync.Mutex
+	resourceVersion int64
+	history         []domain.SchedulingIntentEvent
+	watchers        map[chan domain.SchedulingIntentEvent]struct{}
+}
+
+// newIntentWatchHub starts the resource versions at the current time in microseconds, so versions keep
+// increasing across restarts and a version handed out by a previous process is never taken for a current one
+func newIntentWatchHub() *intentWatchHub {
+	return &intentWatchHub{
+		resourceVersion: time.Now().UnixMicro(),
+		watchers:        make(map[chan domain.SchedulingIntentEvent]struct{}),
+	}
+}
+
+// publish assigns the next resource version to the event and delivers it to every watcher.
+// Watchers whose buffer is full are dropped and have to resume from their last resource version.
+func (hub *intentWatchHub) publish(eventType domain.SchedulingIntentEventType, key string, intent *domain.SchedulingIntents) {
+	if hub == nil {
+		return
+	}
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	hub.resourceVersion++
+	event := domain.SchedulingIntentEvent{
+		Type:            eventType,
+		ResourceVersion: hub.resourceVersion,
+		Key:             key,
+		Intent:          intent,
+	}
+	hub.history = append(hub.history, event)
+	if len(hub.history) > watchHistorySize {
+		hub.history = slices.Delete(hub.history, 0, len(hub.history)-watchHistorySize)
+	}
+	for watcher := range hub.watchers {
+		select {
+		case watcher <- event:
+		default:
+			delete(hub.watchers, watcher)
+			close(watcher)
+		}
+	}
+}
+
+// subscribe registers a watcher and returns the events it missed since resourceVersion,
+// or nil events with ok false when they are no longer retained
+func (hub *intentWatchHub) subscribe(resourceVersion int64) (missed []domain.SchedulingIntentEvent, watcher chan domain.SchedulingIntentEvent, current int64, ok bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	missed, ok = hub.eventsSince(resourceVersion)
+	if !ok {
+		return nil, nil, hub.resourceVersion, false
+	}
+	watcher = make(chan domain.SchedulingIntentEvent, watchBufferSize)
+	hub.watchers[watcher] = struct{}{}
+	return missed, watcher, hub.resourceVersion, true
+}
+
+// changesSince returns the events published after resourceVersion together with the current resource version
+func (hub *intentWatchHub) changesSince(resourceVersion int64) (events []domain.SchedulingIntentEvent, current int64, ok bool) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	events, ok = hub.eventsSince(resourceVersion)
+	return events, hub.resourceVersion, ok
+}
+
+// eventsSince returns the retained events after resourceVersion, ok is false when some of them
+// are no longer retained or the version is in the future. The caller must hold hub.mu.
+func (hub *intentWatchHub) eventsSince(resourceVersion int64) ([]domain.SchedulingIntentEvent, bool) {
+	if resourceVersion > hub.resourceVersion {
+		return nil, false
+	}
+	if resourceVersion <= 0 || resourceVersion == hub.resourceVersion {
+		return nil, true
+	}
+	if len(hub.history) == 0 || hub.history[0].ResourceVersion > resourceVersion+1 {
+		return nil, false
+	}
+	var events []domain.SchedulingIntentEvent
+	for _, event := range hub.history {
+		if event.ResourceVersion > resourceVersion {
+			events = append(events, event)
+		}
+	}
+	return events, true
+}
+
+func (hub *intentWatchHub) unsubscribe(watcher chan domain.SchedulingIntentEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if _, ok := hub.watchers[watcher]; ok {
+		delete(hub.watchers, watcher)
+		close(watcher)
+	}
+}
+
+// WatchSchedulingIntents streams changes of the scheduling intents. Without a resource version the initial
+// events hold every current intent as ADDED, otherwise the changes made after that version. The initial events
+// always end with a BOOKMARK of the current version. The channel is closed when ctx is done or the watcher
+// falls too far behind, a resource version that is no longer retained yields 410 Gone.
+func (svc *Service) WatchSchedulingIntents(ctx context.Context, resourceVersion int64) ([]domain.SchedulingIntentEvent, <-chan domain.SchedulingIntentEvent, error) {
+	svc.intentSet.mu.RLock()
+	missed, watcher, current, ok := svc.intentWatchers.subscribe(resourceVersion)
+	var initial []domain.SchedulingIntentEvent
+	if ok && resourceVersion <= 0 {
+		svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
+			for _, intent := range value {
+				initial = append(initial, domain.SchedulingIntentEvent{
+					Type:            domain.SchedulingIntentAdded,
+					ResourceVersion: current,
+					Key:             key,
+					Intent:          intent,
+				})
+			}
+			return true
+		})
+	}
+	svc.intentSet.mu.RUnlock()
+	if !ok {
+		return nil, nil, errs.NewHTTPStatusError(http.StatusGone,
+			fmt.Sprintf("resource version %d is too old or unknown, current resource version is %d", resourceVersion, current), nil)
+	}
+
+	initial = append(initial, missed...)
+	initial = append(initial, domain.SchedulingIntentEvent{
+		Type:            domain.SchedulingIntentBookmark,
+		ResourceVersion: current,
+	})
+	go func() {
+		<-ctx.Done()
+		svc.intentWatchers.unsubscribe(watcher)
+	}()
+	return initial, watcher, nil
+}
+
+// ListSchedulingIntentsSince returns the scheduling intents changed or removed after resourceVersion.
+// Without a resource version, or when the changes since it are no longer retained, it returns every
+// current intent with Delta false so the caller replaces its state instead of patching it.
+func (svc *Service) ListSchedulingIntentsSince(ctx context.Context, resourceVersion int64) (*domain.SchedulingIntentsDelta, error) {
+	svc.intentSet.mu.RLock()
+	defer svc.intentSet.mu.RUnlock()
+
+	events, current, ok := svc.intentWatchers.changesSince(resourceVersion)
+	result := &domain.SchedulingIntentsDelta{
+		ResourceVersion: current,
+		Delta:           ok && resourceVersion > 0,
+		Changed:         []*domain.SchedulingIntents{},
+	}
+	if !result.Delta {
+		svc.schedulingIntentsMap.Range(func(key string, value []*domain.SchedulingIntents) bool {
+			result.Changed = append(result.Changed, value...)
+			return true
+		})
+		return result, nil
+	}
+
+	changedKeys := map[string]struct{}{}
+	removedPIDs := map[int]struct{}{}
+	for _, event := range events {
+		if event.Type == domain.SchedulingIntentDeleted {
+			removedPIDs[event.Intent.PID] = struct{}{}
+			continue
+		}
+		changedKeys[event.Key] = struct{}{}
+	}
+	for key := range changedKeys {
+		intents, ok := svc.schedulingIntentsMap.Load(key)
+		if !ok {
+			continue
+		}
+		for _, intent := range intents {
+			result.Changed = append(result.Changed, intent)
+			// the PID moved to another key, it is updated rather than removed
+			delete(removedPIDs, intent.PID)
+		}
+	}
+	slices.SortFunc(result.Changed, func(a, b *domain.SchedulingIntents) int {
+		return a.PID - b.PID
+	})
+	result.RemovedPIDs = slices.Sorted(maps.Keys(removedPIDs))
+	return result, nil
+}
+
+// storeSchedulingIntent stores the scheduling intent of a process and publishes the change if there is one,
+// it reports whether the key was new. The caller must hold intentSet.mu.
+func (svc *Service) storeSchedulingIntent(key string, intent *domain.SchedulingIntents) (added bool) {
+	previous, loaded := svc.schedulingIntentsMap.Swap(key, []*domain.SchedulingIntents{intent})
+	switch {
+	case !loaded:
+		svc.intentWatchers.publish(domain.SchedulingIntentAdded, key, intent)
+	case len(previous) != 1 || !schedulingIntentEqual(previous[0], intent):
+		svc.intentWatchers.publish(domain.SchedulingIntentModified, key, intent)
+	}
+	return !loaded
+}
+
+// deleteSchedulingIntent removes the scheduling intent of a process and publishes the deletion.
+// The caller must hold intentSet.mu.
+func (svc *Service) deleteSchedulingIntent(key string) bool {
+	previous, loaded := svc.schedulingIntentsMap.LoadAndDelete(key)
+	if !loaded {
+		return false
+	}
+	for _, intent := range previous {
+		svc.intentWatchers.publish(domain.SchedulingIntentDeleted, key, intent)
+	}
+	return true
+}
+
+// syncSchedulingIntents makes the entries selected by inScope equal to desired and returns
+// how many entries were added and removed. The caller must hold intentSet.mu.
+func (svc *Service) syncSchedulingIntents(desired map[string]*domain.SchedulingIntents, inScope func(key string) bool) (added int, removed int) {
+	svc.schedulingIntentsMap.Range(func(key string, _ []*domain.SchedulingIntents) bool {
+		if _, ok := desired[key]; !ok && inScope(key) && svc.deleteSchedulingIntent(key) {
+			removed++
+		}
+		return true
+	})
+	for key, intent := range desired {
+		if svc.storeSchedulingIntent(key, intent) {
+			added++
+		}
+	}
+	return added, removed
+}
+
+func schedulingIntentEqual(a, b *domain.SchedulingIntents) bool {
+	return a.Priority == b.Priority &&
+		a.ExecutionTime == b.ExecutionTime &&
+		a.PID == b.PID &&
+		a.CommandRegex == b.CommandRegex &&
+		slices.Equal(a.Selectors, b.Selectors)
+}
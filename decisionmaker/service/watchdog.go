@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+const (
+	defaultStaleThreshold = 30 * time.Second
+	defaultWatchdogCheck  = 5 * time.Second
+	// schedulerEventHistorySize bounds the state transitions kept for the manager
+	schedulerEventHistorySize = 100
+)
+
+// schedulerWatchdog tracks the metric pushes of the scheduler and decides whether it is stalled
+type schedulerWatchdog struct {
+	mu                sync.Mutex
+	threshold         time.Duration
+	startedAt         time.Time
+	lastMetricsAt     time.Time
+	lastRunAt         uint64
+	lastRunAdvancedAt time.Time
+	state             domain.SchedulerState
+	reason            string
+	events            []domain.SchedulerEvent
+}
+
+func newSchedulerWatchdog(cfg config.WatchdogConfig, now time.Time) *schedulerWatchdog {
+	if !cfg.Enabled {
+		return nil
+	}
+	threshold := cfg.StaleThreshold
+	if threshold <= 0 {
+		threshold = defaultStaleThreshold
+	}
+	return &schedulerWatchdog{
+		threshold: threshold,
+		startedAt: now,
+		state:     domain.SchedulerStateUnknown,
+	}
+}
+
+// observe records a metric push of the scheduler
+func (wd *schedulerWatchdog) observe(metricSet *domain.MetricSet, now time.Time) {
+	if wd == nil {
+		return
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	if wd.lastMetricsAt.IsZero() || metricSet.UserSchedLastRunAt != wd.lastRunAt {
+		wd.lastRunAdvancedAt = now
+	}
+	wd.lastMetricsAt = now
+	wd.lastRunAt = metricSet.UserSchedLastRunAt
+	wd.evaluate(now)
+}
+
+// check re-evaluates the state at now and returns it
+func (wd *schedulerWatchdog) check(now time.Time) domain.SchedulerHealth {
+	if wd == nil {
+		return domain.SchedulerHealth{State: domain.SchedulerStateUnknown, Reason: "watchdog is disabled"}
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	wd.evaluate(now)
+	return domain.SchedulerHealth{
+		State:              wd.state,
+		Reason:             wd.reason,
+		LastMetricsAt:      wd.lastMetricsAt,
+		UserSchedLastRunAt: wd.lastRunAt,
+		LastRunAdvancedAt:  wd.lastRunAdvancedAt,
+	}
+}
+
+// evaluate updates the state and records a transition event, the caller must hold mu
+func (wd *schedulerWatchdog) evaluate(now time.Time) {
+	state, reason := domain.SchedulerStateRunning, ""
+	switch {
+	case wd.lastMetricsAt.IsZero():
+		if now.Sub(wd.startedAt) <= wd.threshold {
+			// give the scheduler time to push its first metrics
+			state = domain.SchedulerStateUnknown
+		} else {
+			state, reason = domain.SchedulerStateStalled, fmt.Sprintf("no metrics received within %s", wd.threshold)
+		}
+	case now.Sub(wd.lastMetricsAt) > wd.threshold:
+		state, reason = domain.SchedulerStateStalled, fmt.Sprintf("no metrics received for %s", now.Sub(wd.lastMetricsAt).Truncate(time.Second))
+	case now.Sub(wd.lastRunAdvancedAt) > wd.threshold:
+		state, reason = domain.SchedulerStateStalled, fmt.Sprintf("usersched_last_run_at has not advanced for %s", now.Sub(wd.lastRunAdvancedAt).Truncate(time.Second))
+	}
+
+	previous := wd.state
+	wd.state, wd.reason = state, reason
+	switch {
+	case state == domain.SchedulerStateStalled && previous != domain.SchedulerStateStalled:
+		wd.record(domain.SchedulerEvent{Type: domain.SchedulerEventStalled, Reason: reason, Timestamp: now})
+	case state == domain.SchedulerStateRunning && previous == domain.SchedulerStateStalled:
+		wd.record(domain.SchedulerEvent{Type: domain.SchedulerEventRecovered, Timestamp: now})
+	}
+}
+
+func (wd *schedulerWatchdog) record(event domain.SchedulerEvent) {
+	wd.events = append(wd.events, event)
+	if len(wd.events) > schedulerEventHistorySize {
+		wd.events = slices.Delete(wd.events, 0, len(wd.events)-schedulerEventHistorySize)
+	}
+}
+
+// eventsSince returns the recorded transitions after since, oldest first
+func (wd *schedulerWatchdog) eventsSince(since time.Time) []domain.SchedulerEvent {
+	if wd == nil {
+		return []domain.SchedulerEvent{}
+	}
+	wd.mu.Lock()
+	defer wd.mu.Unlock()
+
+	events := []domain.SchedulerEvent{}
+	for _, event := range wd.events {
+		if event.Timestamp.After(since) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// SchedulerHealth returns whether the scheduler of the node is running, stalled or not known yet
+func (svc *Service) SchedulerHealth(ctx context.Context) domain.SchedulerHealth {
+	return svc.watchdog.check(time.Now())
+}
+
+// ListSchedulerEvents returns the scheduler state transitions recorded after since
+func (svc *Service) ListSchedulerEvents(ctx context.Context, since time.Time) []domain.SchedulerEvent {
+	return svc.watchdog.eventsSince(since)
+}
+
+// RunSchedulerWatchdog re-evaluates the scheduler state periodically, so that a stall is logged
+// and recorded even when nobody asks for it. It blocks until ctx is done.
+func (svc *Service) RunSchedulerWatchdog(ctx context.Context) error {
+	interval := svc.watchdogConfig.CheckInterval
+	if interval <= 0 {
+		interval = defaultWatchdogCheck
+	}
+	logger.Logger(ctx).Info().Msgf("starting scheduler watchdog, threshold:%s interval:%s", svc.watchdog.threshold, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	state := domain.SchedulerStateUnknown
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			health := svc.watchdog.check(time.Now())
+			if health.State == state {
+				continue
+			}
+			switch health.State {
+			case domain.SchedulerStateStalled:
+				logger.Logger(ctx).Warn().Msgf("scheduler stalled: %s", health.Reason)
+			case domain.SchedulerStateRunning:
+				logger.Logger(ctx).Info().Msg("scheduler is running")
+			}
+			state = health.State
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/decisionmaker/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchedulerWatchdog tests the stalled and recovered transitions of the scheduler
+func TestSchedulerWatchdog(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	wd := newSchedulerWatchdog(config.WatchdogConfig{Enabled: true, StaleThreshold: 30 * time.Second}, start)
+	require.NotNil(t, wd, "enabled watchdog should be created")
+
+	assert.Equal(t, domain.SchedulerStateUnknown, wd.check(start.Add(10*time.Second)).State, "should wait for the first push")
+	health := wd.check(start.Add(40 * time.Second))
+	assert.Equal(t, domain.SchedulerStateStalled, health.State, "no push at all should be stalled")
+	assert.Contains(t, health.Reason, "no metrics received", "unexpected reason")
+
+	wd.observe(&domain.MetricSet{UserSchedLastRunAt: 100}, start.Add(41*time.Second))
+	assert.Equal(t, domain.SchedulerStateRunning, wd.check(start.Add(42*time.Second)).State, "push should recover the scheduler")
+
+	// metrics keep arriving but the scheduler stopped running
+	for i := 1; i <= 4; i++ {
+		wd.observe(&domain.MetricSet{UserSchedLastRunAt: 100}, start.Add(time.Duration(41+i*10)*time.Second))
+	}
+	health = wd.check(start.Add(81 * time.Second))
+	assert.Equal(t, domain.SchedulerStateStalled, health.State, "last run not advancing should be stalled")
+	assert.Contains(t, health.Reason, "usersched_last_run_at", "unexpected reason")
+
+	wd.observe(&domain.MetricSet{UserSchedLastRunAt: 200}, start.Add(90*time.Second))
+	assert.Equal(t, domain.SchedulerStateStalled, wd.check(start.Add(200*time.Second)).State, "missing pushes should be stalled")
+
+	events := wd.eventsSince(time.Time{})
+	eventTypes := []domain.SchedulerEventType{}
+	for _, event := range events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	assert.Equal(t, []domain.SchedulerEventType{
+		domain.SchedulerEventStalled,
+		domain.SchedulerEventRecovered,
+		domain.SchedulerEventStalled,
+		domain.SchedulerEventRecovered,
+		domain.SchedulerEventStalled,
+	}, eventTypes, "unexpected transitions")
+	assert.Len(t, wd.eventsSince(start.Add(85*time.Second)), 2, "should filter events by time")
+
+	var disabled *schedulerWatchdog
+	assert.Equal(t, domain.SchedulerStateUnknown, disabled.check(start).State, "disabled watchdog should report unknown")
+}
@@ -83,7 +83,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "List schedule intents created by the authenticated user.",
+                "description": "List schedule intents created by the authenticated user, optionally filtered by state.",
                 "consumes": [
                     "application/json"
                 ],
@@ -94,6 +94,14 @@ const docTemplate = `{
                     "Strategies"
                 ],
                 "summary": "List self schedule intents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated intent states to filter by (e.g. applied,failed,pod_not_found)",
+                        "name": "state",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -128,6 +136,150 @@ const docTemplate = `{
                 }
             }
         },
+        "/api/v1/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the nodes running a decision maker with their online/offline state, decision maker version, last contact time and intent counts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Nodes"
+                ],
+                "summary": "List nodes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListNodesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/nodes/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the scheduler metrics last scraped from the decision maker of every node, with their cluster totals. Totals only include nodes whose last scrape succeeded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Nodes"
+                ],
+                "summary": "List node scheduler metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListNodeMetricsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/nodes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a node running a decision maker by its Kubernetes node name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Nodes"
+                ],
+                "summary": "Get node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Node name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_Node"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/api/v1/permissions": {
             "get": {
                 "security": [
@@ -392,7 +544,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new schedule strategy.",
+                "description": "Create a new schedule strategy. Existing strategies selecting the same pods are reported as conflicts, the strategy of highest precedence wins each pod: higher weight, then namespace-specific over cluster-wide, then newest. Conflicts are rejected with 409 when the manager is configured to.",
                 "consumes": [
                     "application/json"
                 ],
@@ -418,7 +570,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse"
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_CreateScheduleStrategyResponse"
                         }
                     },
                     "400": {
@@ -439,6 +591,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -448,14 +612,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/strategies/self": {
-            "get": {
+        "/api/v1/strategies/preview": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "List schedule strategies created by the authenticated user.",
+                "description": "Resolve the pods a schedule strategy would select, grouped by node and namespace, together with the reachability of the decision makers of those nodes and the existing strategies already targeting the pods. Nothing is stored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -465,12 +629,23 @@ const docTemplate = `{
                 "tags": [
                     "Strategies"
                 ],
-                "summary": "List self schedule strategies",
+                "summary": "Preview schedule strategy",
+                "parameters": [
+                    {
+                        "description": "Schedule strategy payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.CreateScheduleStrategyRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListSchedulerStrategiesResponse"
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_PreviewScheduleStrategyResponse"
                         }
                     },
                     "400": {
@@ -500,49 +675,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users": {
+        "/api/v1/strategies/self": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve user list.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "List users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListUsersResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new user account.",
+                "description": "List schedule strategies created by the authenticated user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -550,25 +690,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Create user",
-                "parameters": [
-                    {
-                        "description": "User payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.CreateUserRequest"
-                        }
-                    }
+                    "Strategies"
                 ],
+                "summary": "List self schedule strategies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse"
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListSchedulerStrategiesResponse"
                         }
                     },
                     "400": {
@@ -598,14 +727,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/password": {
+        "/api/v1/strategies/{id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Reset another user's password.",
+                "description": "Replace the criteria of a schedule strategy and push the resulting intent changes to the decision makers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -613,17 +742,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Strategies"
                 ],
-                "summary": "Reset user password",
+                "summary": "Update schedule strategy",
                 "parameters": [
                     {
-                        "description": "Reset payload",
+                        "type": "string",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule strategy payload",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/rest.ResetPasswordRequest"
+                            "$ref": "#/definitions/rest.UpdateScheduleStrategyRequest"
                         }
                     }
                 ],
@@ -652,6 +788,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -659,16 +801,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/users/permissions": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a user's roles or status.",
+                "description": "Delete a schedule strategy and retract its intents from the decision makers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -676,18 +816,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Strategies"
                 ],
-                "summary": "Update user roles and status",
+                "summary": "Delete schedule strategy",
                 "parameters": [
                     {
-                        "description": "Permissions payload",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/rest.UpdateUserPermissionsRequest"
-                        }
+                        "type": "string",
+                        "description": "Strategy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -715,6 +853,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -724,26 +868,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/users/self": {
+        "/api/v1/users": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve profile of the authenticated user.",
+                "description": "Retrieve user list.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Users"
                 ],
-                "summary": "Get current user",
+                "summary": "List users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_GetSelfUserResponse"
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListUsersResponse"
                         }
                     },
                     "401": {
@@ -759,11 +903,235 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/users/self/password": {
-            "put": {
-                "security": [
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new user account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "User payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reset another user's password.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Reset user password",
+                "parameters": [
+                    {
+                        "description": "Reset payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/permissions": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a user's roles or status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user roles and status",
+                "parameters": [
+                    {
+                        "description": "Permissions payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/rest.UpdateUserPermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/self": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve profile of the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_GetSelfUserResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/self/password": {
+            "put": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
@@ -838,6 +1206,32 @@ const docTemplate = `{
                 }
             }
         },
+        "/ready": {
+            "get": {
+                "description": "Fails with 503 while the watchdog considers the scheduler of the node stalled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_decisionmaker_rest.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Gthulhu_api_decisionmaker_rest.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/version": {
             "get": {
                 "description": "Returns service version and exposed endpoints.",
@@ -860,19 +1254,6 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "domain.IntentState": {
-            "type": "integer",
-            "enum": [
-                0,
-                1,
-                2
-            ],
-            "x-enum-varnames": [
-                "IntentStateUnknown",
-                "IntentStateInitialized",
-                "IntentStateSent"
-            ]
-        },
         "domain.PermissionKey": {
             "type": "string",
             "enum": [
@@ -887,7 +1268,10 @@ const docTemplate = `{
                 "permission.read",
                 "schedule_strategy.create",
                 "schedule_strategy.read",
-                "schedule_intent.read"
+                "schedule_strategy.update",
+                "schedule_strategy.delete",
+                "schedule_intent.read",
+                "node.read"
             ],
             "x-enum-varnames": [
                 "CreateUser",
@@ -901,11 +1285,15 @@ const docTemplate = `{
                 "PermissionRead",
                 "ScheduleStrategyCreate",
                 "ScheduleStrategyRead",
-                "ScheduleIntentRead"
+                "ScheduleStrategyUpdate",
+                "ScheduleStrategyDelete",
+                "ScheduleIntentRead",
+                "NodeRead"
             ]
         },
         "domain.UserStatus": {
             "type": "integer",
+            "format": "int32",
             "enum": [
                 1,
                 2,
@@ -920,6 +1308,12 @@ const docTemplate = `{
         "github_com_Gthulhu_api_decisionmaker_rest.HealthResponse": {
             "type": "object",
             "properties": {
+                "sched_ext": {
+                    "$ref": "#/definitions/rest.SchedExtStatus"
+                },
+                "scheduler": {
+                    "$ref": "#/definitions/rest.SchedulerHealth"
+                },
                 "service": {
                     "type": "string"
                 },
@@ -973,6 +1367,17 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_Gthulhu_api_manager_rest.LabelSelector": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_Gthulhu_api_manager_rest.SuccessResponse-github_com_Gthulhu_api_manager_rest_EmptyResponse": {
             "type": "object",
             "properties": {
@@ -987,11 +1392,53 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_GetSelfUserResponse": {
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_CreateScheduleStrategyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/rest.CreateScheduleStrategyResponse"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_GetSelfUserResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/rest.GetSelfUserResponse"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListNodeMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/rest.ListNodeMetricsResponse"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_ListNodesResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/rest.GetSelfUserResponse"
+                    "$ref": "#/definitions/rest.ListNodesResponse"
                 },
                 "success": {
                     "type": "boolean"
@@ -1085,6 +1532,34 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_Node": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/rest.Node"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Gthulhu_api_manager_rest.SuccessResponse-rest_PreviewScheduleStrategyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/rest.PreviewScheduleStrategyResponse"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_Gthulhu_api_manager_rest.VersionResponse": {
             "type": "object",
             "properties": {
@@ -1136,6 +1611,9 @@ const docTemplate = `{
                 "executionTime": {
                     "type": "integer"
                 },
+                "fieldFilter": {
+                    "$ref": "#/definitions/rest.PodFieldFilter"
+                },
                 "k8sNamespace": {
                     "type": "array",
                     "items": {
@@ -1145,7 +1623,14 @@ const docTemplate = `{
                 "labelSelectors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/rest.LabelSelector"
+                        "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.LabelSelector"
+                    }
+                },
+                "matchExpressions": {
+                    "description": "MatchExpressions are label requirements the pods have to meet on top of LabelSelectors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MatchExpression"
                     }
                 },
                 "priority": {
@@ -1153,6 +1638,22 @@ const docTemplate = `{
                 },
                 "strategyNamespace": {
                     "type": "string"
+                },
+                "weight": {
+                    "description": "Weight decides between strategies selecting the same pod, the higher weight wins",
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.CreateScheduleStrategyResponse": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "description": "Conflicts are the existing strategies selecting some of the pods of the new strategy",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.StrategyConflict"
+                    }
                 }
             }
         },
@@ -1195,133 +1696,342 @@ const docTemplate = `{
                 }
             }
         },
-        "rest.LabelSelector": {
+        "rest.ListNodeMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "dispatchRate": {
+                    "type": "number"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.NodeMetrics"
+                    }
+                },
+                "nodesReporting": {
+                    "type": "integer"
+                },
+                "nrFailedDispatches": {
+                    "type": "integer"
+                },
+                "nrQueued": {
+                    "type": "number"
+                },
+                "nrRunning": {
+                    "type": "number"
+                },
+                "nrSchedCongested": {
+                    "type": "integer"
+                },
+                "nrScheduled": {
+                    "type": "number"
+                },
+                "scrapedAt": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.ListNodesResponse": {
+            "type": "object",
+            "properties": {
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.Node"
+                    }
+                }
+            }
+        },
+        "rest.ListPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "description": {
+                                "type": "string"
+                            },
+                            "key": {
+                                "$ref": "#/definitions/domain.PermissionKey"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "rest.ListRolesResponse": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "description": {
+                                "type": "string"
+                            },
+                            "id": {
+                                "type": "string"
+                            },
+                            "name": {
+                                "type": "string"
+                            },
+                            "rolePolicy": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/rest.RolePolicy"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "rest.ListScheduleIntentsResponse": {
+            "type": "object",
+            "properties": {
+                "intents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ScheduleIntent"
+                    }
+                }
+            }
+        },
+        "rest.ListSchedulerStrategiesResponse": {
+            "type": "object",
+            "properties": {
+                "strategies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.ScheduleStrategy"
+                    }
+                }
+            }
+        },
+        "rest.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "id": {
+                                "type": "string"
+                            },
+                            "roles": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
+                            },
+                            "status": {
+                                "$ref": "#/definitions/domain.UserStatus"
+                            },
+                            "username": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "rest.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.MatchExpression": {
             "type": "object",
             "properties": {
                 "key": {
                     "type": "string"
                 },
-                "value": {
+                "operator": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "rest.Node": {
+            "type": "object",
+            "properties": {
+                "dmVersion": {
+                    "type": "string"
+                },
+                "health": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "intentCount": {
+                    "type": "integer"
+                },
+                "intentCounts": {
+                    "description": "keyed by intent state",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "lastContactTime": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastProbeTime": {
+                    "type": "integer"
+                },
+                "nodeID": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.NodeMetricSample": {
+            "type": "object",
+            "properties": {
+                "dispatchRate": {
+                    "type": "number"
+                },
+                "failedDispatchRatio": {
+                    "type": "number"
+                },
+                "nrBounceDispatches": {
+                    "type": "integer"
+                },
+                "nrCancelDispatches": {
+                    "type": "integer"
+                },
+                "nrFailedDispatches": {
+                    "type": "integer"
+                },
+                "nrKernelDispatches": {
+                    "type": "integer"
+                },
+                "nrOnlineCPUs": {
+                    "type": "number"
+                },
+                "nrQueued": {
+                    "type": "number"
+                },
+                "nrRunning": {
+                    "type": "number"
+                },
+                "nrSchedCongested": {
+                    "type": "integer"
+                },
+                "nrScheduled": {
+                    "type": "number"
+                },
+                "nrUserDispatches": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.NodeMetrics": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "host": {
+                    "type": "string"
+                },
+                "nodeID": {
                     "type": "string"
+                },
+                "sample": {
+                    "$ref": "#/definitions/rest.NodeMetricSample"
+                },
+                "scrapedAt": {
+                    "type": "integer"
                 }
             }
         },
-        "rest.ListPermissionsResponse": {
+        "rest.PodFieldFilter": {
             "type": "object",
             "properties": {
-                "permissions": {
+                "nodeNames": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "description": {
-                                "type": "string"
-                            },
-                            "key": {
-                                "$ref": "#/definitions/domain.PermissionKey"
-                            }
-                        }
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "rest.ListRolesResponse": {
-            "type": "object",
-            "properties": {
-                "roles": {
+                },
+                "ownerKind": {
+                    "description": "OwnerKind and OwnerName match the direct owner of the pod, e.g. the ReplicaSet of a Deployment",
+                    "type": "string"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "phases": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "description": {
-                                "type": "string"
-                            },
-                            "id": {
-                                "type": "string"
-                            },
-                            "name": {
-                                "type": "string"
-                            },
-                            "rolePolicy": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/rest.RolePolicy"
-                                }
-                            }
-                        }
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "rest.ListScheduleIntentsResponse": {
-            "type": "object",
-            "properties": {
-                "intents": {
+                },
+                "qosClasses": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/rest.ScheduleIntent"
+                        "type": "string"
                     }
                 }
             }
         },
-        "rest.ListSchedulerStrategiesResponse": {
+        "rest.PreviewScheduleStrategyResponse": {
             "type": "object",
             "properties": {
-                "strategies": {
+                "matchedPods": {
+                    "type": "integer"
+                },
+                "nodes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/rest.ScheduleStrategy"
+                        "$ref": "#/definitions/rest.StrategyPreviewNode"
                     }
-                }
-            }
-        },
-        "rest.ListUsersResponse": {
-            "type": "object",
-            "properties": {
-                "users": {
+                },
+                "strategies": {
+                    "description": "Strategies already have an intent for one of the matched pods",
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "id": {
-                                "type": "string"
-                            },
-                            "roles": {
-                                "type": "array",
-                                "items": {
-                                    "type": "string"
-                                }
-                            },
-                            "status": {
-                                "$ref": "#/definitions/domain.UserStatus"
-                            },
-                            "username": {
-                                "type": "string"
-                            }
-                        }
+                        "$ref": "#/definitions/rest.ScheduleStrategy"
                     }
                 }
             }
         },
-        "rest.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "password": {
-                    "type": "string"
-                },
-                "username": {
-                    "type": "string"
-                }
-            }
-        },
-        "rest.LoginResponse": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string"
-                }
-            }
-        },
         "rest.ResetPasswordRequest": {
             "type": "object",
             "properties": {
@@ -1350,9 +2060,40 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.SchedExtStatus": {
+            "type": "object",
+            "properties": {
+                "enable_seq": {
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "nr_rejected": {
+                    "type": "integer"
+                },
+                "ops": {
+                    "description": "name of the loaded BPF scheduler",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "switch_all": {
+                    "type": "boolean"
+                }
+            }
+        },
         "rest.ScheduleIntent": {
             "type": "object",
             "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
                 "commandRegex": {
                     "type": "string"
                 },
@@ -1365,9 +2106,18 @@ const docTemplate = `{
                 "k8sNamespace": {
                     "type": "string"
                 },
+                "lastError": {
+                    "type": "string"
+                },
+                "nextAttemptTime": {
+                    "type": "integer"
+                },
                 "nodeID": {
                     "type": "string"
                 },
+                "pidcount": {
+                    "type": "integer"
+                },
                 "podID": {
                     "type": "string"
                 },
@@ -1380,11 +2130,28 @@ const docTemplate = `{
                 "priority": {
                     "type": "integer"
                 },
+                "reason": {
+                    "type": "string"
+                },
                 "state": {
-                    "$ref": "#/definitions/domain.IntentState"
+                    "type": "string",
+                    "enum": [
+                        "unknown",
+                        "initialized",
+                        "sent",
+                        "retracting",
+                        "applied",
+                        "failed",
+                        "pod_not_found",
+                        "overridden"
+                    ]
                 },
                 "strategyID": {
                     "type": "string"
+                },
+                "winningStrategyID": {
+                    "description": "WinningStrategyID is the strategy taking precedence on the pod, the intent is overridden unless it is StrategyID",
+                    "type": "string"
                 }
             }
         },
@@ -1397,6 +2164,9 @@ const docTemplate = `{
                 "executionTime": {
                     "type": "integer"
                 },
+                "fieldFilter": {
+                    "$ref": "#/definitions/rest.PodFieldFilter"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -1409,7 +2179,13 @@ const docTemplate = `{
                 "labelSelectors": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/rest.LabelSelector"
+                        "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.LabelSelector"
+                    }
+                },
+                "matchExpressions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MatchExpression"
                     }
                 },
                 "priority": {
@@ -1417,6 +2193,104 @@ const docTemplate = `{
                 },
                 "strategyNamespace": {
                     "type": "string"
+                },
+                "weight": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.SchedulerHealth": {
+            "type": "object",
+            "properties": {
+                "last_metrics_at": {
+                    "type": "string"
+                },
+                "last_run_advanced_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "usersched_last_run_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "rest.StrategyConflict": {
+            "type": "object",
+            "properties": {
+                "podIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "strategyID": {
+                    "type": "string"
+                },
+                "wins": {
+                    "description": "Wins reports whether the existing strategy takes precedence over the new one on those pods",
+                    "type": "boolean"
+                }
+            }
+        },
+        "rest.StrategyPreviewNamespace": {
+            "type": "object",
+            "properties": {
+                "k8sNamespace": {
+                    "type": "string"
+                },
+                "pods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.StrategyPreviewPod"
+                    }
+                }
+            }
+        },
+        "rest.StrategyPreviewNode": {
+            "type": "object",
+            "properties": {
+                "decisionMakerError": {
+                    "type": "string"
+                },
+                "decisionMakerReachable": {
+                    "type": "boolean"
+                },
+                "namespaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.StrategyPreviewNamespace"
+                    }
+                },
+                "nodeID": {
+                    "type": "string"
+                }
+            }
+        },
+        "rest.StrategyPreviewPod": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "podID": {
+                    "type": "string"
+                },
+                "podName": {
+                    "type": "string"
+                },
+                "strategyIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -1440,6 +2314,49 @@ const docTemplate = `{
                 }
             }
         },
+        "rest.UpdateScheduleStrategyRequest": {
+            "type": "object",
+            "properties": {
+                "commandRegex": {
+                    "type": "string"
+                },
+                "executionTime": {
+                    "type": "integer"
+                },
+                "fieldFilter": {
+                    "$ref": "#/definitions/rest.PodFieldFilter"
+                },
+                "k8sNamespace": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "labelSelectors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Gthulhu_api_manager_rest.LabelSelector"
+                    }
+                },
+                "matchExpressions": {
+                    "description": "MatchExpressions are label requirements the pods have to meet on top of LabelSelectors",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rest.MatchExpression"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "strategyNamespace": {
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "Weight decides between strategies selecting the same pod, the higher weight wins",
+                    "type": "integer"
+                }
+            }
+        },
         "rest.UpdateUserPermissionsRequest": {
             "type": "object",
             "properties": {
@@ -33,6 +33,24 @@ func ConfigModule(cfg config.ManageConfig) (fx.Option, error) {
 		fx.Provide(func(managerCfg config.ManageConfig) config.K8SConfig {
 			return managerCfg.K8S
 		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.ReconcilerConfig {
+			return managerCfg.Reconciler
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.OutboxConfig {
+			return managerCfg.Outbox
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.NodeMetricsConfig {
+			return managerCfg.NodeMetrics
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.NodeRegistryConfig {
+			return managerCfg.NodeRegistry
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.DecisionMakerDiscoveryConfig {
+			return managerCfg.DecisionMaker
+		}),
+		fx.Provide(func(managerCfg config.ManageConfig) config.StrategyConfig {
+			return managerCfg.Strategy
+		}),
 	), nil
 }
 
@@ -2,12 +2,14 @@ package app
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/migration"
 	"github.com/Gthulhu/api/manager/rest"
 	"github.com/Gthulhu/api/pkg/logger"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/fx"
 )
 
@@ -40,11 +42,31 @@ func NewRestApp(configName string, configDirPath string) (*fx.App, error) {
 	app := fx.New(
 		handlerModule,
 		fx.Invoke(migration.RunMongoMigration),
+		fx.Invoke(RegisterNodeMetricsCollector),
 		fx.Invoke(StartRestApp),
+		fx.Invoke(StartStrategyReconciler),
+		fx.Invoke(StartIntentDeliveryWorker),
+		fx.Invoke(StartNodeMetricsScraper),
+		fx.Invoke(StartNodeRegistry),
 	)
 	return app, nil
 }
 
+// NodeMetricsCollectorParams holds the collector of the scraped node metrics provided by the service
+type NodeMetricsCollectorParams struct {
+	fx.In
+	Collector prometheus.Collector `name:"node_metrics_collector"`
+}
+
+// RegisterNodeMetricsCollector registers the node metrics collector with the default prometheus registry served on
+// /metrics, once per app rather than once per service
+func RegisterNodeMetricsCollector(params NodeMetricsCollectorParams) error {
+	if err := prometheus.Register(params.Collector); err != nil {
+		return fmt.Errorf("register node metrics collector: %w", err)
+	}
+	return nil
+}
+
 func StartRestApp(lc fx.Lifecycle, cfg config.ServerConfig, handler *rest.Handler) error {
 	engine := echo.New()
 	handler.SetupRoutes(engine)
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.uber.org/fx"
+)
+
+// StartStrategyReconciler runs the strategy reconciliation loop for the lifetime of the app
+func StartStrategyReconciler(lc fx.Lifecycle, cfg config.ReconcilerConfig, svc domain.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "strategy reconciler", svc.RunStrategyReconciler)
+	return nil
+}
+
+// StartIntentDeliveryWorker runs the worker retrying pending intent deliveries for the lifetime of the app
+func StartIntentDeliveryWorker(lc fx.Lifecycle, cfg config.OutboxConfig, svc domain.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "intent delivery worker", svc.RunIntentDeliveryWorker)
+	return nil
+}
+
+// StartNodeMetricsScraper runs the loop scraping the decision makers for scheduler metrics for the lifetime of the app
+func StartNodeMetricsScraper(lc fx.Lifecycle, cfg config.NodeMetricsConfig, svc domain.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "node metrics scraper", svc.RunNodeMetricsScraper)
+	return nil
+}
+
+// StartNodeRegistry runs the loop keeping the node registry up to date for the lifetime of the app
+func StartNodeRegistry(lc fx.Lifecycle, cfg config.NodeRegistryConfig, svc domain.Service) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	runWorker(lc, "node registry", svc.RunNodeRegistry)
+	return nil
+}
+
+// runWorker starts run in the background when the app starts and cancels it when the app stops
+func runWorker(lc fx.Lifecycle, name string, run func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				if err := run(ctx); err != nil {
+					logger.Logger(ctx).Error().Err(err).Msgf("%s stopped", name)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			logger.Logger(stopCtx).Info().Msgf("shutting down %s", name)
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
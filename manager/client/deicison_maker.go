@@ -5,15 +5,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/Gthulhu/api/config"
+	dmdomain "github.com/Gthulhu/api/decisionmaker/domain"
 	dmrest "github.com/Gthulhu/api/decisionmaker/rest"
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func NewDecisionMakerClient(keyConfig config.KeyConfig) domain.DecisionMakerAdapter {
@@ -33,10 +37,10 @@ type DecisionMakerClient struct {
 	tokenCache     *cache.Cache[string, string]
 }
 
-func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisionMaker *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
+func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisionMaker *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) ([]*domain.ScheduleIntentResult, error) {
 	token, err := dm.GetToken(ctx, decisionMaker)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	logger.Logger(ctx).Debug().Msgf("Sending %d scheduling intents to decision maker pod (host:%s nodeID:%s port:%d)", len(intents), decisionMaker.Host, decisionMaker.NodeID, decisionMaker.Port)
@@ -46,6 +50,8 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 	}
 	for _, intent := range intents {
 		reqPayload.Intents = append(reqPayload.Intents, dmrest.Intent{
+			ID:            intent.ID.Hex(),
+			StrategyID:    intent.StrategyID.Hex(),
 			PodName:       intent.PodName,
 			PodID:         intent.PodID,
 			NodeID:        intent.NodeID,
@@ -57,17 +63,143 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 		})
 	}
 
-	jsonBody, err := json.Marshal(reqPayload)
+	var resp dmrest.SuccessResponse[dmrest.HandleIntentsResponse]
+	err = dm.doRequest(ctx, decisionMaker, token, http.MethodPost, "/api/v1/intents", reqPayload, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+
+	results := make([]*domain.ScheduleIntentResult, 0, len(resp.Data.Results))
+	for _, result := range resp.Data.Results {
+		intentID, err := bson.ObjectIDFromHex(result.IntentID)
+		if err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msgf("decision maker %s reported result for unknown intent %q", decisionMaker, result.IntentID)
+			continue
+		}
+		results = append(results, &domain.ScheduleIntentResult{
+			IntentID: intentID,
+			State:    intentStateFromResultStatus(result.Status),
+			PIDCount: result.PIDCount,
+			Reason:   result.Reason,
+		})
+	}
+	return results, nil
+}
+
+func intentStateFromResultStatus(status string) domain.IntentState {
+	switch dmdomain.IntentResultStatus(status) {
+	case dmdomain.IntentResultApplied:
+		return domain.IntentStateApplied
+	case dmdomain.IntentResultPodNotFound:
+		return domain.IntentStatePodNotFound
+	default:
+		return domain.IntentStateFailed
+	}
+}
+
+func (dm *DecisionMakerClient) DeleteSchedulingIntent(ctx context.Context, decisionMaker *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
+	token, err := dm.GetToken(ctx, decisionMaker)
 	if err != nil {
 		return err
 	}
-	endpoint := "http://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + "/api/v1/intents"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonBody))
+
+	logger.Logger(ctx).Debug().Msgf("Deleting %d scheduling intents from decision maker pod (host:%s nodeID:%s port:%d)", len(intents), decisionMaker.Host, decisionMaker.NodeID, decisionMaker.Port)
+
+	reqPayload := dmrest.DeleteIntentsRequest{
+		PodIDs: make([]string, 0, len(intents)),
+	}
+	for _, intent := range intents {
+		reqPayload.PodIDs = append(reqPayload.PodIDs, intent.PodID)
+	}
+	return dm.doRequest(ctx, decisionMaker, token, http.MethodDelete, "/api/v1/intents", reqPayload, nil)
+}
+
+// GetMetricsHistory returns the scheduler metrics the decision maker received between from and to, oldest first
+func (dm *DecisionMakerClient) GetMetricsHistory(ctx context.Context, decisionMaker *domain.DecisionMakerPod, from, to time.Time) ([]*domain.NodeMetricSample, error) {
+	token, err := dm.GetToken(ctx, decisionMaker)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("to", strconv.FormatInt(to.Unix(), 10))
+	var resp dmrest.SuccessResponse[dmrest.MetricsHistoryResponse]
+	err = dm.doRequest(ctx, decisionMaker, token, http.MethodGet, "/api/v1/metrics/history?"+query.Encode(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, nil
+	}
+
+	samples := make([]*domain.NodeMetricSample, 0, len(resp.Data.Samples))
+	for _, sample := range resp.Data.Samples {
+		timestamp, err := time.Parse(time.RFC3339Nano, sample.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("decision maker %s returned an invalid sample timestamp %q: %w", decisionMaker, sample.Timestamp, err)
+		}
+		samples = append(samples, &domain.NodeMetricSample{
+			Timestamp:           timestamp.UnixMilli(),
+			NrQueued:            sample.NrQueued,
+			NrScheduled:         sample.NrScheduled,
+			NrRunning:           sample.NrRunning,
+			NrOnlineCPUs:        sample.NrOnlineCPUs,
+			NrUserDispatches:    sample.NrUserDispatches,
+			NrKernelDispatches:  sample.NrKernelDispatches,
+			NrCancelDispatches:  sample.NrCancelDispatches,
+			NrBounceDispatches:  sample.NrBounceDispatches,
+			NrFailedDispatches:  sample.NrFailedDispatches,
+			NrSchedCongested:    sample.NrSchedCongested,
+			DispatchRate:        sample.DispatchRate,
+			FailedDispatchRatio: sample.FailedDispatchRatio,
+		})
+	}
+	return samples, nil
+}
+
+// ProbeDecisionMaker reads the /health and /version endpoints of the decision maker, they need no token
+func (dm *DecisionMakerClient) ProbeDecisionMaker(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (*domain.DecisionMakerStatus, error) {
+	var health dmrest.HealthResponse
+	err := dm.doRequest(ctx, decisionMaker, "", http.MethodGet, "/health", nil, &health)
+	if err != nil {
+		return nil, err
+	}
+	var version dmrest.VersionResponse
+	err = dm.doRequest(ctx, decisionMaker, "", http.MethodGet, "/version", nil, &version)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DecisionMakerStatus{
+		Health:  health.Status,
+		Version: version.Version,
+	}, nil
+}
+
+// doRequest sends payload, unless it is nil, as JSON to the decision maker and decodes the response into result unless it is nil
+func (dm *DecisionMakerClient) doRequest(ctx context.Context, decisionMaker *domain.DecisionMakerPod, token string, method string, path string, payload any, result any) error {
+	var body io.Reader
+	if payload != nil {
+		jsonBody, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(jsonBody)
+	}
+	endpoint := "http://" + decisionMaker.Host + ":" + strconv.Itoa(decisionMaker.Port) + path
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 	resp, err := dm.Client.Do(req)
 	if err != nil {
 		return err
@@ -76,7 +208,10 @@ func (dm *DecisionMakerClient) SendSchedulingIntent(ctx context.Context, decisio
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("decision maker %s returned non-OK status: %s", decisionMaker, resp.Status)
 	}
-	return nil
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
 }
 
 func (dm *DecisionMakerClient) GetToken(ctx context.Context, decisionMaker *domain.DecisionMakerPod) (string, error) {
@@ -113,7 +248,8 @@ func (dm *DecisionMakerClient) GetToken(ctx context.Context, decisionMaker *doma
 		return "", err
 	}
 
-	ttl := time.Now().Unix() - tokenResp.Data.ExpiredAt - 60
+	// renew the token a minute before it expires
+	ttl := tokenResp.Data.ExpiredAt - time.Now().Unix() - 60
 
 	dm.tokenCache.Set(decisionMaker.NodeID, tokenResp.Data.Token, cache.WithExpiration(time.Duration(ttl)*time.Second))
 	return tokenResp.Data.Token, nil
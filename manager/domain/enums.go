@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type PermissionKey string
 
 const (
@@ -14,7 +19,10 @@ const (
 	PermissionRead         PermissionKey = "permission.read"
 	ScheduleStrategyCreate PermissionKey = "schedule_strategy.create"
 	ScheduleStrategyRead   PermissionKey = "schedule_strategy.read"
+	ScheduleStrategyUpdate PermissionKey = "schedule_strategy.update"
+	ScheduleStrategyDelete PermissionKey = "schedule_strategy.delete"
 	ScheduleIntentRead     PermissionKey = "schedule_intent.read"
+	NodeRead               PermissionKey = "node.read"
 )
 
 const (
@@ -29,10 +37,85 @@ const (
 	NodeStateOffline
 )
 
+var nodeStateNames = map[NodeState]string{
+	NodeStateUnknown: "unknown",
+	NodeStateOnline:  "online",
+	NodeStateOffline: "offline",
+}
+
+func (s NodeState) String() string {
+	if name, ok := nodeStateNames[s]; ok {
+		return name
+	}
+	return nodeStateNames[NodeStateUnknown]
+}
+
 type IntentState int8
 
 const (
 	IntentStateUnknown IntentState = iota
 	IntentStateInitialized
 	IntentStateSent
+	IntentStateRetracting
+	IntentStateApplied
+	IntentStateFailed
+	IntentStatePodNotFound
+	// IntentStateOverridden marks an intent losing its pod to a strategy of higher precedence, it is not delivered
+	IntentStateOverridden
+)
+
+var intentStateNames = map[IntentState]string{
+	IntentStateUnknown:     "unknown",
+	IntentStateInitialized: "initialized",
+	IntentStateSent:        "sent",
+	IntentStateRetracting:  "retracting",
+	IntentStateApplied:     "applied",
+	IntentStateFailed:      "failed",
+	IntentStatePodNotFound: "pod_not_found",
+	IntentStateOverridden:  "overridden",
+}
+
+func (s IntentState) String() string {
+	if name, ok := intentStateNames[s]; ok {
+		return name
+	}
+	return intentStateNames[IntentStateUnknown]
+}
+
+// ParseIntentState converts the name of an intent state, as returned by IntentState.String, back to the state
+func ParseIntentState(name string) (IntentState, error) {
+	for state, stateName := range intentStateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return IntentStateUnknown, fmt.Errorf("unknown intent state %q", name)
+}
+
+// MarshalJSON encodes the state by its name, the one accepted by the state filters
+func (s IntentState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *IntentState) UnmarshalJSON(data []byte) error {
+	var name string
+	err := json.Unmarshal(data, &name)
+	if err != nil {
+		return fmt.Errorf("intent state must be a name: %w", err)
+	}
+	state, err := ParseIntentState(name)
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
+
+type PodEventType int8
+
+const (
+	PodEventUnknown PodEventType = iota
+	PodEventAdded
+	PodEventUpdated
+	PodEventDeleted
 )
@@ -7,4 +7,6 @@ var (
 	ErrNoKubeConfig  = errors.New("kubernetes configuration not provided")
 	ErrNilQueryInput = errors.New("query options is nil")
 	ErrNoClient      = errors.New("kubernetes client is not initialized")
+	// ErrInvalidSelector is returned for label selectors or match expressions kubernetes does not accept
+	ErrInvalidSelector = errors.New("invalid pod selector")
 )
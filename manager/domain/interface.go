@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -47,6 +48,13 @@ type QueryIntentOptions struct {
 	PodIDs        []string
 	Result        []*ScheduleIntent
 	CreatorIDs    []bson.ObjectID
+	// DueBefore only matches intents whose next delivery attempt is due at the given unix time in milliseconds
+	DueBefore int64
+}
+
+type QueryNodeOptions struct {
+	NodeIDs []string
+	Result  []*Node
 }
 
 type Repository interface {
@@ -63,9 +71,25 @@ type Repository interface {
 	QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error
 
 	InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error
+	UpdateStrategy(ctx context.Context, strategy *ScheduleStrategy) error
+	DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error
+	InsertIntents(ctx context.Context, intents []*ScheduleIntent) error
+	UpdateIntents(ctx context.Context, intents []*ScheduleIntent) error
+	DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error
 	BatchUpdateIntentsState(ctx context.Context, intentIDs []bson.ObjectID, newState IntentState) error
+	MarkIntentsDelivered(ctx context.Context, intentIDs []bson.ObjectID) error
+	BatchUpdateIntentsResult(ctx context.Context, results []*ScheduleIntentResult) error
+	MarkIntentsDeliveryFailed(ctx context.Context, intentIDs []bson.ObjectID, lastError string, nextAttemptTime int64) error
+	// ResolveIntents applies the resolutions and returns the IDs of the intents they applied to
+	ResolveIntents(ctx context.Context, resolutions []*IntentResolution, now int64) ([]bson.ObjectID, error)
+	// ClaimIntents leases due initialized or retracting intents for delivery until leaseUntil and returns those claimed
+	ClaimIntents(ctx context.Context, intentIDs []bson.ObjectID, now, leaseUntil int64) ([]*ScheduleIntent, error)
 	QueryStrategies(ctx context.Context, opt *QueryStrategyOptions) error
 	QueryIntents(ctx context.Context, opt *QueryIntentOptions) error
+	CountIntentsByNode(ctx context.Context, nodeIDs []string) (map[string]map[IntentState]int, error)
+
+	UpsertNodes(ctx context.Context, nodes []*Node) error
+	QueryNodes(ctx context.Context, opt *QueryNodeOptions) error
 }
 
 type Service interface {
@@ -84,28 +108,54 @@ type Service interface {
 	QueryRoles(ctx context.Context, opt *QueryRoleOptions) error
 	QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error
 
-	CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error
+	CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) ([]*StrategyConflict, error)
+	PreviewScheduleStrategy(ctx context.Context, strategy *ScheduleStrategy) (*StrategyPreview, error)
+	UpdateScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error
+	DeleteScheduleStrategy(ctx context.Context, operator *Claims, strategyID string) error
 	ListScheduleStrategies(ctx context.Context, filterOpts *QueryStrategyOptions) error
 	ListScheduleIntents(ctx context.Context, filterOpts *QueryIntentOptions) error
+	ReconcileScheduleStrategies(ctx context.Context) error
+	RunStrategyReconciler(ctx context.Context) error
+	DeliverPendingIntents(ctx context.Context) error
+	RunIntentDeliveryWorker(ctx context.Context) error
+
+	ListNodeMetrics(ctx context.Context) (*ClusterMetrics, error)
+	ScrapeNodeMetrics(ctx context.Context) error
+	RunNodeMetricsScraper(ctx context.Context) error
+
+	ListNodes(ctx context.Context, opt *QueryNodeOptions) error
+	GetNode(ctx context.Context, nodeID string) (*Node, error)
+	SyncNodeRegistry(ctx context.Context) error
+	RunNodeRegistry(ctx context.Context) error
 }
 
 type QueryPodsOptions struct {
-	K8SNamespace   []string
-	LabelSelectors []LabelSelector
-	CommandRegex   string
+	K8SNamespace     []string
+	LabelSelectors   []LabelSelector
+	MatchExpressions []MatchExpression
+	FieldFilter      *PodFieldFilter
+	CommandRegex     string
 }
 
 type QueryDecisionMakerPodsOptions struct {
-	K8SNamespace       []string
-	NodeIDs            []string
-	DecisionMakerLabel LabelSelector
+	K8SNamespace        []string
+	NodeIDs             []string
+	DecisionMakerLabels []LabelSelector
+	// PortName is the named container port the decision maker listens on, the first port is taken when empty
+	PortName string
+	// ServiceName resolves the decision makers through the EndpointSlices of this Service instead of their pods
+	ServiceName string
 }
 
 type K8SAdapter interface {
 	QueryPods(ctx context.Context, opt *QueryPodsOptions) ([]*Pod, error)
 	QueryDecisionMakerPods(ctx context.Context, opt *QueryDecisionMakerPodsOptions) ([]*DecisionMakerPod, error)
+	SubscribePodEvents(ctx context.Context) (<-chan PodEvent, error)
 }
 
 type DecisionMakerAdapter interface {
-	SendSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error
+	SendSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) ([]*ScheduleIntentResult, error)
+	DeleteSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error
+	GetMetricsHistory(ctx context.Context, decisionMaker *DecisionMakerPod, from, to time.Time) ([]*NodeMetricSample, error)
+	ProbeDecisionMaker(ctx context.Context, decisionMaker *DecisionMakerPod) (*DecisionMakerStatus, error)
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "strconv"
+
 type DecisionMakerPod struct {
 	NodeID string
 	Port   int
@@ -8,7 +10,7 @@ type DecisionMakerPod struct {
 }
 
 func (d *DecisionMakerPod) String() string {
-	return "(" + d.NodeID + ")" + d.Host + ":" + string(rune(d.Port))
+	return "(" + d.NodeID + ")" + d.Host + ":" + strconv.Itoa(d.Port)
 }
 
 type Pod struct {
@@ -31,6 +33,12 @@ func (p *Pod) LabelsToSelectors() []LabelSelector {
 	return selectors
 }
 
+// PodEvent is published by the K8SAdapter whenever the pod informer observes a relevant pod change
+type PodEvent struct {
+	Type PodEventType
+	Pod  *Pod
+}
+
 type Container struct {
 	ContainerID string
 	Name        string
@@ -6,6 +6,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -38,6 +39,63 @@ func (_m *MockRepository) EXPECT() *MockRepository_Expecter {
 	return &MockRepository_Expecter{mock: &_m.Mock}
 }
 
+// BatchUpdateIntentsResult provides a mock function for the type MockRepository
+func (_mock *MockRepository) BatchUpdateIntentsResult(ctx context.Context, results []*ScheduleIntentResult) error {
+	ret := _mock.Called(ctx, results)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchUpdateIntentsResult")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*ScheduleIntentResult) error); ok {
+		r0 = returnFunc(ctx, results)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_BatchUpdateIntentsResult_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BatchUpdateIntentsResult'
+type MockRepository_BatchUpdateIntentsResult_Call struct {
+	*mock.Call
+}
+
+// BatchUpdateIntentsResult is a helper method to define mock.On call
+//   - ctx context.Context
+//   - results []*ScheduleIntentResult
+func (_e *MockRepository_Expecter) BatchUpdateIntentsResult(ctx interface{}, results interface{}) *MockRepository_BatchUpdateIntentsResult_Call {
+	return &MockRepository_BatchUpdateIntentsResult_Call{Call: _e.mock.On("BatchUpdateIntentsResult", ctx, results)}
+}
+
+func (_c *MockRepository_BatchUpdateIntentsResult_Call) Run(run func(ctx context.Context, results []*ScheduleIntentResult)) *MockRepository_BatchUpdateIntentsResult_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*ScheduleIntentResult
+		if args[1] != nil {
+			arg1 = args[1].([]*ScheduleIntentResult)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_BatchUpdateIntentsResult_Call) Return(err error) *MockRepository_BatchUpdateIntentsResult_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_BatchUpdateIntentsResult_Call) RunAndReturn(run func(ctx context.Context, results []*ScheduleIntentResult) error) *MockRepository_BatchUpdateIntentsResult_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BatchUpdateIntentsState provides a mock function for the type MockRepository
 func (_mock *MockRepository) BatchUpdateIntentsState(ctx context.Context, intentIDs []bson.ObjectID, newState IntentState) error {
 	ret := _mock.Called(ctx, intentIDs, newState)
@@ -101,6 +159,154 @@ func (_c *MockRepository_BatchUpdateIntentsState_Call) RunAndReturn(run func(ctx
 	return _c
 }
 
+// ClaimIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) ClaimIntents(ctx context.Context, intentIDs []bson.ObjectID, now int64, leaseUntil int64) ([]*ScheduleIntent, error) {
+	ret := _mock.Called(ctx, intentIDs, now, leaseUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimIntents")
+	}
+
+	var r0 []*ScheduleIntent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID, int64, int64) ([]*ScheduleIntent, error)); ok {
+		return returnFunc(ctx, intentIDs, now, leaseUntil)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID, int64, int64) []*ScheduleIntent); ok {
+		r0 = returnFunc(ctx, intentIDs, now, leaseUntil)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ScheduleIntent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []bson.ObjectID, int64, int64) error); ok {
+		r1 = returnFunc(ctx, intentIDs, now, leaseUntil)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_ClaimIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimIntents'
+type MockRepository_ClaimIntents_Call struct {
+	*mock.Call
+}
+
+// ClaimIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - intentIDs []bson.ObjectID
+//   - now int64
+//   - leaseUntil int64
+func (_e *MockRepository_Expecter) ClaimIntents(ctx interface{}, intentIDs interface{}, now interface{}, leaseUntil interface{}) *MockRepository_ClaimIntents_Call {
+	return &MockRepository_ClaimIntents_Call{Call: _e.mock.On("ClaimIntents", ctx, intentIDs, now, leaseUntil)}
+}
+
+func (_c *MockRepository_ClaimIntents_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID, now int64, leaseUntil int64)) *MockRepository_ClaimIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []bson.ObjectID
+		if args[1] != nil {
+			arg1 = args[1].([]bson.ObjectID)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_ClaimIntents_Call) Return(scheduleIntents []*ScheduleIntent, err error) *MockRepository_ClaimIntents_Call {
+	_c.Call.Return(scheduleIntents, err)
+	return _c
+}
+
+func (_c *MockRepository_ClaimIntents_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID, now int64, leaseUntil int64) ([]*ScheduleIntent, error)) *MockRepository_ClaimIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountIntentsByNode provides a mock function for the type MockRepository
+func (_mock *MockRepository) CountIntentsByNode(ctx context.Context, nodeIDs []string) (map[string]map[IntentState]int, error) {
+	ret := _mock.Called(ctx, nodeIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountIntentsByNode")
+	}
+
+	var r0 map[string]map[IntentState]int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) (map[string]map[IntentState]int, error)); ok {
+		return returnFunc(ctx, nodeIDs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) map[string]map[IntentState]int); ok {
+		r0 = returnFunc(ctx, nodeIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[IntentState]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, nodeIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_CountIntentsByNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountIntentsByNode'
+type MockRepository_CountIntentsByNode_Call struct {
+	*mock.Call
+}
+
+// CountIntentsByNode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodeIDs []string
+func (_e *MockRepository_Expecter) CountIntentsByNode(ctx interface{}, nodeIDs interface{}) *MockRepository_CountIntentsByNode_Call {
+	return &MockRepository_CountIntentsByNode_Call{Call: _e.mock.On("CountIntentsByNode", ctx, nodeIDs)}
+}
+
+func (_c *MockRepository_CountIntentsByNode_Call) Run(run func(ctx context.Context, nodeIDs []string)) *MockRepository_CountIntentsByNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_CountIntentsByNode_Call) Return(mapParam map[string]map[IntentState]int, err error) *MockRepository_CountIntentsByNode_Call {
+	_c.Call.Return(mapParam, err)
+	return _c
+}
+
+func (_c *MockRepository_CountIntentsByNode_Call) RunAndReturn(run func(ctx context.Context, nodeIDs []string) (map[string]map[IntentState]int, error)) *MockRepository_CountIntentsByNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateAuditLog provides a mock function for the type MockRepository
 func (_mock *MockRepository) CreateAuditLog(ctx context.Context, log *AuditLog) error {
 	ret := _mock.Called(ctx, log)
@@ -329,107 +535,101 @@ func (_c *MockRepository_CreateUser_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
-// InsertStrategyAndIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error {
-	ret := _mock.Called(ctx, strategy, intents)
+// DeleteIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error {
+	ret := _mock.Called(ctx, intentIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for InsertStrategyAndIntents")
+		panic("no return value specified for DeleteIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy, []*ScheduleIntent) error); ok {
-		r0 = returnFunc(ctx, strategy, intents)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, intentIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_InsertStrategyAndIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertStrategyAndIntents'
-type MockRepository_InsertStrategyAndIntents_Call struct {
+// MockRepository_DeleteIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIntents'
+type MockRepository_DeleteIntents_Call struct {
 	*mock.Call
 }
 
-// InsertStrategyAndIntents is a helper method to define mock.On call
+// DeleteIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - strategy *ScheduleStrategy
-//   - intents []*ScheduleIntent
-func (_e *MockRepository_Expecter) InsertStrategyAndIntents(ctx interface{}, strategy interface{}, intents interface{}) *MockRepository_InsertStrategyAndIntents_Call {
-	return &MockRepository_InsertStrategyAndIntents_Call{Call: _e.mock.On("InsertStrategyAndIntents", ctx, strategy, intents)}
+//   - intentIDs []bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteIntents(ctx interface{}, intentIDs interface{}) *MockRepository_DeleteIntents_Call {
+	return &MockRepository_DeleteIntents_Call{Call: _e.mock.On("DeleteIntents", ctx, intentIDs)}
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent)) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_DeleteIntents_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID)) *MockRepository_DeleteIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *ScheduleStrategy
+		var arg1 []bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*ScheduleStrategy)
-		}
-		var arg2 []*ScheduleIntent
-		if args[2] != nil {
-			arg2 = args[2].([]*ScheduleIntent)
+			arg1 = args[1].([]bson.ObjectID)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) Return(err error) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_DeleteIntents_Call) Return(err error) *MockRepository_DeleteIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_InsertStrategyAndIntents_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error) *MockRepository_InsertStrategyAndIntents_Call {
+func (_c *MockRepository_DeleteIntents_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID) error) *MockRepository_DeleteIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryAuditLogs provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error {
-	ret := _mock.Called(ctx, opt)
+// DeleteStrategy provides a mock function for the type MockRepository
+func (_mock *MockRepository) DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error {
+	ret := _mock.Called(ctx, strategyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryAuditLogs")
+		panic("no return value specified for DeleteStrategy")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAuditLogOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, strategyID)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAuditLogs'
-type MockRepository_QueryAuditLogs_Call struct {
+// MockRepository_DeleteStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStrategy'
+type MockRepository_DeleteStrategy_Call struct {
 	*mock.Call
 }
 
-// QueryAuditLogs is a helper method to define mock.On call
+// DeleteStrategy is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryAuditLogOptions
-func (_e *MockRepository_Expecter) QueryAuditLogs(ctx interface{}, opt interface{}) *MockRepository_QueryAuditLogs_Call {
-	return &MockRepository_QueryAuditLogs_Call{Call: _e.mock.On("QueryAuditLogs", ctx, opt)}
+//   - strategyID bson.ObjectID
+func (_e *MockRepository_Expecter) DeleteStrategy(ctx interface{}, strategyID interface{}) *MockRepository_DeleteStrategy_Call {
+	return &MockRepository_DeleteStrategy_Call{Call: _e.mock.On("DeleteStrategy", ctx, strategyID)}
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context, opt *QueryAuditLogOptions)) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_DeleteStrategy_Call) Run(run func(ctx context.Context, strategyID bson.ObjectID)) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryAuditLogOptions
+		var arg1 bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryAuditLogOptions)
+			arg1 = args[1].(bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -439,54 +639,54 @@ func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context,
 	return _c
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) Return(err error) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_DeleteStrategy_Call) Return(err error) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryAuditLogs_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAuditLogOptions) error) *MockRepository_QueryAuditLogs_Call {
+func (_c *MockRepository_DeleteStrategy_Call) RunAndReturn(run func(ctx context.Context, strategyID bson.ObjectID) error) *MockRepository_DeleteStrategy_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryIntents provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryIntents(ctx context.Context, opt *QueryIntentOptions) error {
-	ret := _mock.Called(ctx, opt)
+// InsertIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) InsertIntents(ctx context.Context, intents []*ScheduleIntent) error {
+	ret := _mock.Called(ctx, intents)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryIntents")
+		panic("no return value specified for InsertIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*ScheduleIntent) error); ok {
+		r0 = returnFunc(ctx, intents)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryIntents'
-type MockRepository_QueryIntents_Call struct {
+// MockRepository_InsertIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertIntents'
+type MockRepository_InsertIntents_Call struct {
 	*mock.Call
 }
 
-// QueryIntents is a helper method to define mock.On call
+// InsertIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryIntentOptions
-func (_e *MockRepository_Expecter) QueryIntents(ctx interface{}, opt interface{}) *MockRepository_QueryIntents_Call {
-	return &MockRepository_QueryIntents_Call{Call: _e.mock.On("QueryIntents", ctx, opt)}
+//   - intents []*ScheduleIntent
+func (_e *MockRepository_Expecter) InsertIntents(ctx interface{}, intents interface{}) *MockRepository_InsertIntents_Call {
+	return &MockRepository_InsertIntents_Call{Call: _e.mock.On("InsertIntents", ctx, intents)}
 }
 
-func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, opt *QueryIntentOptions)) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_InsertIntents_Call) Run(run func(ctx context.Context, intents []*ScheduleIntent)) *MockRepository_InsertIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryIntentOptions
+		var arg1 []*ScheduleIntent
 		if args[1] != nil {
-			arg1 = args[1].(*QueryIntentOptions)
+			arg1 = args[1].([]*ScheduleIntent)
 		}
 		run(
 			arg0,
@@ -496,111 +696,117 @@ func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, op
 	return _c
 }
 
-func (_c *MockRepository_QueryIntents_Call) Return(err error) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_InsertIntents_Call) Return(err error) *MockRepository_InsertIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryIntents_Call) RunAndReturn(run func(ctx context.Context, opt *QueryIntentOptions) error) *MockRepository_QueryIntents_Call {
+func (_c *MockRepository_InsertIntents_Call) RunAndReturn(run func(ctx context.Context, intents []*ScheduleIntent) error) *MockRepository_InsertIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryPermissions provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
-	ret := _mock.Called(ctx, opt)
+// InsertStrategyAndIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) InsertStrategyAndIntents(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error {
+	ret := _mock.Called(ctx, strategy, intents)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryPermissions")
+		panic("no return value specified for InsertStrategyAndIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy, []*ScheduleIntent) error); ok {
+		r0 = returnFunc(ctx, strategy, intents)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
-type MockRepository_QueryPermissions_Call struct {
+// MockRepository_InsertStrategyAndIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertStrategyAndIntents'
+type MockRepository_InsertStrategyAndIntents_Call struct {
 	*mock.Call
 }
 
-// QueryPermissions is a helper method to define mock.On call
+// InsertStrategyAndIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryPermissionOptions
-func (_e *MockRepository_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockRepository_QueryPermissions_Call {
-	return &MockRepository_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+//   - strategy *ScheduleStrategy
+//   - intents []*ScheduleIntent
+func (_e *MockRepository_Expecter) InsertStrategyAndIntents(ctx interface{}, strategy interface{}, intents interface{}) *MockRepository_InsertStrategyAndIntents_Call {
+	return &MockRepository_InsertStrategyAndIntents_Call{Call: _e.mock.On("InsertStrategyAndIntents", ctx, strategy, intents)}
 }
 
-func (_c *MockRepository_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent)) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryPermissionOptions
+		var arg1 *ScheduleStrategy
 		if args[1] != nil {
-			arg1 = args[1].(*QueryPermissionOptions)
+			arg1 = args[1].(*ScheduleStrategy)
+		}
+		var arg2 []*ScheduleIntent
+		if args[2] != nil {
+			arg2 = args[2].([]*ScheduleIntent)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_QueryPermissions_Call) Return(err error) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) Return(err error) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockRepository_QueryPermissions_Call {
+func (_c *MockRepository_InsertStrategyAndIntents_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy, intents []*ScheduleIntent) error) *MockRepository_InsertStrategyAndIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryRoles provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
-	ret := _mock.Called(ctx, opt)
+// MarkIntentsDelivered provides a mock function for the type MockRepository
+func (_mock *MockRepository) MarkIntentsDelivered(ctx context.Context, intentIDs []bson.ObjectID) error {
+	ret := _mock.Called(ctx, intentIDs)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRoles")
+		panic("no return value specified for MarkIntentsDelivered")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID) error); ok {
+		r0 = returnFunc(ctx, intentIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
-type MockRepository_QueryRoles_Call struct {
+// MockRepository_MarkIntentsDelivered_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkIntentsDelivered'
+type MockRepository_MarkIntentsDelivered_Call struct {
 	*mock.Call
 }
 
-// QueryRoles is a helper method to define mock.On call
+// MarkIntentsDelivered is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryRoleOptions
-func (_e *MockRepository_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockRepository_QueryRoles_Call {
-	return &MockRepository_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+//   - intentIDs []bson.ObjectID
+func (_e *MockRepository_Expecter) MarkIntentsDelivered(ctx interface{}, intentIDs interface{}) *MockRepository_MarkIntentsDelivered_Call {
+	return &MockRepository_MarkIntentsDelivered_Call{Call: _e.mock.On("MarkIntentsDelivered", ctx, intentIDs)}
 }
 
-func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_MarkIntentsDelivered_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID)) *MockRepository_MarkIntentsDelivered_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryRoleOptions
+		var arg1 []bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryRoleOptions)
+			arg1 = args[1].([]bson.ObjectID)
 		}
 		run(
 			arg0,
@@ -610,83 +816,95 @@ func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt
 	return _c
 }
 
-func (_c *MockRepository_QueryRoles_Call) Return(err error) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_MarkIntentsDelivered_Call) Return(err error) *MockRepository_MarkIntentsDelivered_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockRepository_QueryRoles_Call {
+func (_c *MockRepository_MarkIntentsDelivered_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID) error) *MockRepository_MarkIntentsDelivered_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryStrategies provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryStrategies(ctx context.Context, opt *QueryStrategyOptions) error {
-	ret := _mock.Called(ctx, opt)
+// MarkIntentsDeliveryFailed provides a mock function for the type MockRepository
+func (_mock *MockRepository) MarkIntentsDeliveryFailed(ctx context.Context, intentIDs []bson.ObjectID, lastError string, nextAttemptTime int64) error {
+	ret := _mock.Called(ctx, intentIDs, lastError, nextAttemptTime)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryStrategies")
+		panic("no return value specified for MarkIntentsDeliveryFailed")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []bson.ObjectID, string, int64) error); ok {
+		r0 = returnFunc(ctx, intentIDs, lastError, nextAttemptTime)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_QueryStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryStrategies'
-type MockRepository_QueryStrategies_Call struct {
+// MockRepository_MarkIntentsDeliveryFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkIntentsDeliveryFailed'
+type MockRepository_MarkIntentsDeliveryFailed_Call struct {
 	*mock.Call
 }
 
-// QueryStrategies is a helper method to define mock.On call
+// MarkIntentsDeliveryFailed is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryStrategyOptions
-func (_e *MockRepository_Expecter) QueryStrategies(ctx interface{}, opt interface{}) *MockRepository_QueryStrategies_Call {
-	return &MockRepository_QueryStrategies_Call{Call: _e.mock.On("QueryStrategies", ctx, opt)}
+//   - intentIDs []bson.ObjectID
+//   - lastError string
+//   - nextAttemptTime int64
+func (_e *MockRepository_Expecter) MarkIntentsDeliveryFailed(ctx interface{}, intentIDs interface{}, lastError interface{}, nextAttemptTime interface{}) *MockRepository_MarkIntentsDeliveryFailed_Call {
+	return &MockRepository_MarkIntentsDeliveryFailed_Call{Call: _e.mock.On("MarkIntentsDeliveryFailed", ctx, intentIDs, lastError, nextAttemptTime)}
 }
 
-func (_c *MockRepository_QueryStrategies_Call) Run(run func(ctx context.Context, opt *QueryStrategyOptions)) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_MarkIntentsDeliveryFailed_Call) Run(run func(ctx context.Context, intentIDs []bson.ObjectID, lastError string, nextAttemptTime int64)) *MockRepository_MarkIntentsDeliveryFailed_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryStrategyOptions
+		var arg1 []bson.ObjectID
 		if args[1] != nil {
-			arg1 = args[1].(*QueryStrategyOptions)
+			arg1 = args[1].([]bson.ObjectID)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 int64
+		if args[3] != nil {
+			arg3 = args[3].(int64)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockRepository_QueryStrategies_Call) Return(err error) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_MarkIntentsDeliveryFailed_Call) Return(err error) *MockRepository_MarkIntentsDeliveryFailed_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryStrategies_Call) RunAndReturn(run func(ctx context.Context, opt *QueryStrategyOptions) error) *MockRepository_QueryStrategies_Call {
+func (_c *MockRepository_MarkIntentsDeliveryFailed_Call) RunAndReturn(run func(ctx context.Context, intentIDs []bson.ObjectID, lastError string, nextAttemptTime int64) error) *MockRepository_MarkIntentsDeliveryFailed_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryUsers provides a mock function for the type MockRepository
-func (_mock *MockRepository) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
+// QueryAuditLogs provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryAuditLogs(ctx context.Context, opt *QueryAuditLogOptions) error {
 	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryUsers")
+		panic("no return value specified for QueryAuditLogs")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryAuditLogOptions) error); ok {
 		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
@@ -694,27 +912,27 @@ func (_mock *MockRepository) QueryUsers(ctx context.Context, opt *QueryUserOptio
 	return r0
 }
 
-// MockRepository_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
-type MockRepository_QueryUsers_Call struct {
+// MockRepository_QueryAuditLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryAuditLogs'
+type MockRepository_QueryAuditLogs_Call struct {
 	*mock.Call
 }
 
-// QueryUsers is a helper method to define mock.On call
+// QueryAuditLogs is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryUserOptions
-func (_e *MockRepository_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockRepository_QueryUsers_Call {
-	return &MockRepository_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+//   - opt *QueryAuditLogOptions
+func (_e *MockRepository_Expecter) QueryAuditLogs(ctx interface{}, opt interface{}) *MockRepository_QueryAuditLogs_Call {
+	return &MockRepository_QueryAuditLogs_Call{Call: _e.mock.On("QueryAuditLogs", ctx, opt)}
 }
 
-func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_QueryAuditLogs_Call) Run(run func(ctx context.Context, opt *QueryAuditLogOptions)) *MockRepository_QueryAuditLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryUserOptions
+		var arg1 *QueryAuditLogOptions
 		if args[1] != nil {
-			arg1 = args[1].(*QueryUserOptions)
+			arg1 = args[1].(*QueryAuditLogOptions)
 		}
 		run(
 			arg0,
@@ -724,54 +942,54 @@ func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt
 	return _c
 }
 
-func (_c *MockRepository_QueryUsers_Call) Return(err error) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_QueryAuditLogs_Call) Return(err error) *MockRepository_QueryAuditLogs_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockRepository_QueryUsers_Call {
+func (_c *MockRepository_QueryAuditLogs_Call) RunAndReturn(run func(ctx context.Context, opt *QueryAuditLogOptions) error) *MockRepository_QueryAuditLogs_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdatePermission provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdatePermission(ctx context.Context, permission *Permission) error {
-	ret := _mock.Called(ctx, permission)
+// QueryIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryIntents(ctx context.Context, opt *QueryIntentOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdatePermission")
+		panic("no return value specified for QueryIntents")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
-		r0 = returnFunc(ctx, permission)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePermission'
-type MockRepository_UpdatePermission_Call struct {
+// MockRepository_QueryIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryIntents'
+type MockRepository_QueryIntents_Call struct {
 	*mock.Call
 }
 
-// UpdatePermission is a helper method to define mock.On call
+// QueryIntents is a helper method to define mock.On call
 //   - ctx context.Context
-//   - permission *Permission
-func (_e *MockRepository_Expecter) UpdatePermission(ctx interface{}, permission interface{}) *MockRepository_UpdatePermission_Call {
-	return &MockRepository_UpdatePermission_Call{Call: _e.mock.On("UpdatePermission", ctx, permission)}
+//   - opt *QueryIntentOptions
+func (_e *MockRepository_Expecter) QueryIntents(ctx interface{}, opt interface{}) *MockRepository_QueryIntents_Call {
+	return &MockRepository_QueryIntents_Call{Call: _e.mock.On("QueryIntents", ctx, opt)}
 }
 
-func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_QueryIntents_Call) Run(run func(ctx context.Context, opt *QueryIntentOptions)) *MockRepository_QueryIntents_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Permission
+		var arg1 *QueryIntentOptions
 		if args[1] != nil {
-			arg1 = args[1].(*Permission)
+			arg1 = args[1].(*QueryIntentOptions)
 		}
 		run(
 			arg0,
@@ -781,54 +999,54 @@ func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockRepository_UpdatePermission_Call) Return(err error) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_QueryIntents_Call) Return(err error) *MockRepository_QueryIntents_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_UpdatePermission_Call {
+func (_c *MockRepository_QueryIntents_Call) RunAndReturn(run func(ctx context.Context, opt *QueryIntentOptions) error) *MockRepository_QueryIntents_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateRole provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdateRole(ctx context.Context, role *Role) error {
-	ret := _mock.Called(ctx, role)
+// QueryNodes provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryNodes(ctx context.Context, opt *QueryNodeOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateRole")
+		panic("no return value specified for QueryNodes")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
-		r0 = returnFunc(ctx, role)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryNodeOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
-type MockRepository_UpdateRole_Call struct {
+// MockRepository_QueryNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryNodes'
+type MockRepository_QueryNodes_Call struct {
 	*mock.Call
 }
 
-// UpdateRole is a helper method to define mock.On call
+// QueryNodes is a helper method to define mock.On call
 //   - ctx context.Context
-//   - role *Role
-func (_e *MockRepository_Expecter) UpdateRole(ctx interface{}, role interface{}) *MockRepository_UpdateRole_Call {
-	return &MockRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, role)}
+//   - opt *QueryNodeOptions
+func (_e *MockRepository_Expecter) QueryNodes(ctx interface{}, opt interface{}) *MockRepository_QueryNodes_Call {
+	return &MockRepository_QueryNodes_Call{Call: _e.mock.On("QueryNodes", ctx, opt)}
 }
 
-func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_QueryNodes_Call) Run(run func(ctx context.Context, opt *QueryNodeOptions)) *MockRepository_QueryNodes_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Role
+		var arg1 *QueryNodeOptions
 		if args[1] != nil {
-			arg1 = args[1].(*Role)
+			arg1 = args[1].(*QueryNodeOptions)
 		}
 		run(
 			arg0,
@@ -838,54 +1056,54 @@ func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role
 	return _c
 }
 
-func (_c *MockRepository_UpdateRole_Call) Return(err error) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_QueryNodes_Call) Return(err error) *MockRepository_QueryNodes_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_UpdateRole_Call {
+func (_c *MockRepository_QueryNodes_Call) RunAndReturn(run func(ctx context.Context, opt *QueryNodeOptions) error) *MockRepository_QueryNodes_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUser provides a mock function for the type MockRepository
-func (_mock *MockRepository) UpdateUser(ctx context.Context, user *User) error {
-	ret := _mock.Called(ctx, user)
+// QueryPermissions provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateUser")
+		panic("no return value specified for QueryPermissions")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
-		r0 = returnFunc(ctx, user)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockRepository_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
-type MockRepository_UpdateUser_Call struct {
+// MockRepository_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
+type MockRepository_QueryPermissions_Call struct {
 	*mock.Call
 }
 
-// UpdateUser is a helper method to define mock.On call
+// QueryPermissions is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *User
-func (_e *MockRepository_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockRepository_UpdateUser_Call {
-	return &MockRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+//   - opt *QueryPermissionOptions
+func (_e *MockRepository_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockRepository_QueryPermissions_Call {
+	return &MockRepository_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
 }
 
-func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockRepository_QueryPermissions_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *User
+		var arg1 *QueryPermissionOptions
 		if args[1] != nil {
-			arg1 = args[1].(*User)
+			arg1 = args[1].(*QueryPermissionOptions)
 		}
 		run(
 			arg0,
@@ -895,160 +1113,747 @@ func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user
 	return _c
 }
 
-func (_c *MockRepository_UpdateUser_Call) Return(err error) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_QueryPermissions_Call) Return(err error) *MockRepository_QueryPermissions_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockRepository_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_UpdateUser_Call {
+func (_c *MockRepository_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockRepository_QueryPermissions_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockService(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockService {
-	mock := &MockService{}
-	mock.Mock.Test(t)
-
-	t.Cleanup(func() { mock.AssertExpectations(t) })
-
-	return mock
-}
-
-// MockService is an autogenerated mock type for the Service type
-type MockService struct {
-	mock.Mock
-}
-
-type MockService_Expecter struct {
-	mock *mock.Mock
-}
-
-func (_m *MockService) EXPECT() *MockService_Expecter {
-	return &MockService_Expecter{mock: &_m.Mock}
-}
-
-// ChangePassword provides a mock function for the type MockService
-func (_mock *MockService) ChangePassword(ctx context.Context, user *Claims, oldPassword string, newPassword string) error {
-	ret := _mock.Called(ctx, user, oldPassword, newPassword)
+// QueryRoles provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ChangePassword")
+		panic("no return value specified for QueryRoles")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, user, oldPassword, newPassword)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
-type MockService_ChangePassword_Call struct {
+// MockRepository_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
+type MockRepository_QueryRoles_Call struct {
 	*mock.Call
 }
 
-// ChangePassword is a helper method to define mock.On call
+// QueryRoles is a helper method to define mock.On call
 //   - ctx context.Context
-//   - user *Claims
-//   - oldPassword string
-//   - newPassword string
-func (_e *MockService_Expecter) ChangePassword(ctx interface{}, user interface{}, oldPassword interface{}, newPassword interface{}) *MockService_ChangePassword_Call {
-	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, user, oldPassword, newPassword)}
+//   - opt *QueryRoleOptions
+func (_e *MockRepository_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockRepository_QueryRoles_Call {
+	return &MockRepository_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
 }
 
-func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string)) *MockService_ChangePassword_Call {
+func (_c *MockRepository_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockRepository_QueryRoles_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 *QueryRoleOptions
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg1 = args[1].(*QueryRoleOptions)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ChangePassword_Call) Return(err error) *MockService_ChangePassword_Call {
+func (_c *MockRepository_QueryRoles_Call) Return(err error) *MockRepository_QueryRoles_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string) error) *MockService_ChangePassword_Call {
+func (_c *MockRepository_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockRepository_QueryRoles_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateAdminUserIfNotExists provides a mock function for the type MockService
-func (_mock *MockService) CreateAdminUserIfNotExists(ctx context.Context, username string, password string) error {
-	ret := _mock.Called(ctx, username, password)
+// QueryStrategies provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryStrategies(ctx context.Context, opt *QueryStrategyOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateAdminUserIfNotExists")
+		panic("no return value specified for QueryStrategies")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = returnFunc(ctx, username, password)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateAdminUserIfNotExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAdminUserIfNotExists'
-type MockService_CreateAdminUserIfNotExists_Call struct {
+// MockRepository_QueryStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryStrategies'
+type MockRepository_QueryStrategies_Call struct {
 	*mock.Call
 }
 
-// CreateAdminUserIfNotExists is a helper method to define mock.On call
+// QueryStrategies is a helper method to define mock.On call
 //   - ctx context.Context
-//   - username string
-//   - password string
-func (_e *MockService_Expecter) CreateAdminUserIfNotExists(ctx interface{}, username interface{}, password interface{}) *MockService_CreateAdminUserIfNotExists_Call {
-	return &MockService_CreateAdminUserIfNotExists_Call{Call: _e.mock.On("CreateAdminUserIfNotExists", ctx, username, password)}
+//   - opt *QueryStrategyOptions
+func (_e *MockRepository_Expecter) QueryStrategies(ctx interface{}, opt interface{}) *MockRepository_QueryStrategies_Call {
+	return &MockRepository_QueryStrategies_Call{Call: _e.mock.On("QueryStrategies", ctx, opt)}
 }
 
-func (_c *MockService_CreateAdminUserIfNotExists_Call) Run(run func(ctx context.Context, username string, password string)) *MockService_CreateAdminUserIfNotExists_Call {
+func (_c *MockRepository_QueryStrategies_Call) Run(run func(ctx context.Context, opt *QueryStrategyOptions)) *MockRepository_QueryStrategies_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *QueryStrategyOptions
 		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(*QueryStrategyOptions)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryStrategies_Call) Return(err error) *MockRepository_QueryStrategies_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryStrategies_Call) RunAndReturn(run func(ctx context.Context, opt *QueryStrategyOptions) error) *MockRepository_QueryStrategies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryUsers provides a mock function for the type MockRepository
+func (_mock *MockRepository) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryUsers")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
+type MockRepository_QueryUsers_Call struct {
+	*mock.Call
+}
+
+// QueryUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryUserOptions
+func (_e *MockRepository_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockRepository_QueryUsers_Call {
+	return &MockRepository_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+}
+
+func (_c *MockRepository_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockRepository_QueryUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryUserOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryUserOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_QueryUsers_Call) Return(err error) *MockRepository_QueryUsers_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockRepository_QueryUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) ResolveIntents(ctx context.Context, resolutions []*IntentResolution, now int64) ([]bson.ObjectID, error) {
+	ret := _mock.Called(ctx, resolutions, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveIntents")
+	}
+
+	var r0 []bson.ObjectID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*IntentResolution, int64) ([]bson.ObjectID, error)); ok {
+		return returnFunc(ctx, resolutions, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*IntentResolution, int64) []bson.ObjectID); ok {
+		r0 = returnFunc(ctx, resolutions, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bson.ObjectID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []*IntentResolution, int64) error); ok {
+		r1 = returnFunc(ctx, resolutions, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_ResolveIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveIntents'
+type MockRepository_ResolveIntents_Call struct {
+	*mock.Call
+}
+
+// ResolveIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - resolutions []*IntentResolution
+//   - now int64
+func (_e *MockRepository_Expecter) ResolveIntents(ctx interface{}, resolutions interface{}, now interface{}) *MockRepository_ResolveIntents_Call {
+	return &MockRepository_ResolveIntents_Call{Call: _e.mock.On("ResolveIntents", ctx, resolutions, now)}
+}
+
+func (_c *MockRepository_ResolveIntents_Call) Run(run func(ctx context.Context, resolutions []*IntentResolution, now int64)) *MockRepository_ResolveIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*IntentResolution
+		if args[1] != nil {
+			arg1 = args[1].([]*IntentResolution)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_ResolveIntents_Call) Return(objectIDs []bson.ObjectID, err error) *MockRepository_ResolveIntents_Call {
+	_c.Call.Return(objectIDs, err)
+	return _c
+}
+
+func (_c *MockRepository_ResolveIntents_Call) RunAndReturn(run func(ctx context.Context, resolutions []*IntentResolution, now int64) ([]bson.ObjectID, error)) *MockRepository_ResolveIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateIntents provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateIntents(ctx context.Context, intents []*ScheduleIntent) error {
+	ret := _mock.Called(ctx, intents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*ScheduleIntent) error); ok {
+		r0 = returnFunc(ctx, intents)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateIntents'
+type MockRepository_UpdateIntents_Call struct {
+	*mock.Call
+}
+
+// UpdateIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - intents []*ScheduleIntent
+func (_e *MockRepository_Expecter) UpdateIntents(ctx interface{}, intents interface{}) *MockRepository_UpdateIntents_Call {
+	return &MockRepository_UpdateIntents_Call{Call: _e.mock.On("UpdateIntents", ctx, intents)}
+}
+
+func (_c *MockRepository_UpdateIntents_Call) Run(run func(ctx context.Context, intents []*ScheduleIntent)) *MockRepository_UpdateIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*ScheduleIntent
+		if args[1] != nil {
+			arg1 = args[1].([]*ScheduleIntent)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateIntents_Call) Return(err error) *MockRepository_UpdateIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateIntents_Call) RunAndReturn(run func(ctx context.Context, intents []*ScheduleIntent) error) *MockRepository_UpdateIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePermission provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdatePermission(ctx context.Context, permission *Permission) error {
+	ret := _mock.Called(ctx, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePermission")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Permission) error); ok {
+		r0 = returnFunc(ctx, permission)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdatePermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePermission'
+type MockRepository_UpdatePermission_Call struct {
+	*mock.Call
+}
+
+// UpdatePermission is a helper method to define mock.On call
+//   - ctx context.Context
+//   - permission *Permission
+func (_e *MockRepository_Expecter) UpdatePermission(ctx interface{}, permission interface{}) *MockRepository_UpdatePermission_Call {
+	return &MockRepository_UpdatePermission_Call{Call: _e.mock.On("UpdatePermission", ctx, permission)}
+}
+
+func (_c *MockRepository_UpdatePermission_Call) Run(run func(ctx context.Context, permission *Permission)) *MockRepository_UpdatePermission_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Permission
+		if args[1] != nil {
+			arg1 = args[1].(*Permission)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdatePermission_Call) Return(err error) *MockRepository_UpdatePermission_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdatePermission_Call) RunAndReturn(run func(ctx context.Context, permission *Permission) error) *MockRepository_UpdatePermission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateRole provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateRole(ctx context.Context, role *Role) error {
+	ret := _mock.Called(ctx, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Role) error); ok {
+		r0 = returnFunc(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockRepository_UpdateRole_Call struct {
+	*mock.Call
+}
+
+// UpdateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *Role
+func (_e *MockRepository_Expecter) UpdateRole(ctx interface{}, role interface{}) *MockRepository_UpdateRole_Call {
+	return &MockRepository_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, role)}
+}
+
+func (_c *MockRepository_UpdateRole_Call) Run(run func(ctx context.Context, role *Role)) *MockRepository_UpdateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Role
+		if args[1] != nil {
+			arg1 = args[1].(*Role)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateRole_Call) Return(err error) *MockRepository_UpdateRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, role *Role) error) *MockRepository_UpdateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateStrategy provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateStrategy(ctx context.Context, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStrategy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, strategy)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateStrategy'
+type MockRepository_UpdateStrategy_Call struct {
+	*mock.Call
+}
+
+// UpdateStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - strategy *ScheduleStrategy
+func (_e *MockRepository_Expecter) UpdateStrategy(ctx interface{}, strategy interface{}) *MockRepository_UpdateStrategy_Call {
+	return &MockRepository_UpdateStrategy_Call{Call: _e.mock.On("UpdateStrategy", ctx, strategy)}
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy)) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *ScheduleStrategy
+		if args[1] != nil {
+			arg1 = args[1].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) Return(err error) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateStrategy_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy) error) *MockRepository_UpdateStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUser provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpdateUser(ctx context.Context, user *User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpdateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUser'
+type MockRepository_UpdateUser_Call struct {
+	*mock.Call
+}
+
+// UpdateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *User
+func (_e *MockRepository_Expecter) UpdateUser(ctx interface{}, user interface{}) *MockRepository_UpdateUser_Call {
+	return &MockRepository_UpdateUser_Call{Call: _e.mock.On("UpdateUser", ctx, user)}
+}
+
+func (_c *MockRepository_UpdateUser_Call) Run(run func(ctx context.Context, user *User)) *MockRepository_UpdateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *User
+		if args[1] != nil {
+			arg1 = args[1].(*User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpdateUser_Call) Return(err error) *MockRepository_UpdateUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpdateUser_Call) RunAndReturn(run func(ctx context.Context, user *User) error) *MockRepository_UpdateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpsertNodes provides a mock function for the type MockRepository
+func (_mock *MockRepository) UpsertNodes(ctx context.Context, nodes []*Node) error {
+	ret := _mock.Called(ctx, nodes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertNodes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*Node) error); ok {
+		r0 = returnFunc(ctx, nodes)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockRepository_UpsertNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpsertNodes'
+type MockRepository_UpsertNodes_Call struct {
+	*mock.Call
+}
+
+// UpsertNodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodes []*Node
+func (_e *MockRepository_Expecter) UpsertNodes(ctx interface{}, nodes interface{}) *MockRepository_UpsertNodes_Call {
+	return &MockRepository_UpsertNodes_Call{Call: _e.mock.On("UpsertNodes", ctx, nodes)}
+}
+
+func (_c *MockRepository_UpsertNodes_Call) Run(run func(ctx context.Context, nodes []*Node)) *MockRepository_UpsertNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*Node
+		if args[1] != nil {
+			arg1 = args[1].([]*Node)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_UpsertNodes_Call) Return(err error) *MockRepository_UpsertNodes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockRepository_UpsertNodes_Call) RunAndReturn(run func(ctx context.Context, nodes []*Node) error) *MockRepository_UpsertNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockService {
+	mock := &MockService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockService is an autogenerated mock type for the Service type
+type MockService struct {
+	mock.Mock
+}
+
+type MockService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockService) EXPECT() *MockService_Expecter {
+	return &MockService_Expecter{mock: &_m.Mock}
+}
+
+// ChangePassword provides a mock function for the type MockService
+func (_mock *MockService) ChangePassword(ctx context.Context, user *Claims, oldPassword string, newPassword string) error {
+	ret := _mock.Called(ctx, user, oldPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, user, oldPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type MockService_ChangePassword_Call struct {
+	*mock.Call
+}
+
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *Claims
+//   - oldPassword string
+//   - newPassword string
+func (_e *MockService_Expecter) ChangePassword(ctx interface{}, user interface{}, oldPassword interface{}, newPassword interface{}) *MockService_ChangePassword_Call {
+	return &MockService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, user, oldPassword, newPassword)}
+}
+
+func (_c *MockService_ChangePassword_Call) Run(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string)) *MockService_ChangePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) Return(err error) *MockService_ChangePassword_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ChangePassword_Call) RunAndReturn(run func(ctx context.Context, user *Claims, oldPassword string, newPassword string) error) *MockService_ChangePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateAdminUserIfNotExists provides a mock function for the type MockService
+func (_mock *MockService) CreateAdminUserIfNotExists(ctx context.Context, username string, password string) error {
+	ret := _mock.Called(ctx, username, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAdminUserIfNotExists")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, username, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateAdminUserIfNotExists_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateAdminUserIfNotExists'
+type MockService_CreateAdminUserIfNotExists_Call struct {
+	*mock.Call
+}
+
+// CreateAdminUserIfNotExists is a helper method to define mock.On call
+//   - ctx context.Context
+//   - username string
+//   - password string
+func (_e *MockService_Expecter) CreateAdminUserIfNotExists(ctx interface{}, username interface{}, password interface{}) *MockService_CreateAdminUserIfNotExists_Call {
+	return &MockService_CreateAdminUserIfNotExists_Call{Call: _e.mock.On("CreateAdminUserIfNotExists", ctx, username, password)}
+}
+
+func (_c *MockService_CreateAdminUserIfNotExists_Call) Run(run func(ctx context.Context, username string, password string)) *MockService_CreateAdminUserIfNotExists_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -1059,237 +1864,1089 @@ func (_c *MockService_CreateAdminUserIfNotExists_Call) Return(err error) *MockSe
 	return _c
 }
 
-func (_c *MockService_CreateAdminUserIfNotExists_Call) RunAndReturn(run func(ctx context.Context, username string, password string) error) *MockService_CreateAdminUserIfNotExists_Call {
+func (_c *MockService_CreateAdminUserIfNotExists_Call) RunAndReturn(run func(ctx context.Context, username string, password string) error) *MockService_CreateAdminUserIfNotExists_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateNewUser provides a mock function for the type MockService
+func (_mock *MockService) CreateNewUser(ctx context.Context, operator *Claims, username string, password string) error {
+	ret := _mock.Called(ctx, operator, username, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNewUser")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, operator, username, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateNewUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNewUser'
+type MockService_CreateNewUser_Call struct {
+	*mock.Call
+}
+
+// CreateNewUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - username string
+//   - password string
+func (_e *MockService_Expecter) CreateNewUser(ctx interface{}, operator interface{}, username interface{}, password interface{}) *MockService_CreateNewUser_Call {
+	return &MockService_CreateNewUser_Call{Call: _e.mock.On("CreateNewUser", ctx, operator, username, password)}
+}
+
+func (_c *MockService_CreateNewUser_Call) Run(run func(ctx context.Context, operator *Claims, username string, password string)) *MockService_CreateNewUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateNewUser_Call) Return(err error) *MockService_CreateNewUser_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateNewUser_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, username string, password string) error) *MockService_CreateNewUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRole provides a mock function for the type MockService
+func (_mock *MockService) CreateRole(ctx context.Context, operator *Claims, role *Role) error {
+	ret := _mock.Called(ctx, operator, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *Role) error); ok {
+		r0 = returnFunc(ctx, operator, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type MockService_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - role *Role
+func (_e *MockService_Expecter) CreateRole(ctx interface{}, operator interface{}, role interface{}) *MockService_CreateRole_Call {
+	return &MockService_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, operator, role)}
+}
+
+func (_c *MockService_CreateRole_Call) Run(run func(ctx context.Context, operator *Claims, role *Role)) *MockService_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *Role
+		if args[2] != nil {
+			arg2 = args[2].(*Role)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateRole_Call) Return(err error) *MockService_CreateRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_CreateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, role *Role) error) *MockService_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) ([]*StrategyConflict, error) {
+	ret := _mock.Called(ctx, operator, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduleStrategy")
+	}
+
+	var r0 []*StrategyConflict
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ScheduleStrategy) ([]*StrategyConflict, error)); ok {
+		return returnFunc(ctx, operator, strategy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ScheduleStrategy) []*StrategyConflict); ok {
+		r0 = returnFunc(ctx, operator, strategy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*StrategyConflict)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *Claims, *ScheduleStrategy) error); ok {
+		r1 = returnFunc(ctx, operator, strategy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CreateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleStrategy'
+type MockService_CreateScheduleStrategy_Call struct {
+	*mock.Call
+}
+
+// CreateScheduleStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - strategy *ScheduleStrategy
+func (_e *MockService_Expecter) CreateScheduleStrategy(ctx interface{}, operator interface{}, strategy interface{}) *MockService_CreateScheduleStrategy_Call {
+	return &MockService_CreateScheduleStrategy_Call{Call: _e.mock.On("CreateScheduleStrategy", ctx, operator, strategy)}
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy)) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 *ScheduleStrategy
+		if args[2] != nil {
+			arg2 = args[2].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) Return(strategyConflicts []*StrategyConflict, err error) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Return(strategyConflicts, err)
+	return _c
+}
+
+func (_c *MockService_CreateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) ([]*StrategyConflict, error)) *MockService_CreateScheduleStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteRole provides a mock function for the type MockService
+func (_mock *MockService) DeleteRole(ctx context.Context, operator *Claims, roleID string) error {
+	ret := _mock.Called(ctx, operator, roleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRole")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, roleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
+type MockService_DeleteRole_Call struct {
+	*mock.Call
+}
+
+// DeleteRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - roleID string
+func (_e *MockService_Expecter) DeleteRole(ctx interface{}, operator interface{}, roleID interface{}) *MockService_DeleteRole_Call {
+	return &MockService_DeleteRole_Call{Call: _e.mock.On("DeleteRole", ctx, operator, roleID)}
+}
+
+func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string)) *MockService_DeleteRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteRole_Call) Return(err error) *MockService_DeleteRole_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string) error) *MockService_DeleteRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) DeleteScheduleStrategy(ctx context.Context, operator *Claims, strategyID string) error {
+	ret := _mock.Called(ctx, operator, strategyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteScheduleStrategy")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
+		r0 = returnFunc(ctx, operator, strategyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeleteScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteScheduleStrategy'
+type MockService_DeleteScheduleStrategy_Call struct {
+	*mock.Call
+}
+
+// DeleteScheduleStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - operator *Claims
+//   - strategyID string
+func (_e *MockService_Expecter) DeleteScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}) *MockService_DeleteScheduleStrategy_Call {
+	return &MockService_DeleteScheduleStrategy_Call{Call: _e.mock.On("DeleteScheduleStrategy", ctx, operator, strategyID)}
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string)) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *Claims
+		if args[1] != nil {
+			arg1 = args[1].(*Claims)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) Return(err error) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeleteScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string) error) *MockService_DeleteScheduleStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeliverPendingIntents provides a mock function for the type MockService
+func (_mock *MockService) DeliverPendingIntents(ctx context.Context) error {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeliverPendingIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_DeliverPendingIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeliverPendingIntents'
+type MockService_DeliverPendingIntents_Call struct {
+	*mock.Call
+}
+
+// DeliverPendingIntents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) DeliverPendingIntents(ctx interface{}) *MockService_DeliverPendingIntents_Call {
+	return &MockService_DeliverPendingIntents_Call{Call: _e.mock.On("DeliverPendingIntents", ctx)}
+}
+
+func (_c *MockService_DeliverPendingIntents_Call) Run(run func(ctx context.Context)) *MockService_DeliverPendingIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DeliverPendingIntents_Call) Return(err error) *MockService_DeliverPendingIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_DeliverPendingIntents_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_DeliverPendingIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNode provides a mock function for the type MockService
+func (_mock *MockService) GetNode(ctx context.Context, nodeID string) (*Node, error) {
+	ret := _mock.Called(ctx, nodeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNode")
+	}
+
+	var r0 *Node
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*Node, error)); ok {
+		return returnFunc(ctx, nodeID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *Node); ok {
+		r0 = returnFunc(ctx, nodeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Node)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, nodeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetNode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNode'
+type MockService_GetNode_Call struct {
+	*mock.Call
+}
+
+// GetNode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nodeID string
+func (_e *MockService_Expecter) GetNode(ctx interface{}, nodeID interface{}) *MockService_GetNode_Call {
+	return &MockService_GetNode_Call{Call: _e.mock.On("GetNode", ctx, nodeID)}
+}
+
+func (_c *MockService_GetNode_Call) Run(run func(ctx context.Context, nodeID string)) *MockService_GetNode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetNode_Call) Return(node *Node, err error) *MockService_GetNode_Call {
+	_c.Call.Return(node, err)
+	return _c
+}
+
+func (_c *MockService_GetNode_Call) RunAndReturn(run func(ctx context.Context, nodeID string) (*Node, error)) *MockService_GetNode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListNodeMetrics provides a mock function for the type MockService
+func (_mock *MockService) ListNodeMetrics(ctx context.Context) (*ClusterMetrics, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNodeMetrics")
+	}
+
+	var r0 *ClusterMetrics
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*ClusterMetrics, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *ClusterMetrics); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ClusterMetrics)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ListNodeMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodeMetrics'
+type MockService_ListNodeMetrics_Call struct {
+	*mock.Call
+}
+
+// ListNodeMetrics is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) ListNodeMetrics(ctx interface{}) *MockService_ListNodeMetrics_Call {
+	return &MockService_ListNodeMetrics_Call{Call: _e.mock.On("ListNodeMetrics", ctx)}
+}
+
+func (_c *MockService_ListNodeMetrics_Call) Run(run func(ctx context.Context)) *MockService_ListNodeMetrics_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListNodeMetrics_Call) Return(clusterMetrics *ClusterMetrics, err error) *MockService_ListNodeMetrics_Call {
+	_c.Call.Return(clusterMetrics, err)
+	return _c
+}
+
+func (_c *MockService_ListNodeMetrics_Call) RunAndReturn(run func(ctx context.Context) (*ClusterMetrics, error)) *MockService_ListNodeMetrics_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListNodes provides a mock function for the type MockService
+func (_mock *MockService) ListNodes(ctx context.Context, opt *QueryNodeOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListNodes")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryNodeOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListNodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListNodes'
+type MockService_ListNodes_Call struct {
+	*mock.Call
+}
+
+// ListNodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryNodeOptions
+func (_e *MockService_Expecter) ListNodes(ctx interface{}, opt interface{}) *MockService_ListNodes_Call {
+	return &MockService_ListNodes_Call{Call: _e.mock.On("ListNodes", ctx, opt)}
+}
+
+func (_c *MockService_ListNodes_Call) Run(run func(ctx context.Context, opt *QueryNodeOptions)) *MockService_ListNodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryNodeOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryNodeOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListNodes_Call) Return(err error) *MockService_ListNodes_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListNodes_Call) RunAndReturn(run func(ctx context.Context, opt *QueryNodeOptions) error) *MockService_ListNodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListScheduleIntents provides a mock function for the type MockService
+func (_mock *MockService) ListScheduleIntents(ctx context.Context, filterOpts *QueryIntentOptions) error {
+	ret := _mock.Called(ctx, filterOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduleIntents")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
+		r0 = returnFunc(ctx, filterOpts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleIntents'
+type MockService_ListScheduleIntents_Call struct {
+	*mock.Call
+}
+
+// ListScheduleIntents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filterOpts *QueryIntentOptions
+func (_e *MockService_Expecter) ListScheduleIntents(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleIntents_Call {
+	return &MockService_ListScheduleIntents_Call{Call: _e.mock.On("ListScheduleIntents", ctx, filterOpts)}
+}
+
+func (_c *MockService_ListScheduleIntents_Call) Run(run func(ctx context.Context, filterOpts *QueryIntentOptions)) *MockService_ListScheduleIntents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryIntentOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryIntentOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListScheduleIntents_Call) Return(err error) *MockService_ListScheduleIntents_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryIntentOptions) error) *MockService_ListScheduleIntents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListScheduleStrategies provides a mock function for the type MockService
+func (_mock *MockService) ListScheduleStrategies(ctx context.Context, filterOpts *QueryStrategyOptions) error {
+	ret := _mock.Called(ctx, filterOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduleStrategies")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
+		r0 = returnFunc(ctx, filterOpts)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_ListScheduleStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleStrategies'
+type MockService_ListScheduleStrategies_Call struct {
+	*mock.Call
+}
+
+// ListScheduleStrategies is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filterOpts *QueryStrategyOptions
+func (_e *MockService_Expecter) ListScheduleStrategies(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleStrategies_Call {
+	return &MockService_ListScheduleStrategies_Call{Call: _e.mock.On("ListScheduleStrategies", ctx, filterOpts)}
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) Run(run func(ctx context.Context, filterOpts *QueryStrategyOptions)) *MockService_ListScheduleStrategies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryStrategyOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryStrategyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) Return(err error) *MockService_ListScheduleStrategies_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_ListScheduleStrategies_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryStrategyOptions) error) *MockService_ListScheduleStrategies_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateNewUser provides a mock function for the type MockService
-func (_mock *MockService) CreateNewUser(ctx context.Context, operator *Claims, username string, password string) error {
-	ret := _mock.Called(ctx, operator, username, password)
+// Login provides a mock function for the type MockService
+func (_mock *MockService) Login(ctx context.Context, email string, password string) (string, error) {
+	ret := _mock.Called(ctx, email, password)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateNewUser")
+		panic("no return value specified for Login")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, operator, username, password)
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return returnFunc(ctx, email, password)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = returnFunc(ctx, email, password)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockService_CreateNewUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateNewUser'
-type MockService_CreateNewUser_Call struct {
+// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
+type MockService_Login_Call struct {
 	*mock.Call
 }
 
-// CreateNewUser is a helper method to define mock.On call
+// Login is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - username string
+//   - email string
 //   - password string
-func (_e *MockService_Expecter) CreateNewUser(ctx interface{}, operator interface{}, username interface{}, password interface{}) *MockService_CreateNewUser_Call {
-	return &MockService_CreateNewUser_Call{Call: _e.mock.On("CreateNewUser", ctx, operator, username, password)}
+func (_e *MockService_Expecter) Login(ctx interface{}, email interface{}, password interface{}) *MockService_Login_Call {
+	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, email, password)}
 }
 
-func (_c *MockService_CreateNewUser_Call) Run(run func(ctx context.Context, operator *Claims, username string, password string)) *MockService_CreateNewUser_Call {
+func (_c *MockService_Login_Call) Run(run func(ctx context.Context, email string, password string)) *MockService_Login_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
+			arg1 = args[1].(string)
 		}
 		var arg2 string
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Login_Call) Return(token string, err error) *MockService_Login_Call {
+	_c.Call.Return(token, err)
+	return _c
+}
+
+func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (string, error)) *MockService_Login_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PreviewScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) PreviewScheduleStrategy(ctx context.Context, strategy *ScheduleStrategy) (*StrategyPreview, error) {
+	ret := _mock.Called(ctx, strategy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewScheduleStrategy")
+	}
+
+	var r0 *StrategyPreview
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) (*StrategyPreview, error)); ok {
+		return returnFunc(ctx, strategy)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *ScheduleStrategy) *StrategyPreview); ok {
+		r0 = returnFunc(ctx, strategy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*StrategyPreview)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *ScheduleStrategy) error); ok {
+		r1 = returnFunc(ctx, strategy)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_PreviewScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreviewScheduleStrategy'
+type MockService_PreviewScheduleStrategy_Call struct {
+	*mock.Call
+}
+
+// PreviewScheduleStrategy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - strategy *ScheduleStrategy
+func (_e *MockService_Expecter) PreviewScheduleStrategy(ctx interface{}, strategy interface{}) *MockService_PreviewScheduleStrategy_Call {
+	return &MockService_PreviewScheduleStrategy_Call{Call: _e.mock.On("PreviewScheduleStrategy", ctx, strategy)}
+}
+
+func (_c *MockService_PreviewScheduleStrategy_Call) Run(run func(ctx context.Context, strategy *ScheduleStrategy)) *MockService_PreviewScheduleStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *ScheduleStrategy
+		if args[1] != nil {
+			arg1 = args[1].(*ScheduleStrategy)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_PreviewScheduleStrategy_Call) Return(strategyPreview *StrategyPreview, err error) *MockService_PreviewScheduleStrategy_Call {
+	_c.Call.Return(strategyPreview, err)
+	return _c
+}
+
+func (_c *MockService_PreviewScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, strategy *ScheduleStrategy) (*StrategyPreview, error)) *MockService_PreviewScheduleStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryPermissions provides a mock function for the type MockService
+func (_mock *MockService) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryPermissions")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
+type MockService_QueryPermissions_Call struct {
+	*mock.Call
+}
+
+// QueryPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryPermissionOptions
+func (_e *MockService_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockService_QueryPermissions_Call {
+	return &MockService_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+}
+
+func (_c *MockService_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockService_QueryPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryPermissionOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryPermissionOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_QueryPermissions_Call) Return(err error) *MockService_QueryPermissions_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockService_QueryPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QueryRoles provides a mock function for the type MockService
+func (_mock *MockService) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
+	ret := _mock.Called(ctx, opt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryRoles")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
+type MockService_QueryRoles_Call struct {
+	*mock.Call
+}
+
+// QueryRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opt *QueryRoleOptions
+func (_e *MockService_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockService_QueryRoles_Call {
+	return &MockService_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+}
+
+func (_c *MockService_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockService_QueryRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *QueryRoleOptions
+		if args[1] != nil {
+			arg1 = args[1].(*QueryRoleOptions)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateNewUser_Call) Return(err error) *MockService_CreateNewUser_Call {
+func (_c *MockService_QueryRoles_Call) Return(err error) *MockService_QueryRoles_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateNewUser_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, username string, password string) error) *MockService_CreateNewUser_Call {
+func (_c *MockService_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockService_QueryRoles_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateRole provides a mock function for the type MockService
-func (_mock *MockService) CreateRole(ctx context.Context, operator *Claims, role *Role) error {
-	ret := _mock.Called(ctx, operator, role)
+// QueryUsers provides a mock function for the type MockService
+func (_mock *MockService) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
+	ret := _mock.Called(ctx, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateRole")
+		panic("no return value specified for QueryUsers")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *Role) error); ok {
-		r0 = returnFunc(ctx, operator, role)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
+		r0 = returnFunc(ctx, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
-type MockService_CreateRole_Call struct {
+// MockService_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
+type MockService_QueryUsers_Call struct {
 	*mock.Call
 }
 
-// CreateRole is a helper method to define mock.On call
+// QueryUsers is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - role *Role
-func (_e *MockService_Expecter) CreateRole(ctx interface{}, operator interface{}, role interface{}) *MockService_CreateRole_Call {
-	return &MockService_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, operator, role)}
+//   - opt *QueryUserOptions
+func (_e *MockService_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockService_QueryUsers_Call {
+	return &MockService_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
 }
 
-func (_c *MockService_CreateRole_Call) Run(run func(ctx context.Context, operator *Claims, role *Role)) *MockService_CreateRole_Call {
+func (_c *MockService_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockService_QueryUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
+		var arg1 *QueryUserOptions
 		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 *Role
-		if args[2] != nil {
-			arg2 = args[2].(*Role)
+			arg1 = args[1].(*QueryUserOptions)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateRole_Call) Return(err error) *MockService_CreateRole_Call {
+func (_c *MockService_QueryUsers_Call) Return(err error) *MockService_QueryUsers_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, role *Role) error) *MockService_CreateRole_Call {
+func (_c *MockService_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockService_QueryUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateScheduleStrategy provides a mock function for the type MockService
-func (_mock *MockService) CreateScheduleStrategy(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error {
-	ret := _mock.Called(ctx, operator, strategy)
+// ReconcileScheduleStrategies provides a mock function for the type MockService
+func (_mock *MockService) ReconcileScheduleStrategies(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateScheduleStrategy")
+		panic("no return value specified for ReconcileScheduleStrategies")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, *ScheduleStrategy) error); ok {
-		r0 = returnFunc(ctx, operator, strategy)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_CreateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateScheduleStrategy'
-type MockService_CreateScheduleStrategy_Call struct {
+// MockService_ReconcileScheduleStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReconcileScheduleStrategies'
+type MockService_ReconcileScheduleStrategies_Call struct {
 	*mock.Call
 }
 
-// CreateScheduleStrategy is a helper method to define mock.On call
+// ReconcileScheduleStrategies is a helper method to define mock.On call
 //   - ctx context.Context
-//   - operator *Claims
-//   - strategy *ScheduleStrategy
-func (_e *MockService_Expecter) CreateScheduleStrategy(ctx interface{}, operator interface{}, strategy interface{}) *MockService_CreateScheduleStrategy_Call {
-	return &MockService_CreateScheduleStrategy_Call{Call: _e.mock.On("CreateScheduleStrategy", ctx, operator, strategy)}
+func (_e *MockService_Expecter) ReconcileScheduleStrategies(ctx interface{}) *MockService_ReconcileScheduleStrategies_Call {
+	return &MockService_ReconcileScheduleStrategies_Call{Call: _e.mock.On("ReconcileScheduleStrategies", ctx)}
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy)) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockService_ReconcileScheduleStrategies_Call) Run(run func(ctx context.Context)) *MockService_ReconcileScheduleStrategies_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *Claims
-		if args[1] != nil {
-			arg1 = args[1].(*Claims)
-		}
-		var arg2 *ScheduleStrategy
-		if args[2] != nil {
-			arg2 = args[2].(*ScheduleStrategy)
-		}
 		run(
 			arg0,
-			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) Return(err error) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockService_ReconcileScheduleStrategies_Call) Return(err error) *MockService_ReconcileScheduleStrategies_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_CreateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategy *ScheduleStrategy) error) *MockService_CreateScheduleStrategy_Call {
+func (_c *MockService_ReconcileScheduleStrategies_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_ReconcileScheduleStrategies_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteRole provides a mock function for the type MockService
-func (_mock *MockService) DeleteRole(ctx context.Context, operator *Claims, roleID string) error {
-	ret := _mock.Called(ctx, operator, roleID)
+// ResetPassword provides a mock function for the type MockService
+func (_mock *MockService) ResetPassword(ctx context.Context, operator *Claims, id string, newPassword string) error {
+	ret := _mock.Called(ctx, operator, id, newPassword)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteRole")
+		panic("no return value specified for ResetPassword")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string) error); ok {
-		r0 = returnFunc(ctx, operator, roleID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
+		r0 = returnFunc(ctx, operator, id, newPassword)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_DeleteRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteRole'
-type MockService_DeleteRole_Call struct {
+// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type MockService_ResetPassword_Call struct {
 	*mock.Call
 }
 
-// DeleteRole is a helper method to define mock.On call
+// ResetPassword is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - roleID string
-func (_e *MockService_Expecter) DeleteRole(ctx interface{}, operator interface{}, roleID interface{}) *MockService_DeleteRole_Call {
-	return &MockService_DeleteRole_Call{Call: _e.mock.On("DeleteRole", ctx, operator, roleID)}
+//   - id string
+//   - newPassword string
+func (_e *MockService_Expecter) ResetPassword(ctx interface{}, operator interface{}, id interface{}, newPassword interface{}) *MockService_ResetPassword_Call {
+	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, operator, id, newPassword)}
 }
 
-func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string)) *MockService_DeleteRole_Call {
+func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, operator *Claims, id string, newPassword string)) *MockService_ResetPassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1303,414 +2960,368 @@ func (_c *MockService_DeleteRole_Call) Run(run func(ctx context.Context, operato
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_DeleteRole_Call) Return(err error) *MockService_DeleteRole_Call {
+func (_c *MockService_ResetPassword_Call) Return(err error) *MockService_ResetPassword_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_DeleteRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string) error) *MockService_DeleteRole_Call {
+func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, newPassword string) error) *MockService_ResetPassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListScheduleIntents provides a mock function for the type MockService
-func (_mock *MockService) ListScheduleIntents(ctx context.Context, filterOpts *QueryIntentOptions) error {
-	ret := _mock.Called(ctx, filterOpts)
+// RunIntentDeliveryWorker provides a mock function for the type MockService
+func (_mock *MockService) RunIntentDeliveryWorker(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListScheduleIntents")
+		panic("no return value specified for RunIntentDeliveryWorker")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryIntentOptions) error); ok {
-		r0 = returnFunc(ctx, filterOpts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ListScheduleIntents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleIntents'
-type MockService_ListScheduleIntents_Call struct {
+// MockService_RunIntentDeliveryWorker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunIntentDeliveryWorker'
+type MockService_RunIntentDeliveryWorker_Call struct {
 	*mock.Call
 }
 
-// ListScheduleIntents is a helper method to define mock.On call
+// RunIntentDeliveryWorker is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filterOpts *QueryIntentOptions
-func (_e *MockService_Expecter) ListScheduleIntents(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleIntents_Call {
-	return &MockService_ListScheduleIntents_Call{Call: _e.mock.On("ListScheduleIntents", ctx, filterOpts)}
+func (_e *MockService_Expecter) RunIntentDeliveryWorker(ctx interface{}) *MockService_RunIntentDeliveryWorker_Call {
+	return &MockService_RunIntentDeliveryWorker_Call{Call: _e.mock.On("RunIntentDeliveryWorker", ctx)}
 }
 
-func (_c *MockService_ListScheduleIntents_Call) Run(run func(ctx context.Context, filterOpts *QueryIntentOptions)) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_RunIntentDeliveryWorker_Call) Run(run func(ctx context.Context)) *MockService_RunIntentDeliveryWorker_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryIntentOptions
-		if args[1] != nil {
-			arg1 = args[1].(*QueryIntentOptions)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ListScheduleIntents_Call) Return(err error) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_RunIntentDeliveryWorker_Call) Return(err error) *MockService_RunIntentDeliveryWorker_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ListScheduleIntents_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryIntentOptions) error) *MockService_ListScheduleIntents_Call {
+func (_c *MockService_RunIntentDeliveryWorker_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_RunIntentDeliveryWorker_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListScheduleStrategies provides a mock function for the type MockService
-func (_mock *MockService) ListScheduleStrategies(ctx context.Context, filterOpts *QueryStrategyOptions) error {
-	ret := _mock.Called(ctx, filterOpts)
+// RunNodeMetricsScraper provides a mock function for the type MockService
+func (_mock *MockService) RunNodeMetricsScraper(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListScheduleStrategies")
+		panic("no return value specified for RunNodeMetricsScraper")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryStrategyOptions) error); ok {
-		r0 = returnFunc(ctx, filterOpts)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ListScheduleStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListScheduleStrategies'
-type MockService_ListScheduleStrategies_Call struct {
+// MockService_RunNodeMetricsScraper_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunNodeMetricsScraper'
+type MockService_RunNodeMetricsScraper_Call struct {
 	*mock.Call
 }
 
-// ListScheduleStrategies is a helper method to define mock.On call
+// RunNodeMetricsScraper is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filterOpts *QueryStrategyOptions
-func (_e *MockService_Expecter) ListScheduleStrategies(ctx interface{}, filterOpts interface{}) *MockService_ListScheduleStrategies_Call {
-	return &MockService_ListScheduleStrategies_Call{Call: _e.mock.On("ListScheduleStrategies", ctx, filterOpts)}
+func (_e *MockService_Expecter) RunNodeMetricsScraper(ctx interface{}) *MockService_RunNodeMetricsScraper_Call {
+	return &MockService_RunNodeMetricsScraper_Call{Call: _e.mock.On("RunNodeMetricsScraper", ctx)}
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) Run(run func(ctx context.Context, filterOpts *QueryStrategyOptions)) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_RunNodeMetricsScraper_Call) Run(run func(ctx context.Context)) *MockService_RunNodeMetricsScraper_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryStrategyOptions
-		if args[1] != nil {
-			arg1 = args[1].(*QueryStrategyOptions)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) Return(err error) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_RunNodeMetricsScraper_Call) Return(err error) *MockService_RunNodeMetricsScraper_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ListScheduleStrategies_Call) RunAndReturn(run func(ctx context.Context, filterOpts *QueryStrategyOptions) error) *MockService_ListScheduleStrategies_Call {
+func (_c *MockService_RunNodeMetricsScraper_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_RunNodeMetricsScraper_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Login provides a mock function for the type MockService
-func (_mock *MockService) Login(ctx context.Context, email string, password string) (string, error) {
-	ret := _mock.Called(ctx, email, password)
+// RunNodeRegistry provides a mock function for the type MockService
+func (_mock *MockService) RunNodeRegistry(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Login")
+		panic("no return value specified for RunNodeRegistry")
 	}
 
-	var r0 string
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return returnFunc(ctx, email, password)
-	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = returnFunc(ctx, email, password)
-	} else {
-		r0 = ret.Get(0).(string)
-	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = returnFunc(ctx, email, password)
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockService_Login_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Login'
-type MockService_Login_Call struct {
+// MockService_RunNodeRegistry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunNodeRegistry'
+type MockService_RunNodeRegistry_Call struct {
 	*mock.Call
 }
 
-// Login is a helper method to define mock.On call
+// RunNodeRegistry is a helper method to define mock.On call
 //   - ctx context.Context
-//   - email string
-//   - password string
-func (_e *MockService_Expecter) Login(ctx interface{}, email interface{}, password interface{}) *MockService_Login_Call {
-	return &MockService_Login_Call{Call: _e.mock.On("Login", ctx, email, password)}
+func (_e *MockService_Expecter) RunNodeRegistry(ctx interface{}) *MockService_RunNodeRegistry_Call {
+	return &MockService_RunNodeRegistry_Call{Call: _e.mock.On("RunNodeRegistry", ctx)}
 }
 
-func (_c *MockService_Login_Call) Run(run func(ctx context.Context, email string, password string)) *MockService_Login_Call {
+func (_c *MockService_RunNodeRegistry_Call) Run(run func(ctx context.Context)) *MockService_RunNodeRegistry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
-		if args[1] != nil {
-			arg1 = args[1].(string)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
-		}
 		run(
 			arg0,
-			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_Login_Call) Return(token string, err error) *MockService_Login_Call {
-	_c.Call.Return(token, err)
+func (_c *MockService_RunNodeRegistry_Call) Return(err error) *MockService_RunNodeRegistry_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_Login_Call) RunAndReturn(run func(ctx context.Context, email string, password string) (string, error)) *MockService_Login_Call {
+func (_c *MockService_RunNodeRegistry_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_RunNodeRegistry_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryPermissions provides a mock function for the type MockService
-func (_mock *MockService) QueryPermissions(ctx context.Context, opt *QueryPermissionOptions) error {
-	ret := _mock.Called(ctx, opt)
+// RunStrategyReconciler provides a mock function for the type MockService
+func (_mock *MockService) RunStrategyReconciler(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryPermissions")
+		panic("no return value specified for RunStrategyReconciler")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryPermissionOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryPermissions'
-type MockService_QueryPermissions_Call struct {
+// MockService_RunStrategyReconciler_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunStrategyReconciler'
+type MockService_RunStrategyReconciler_Call struct {
 	*mock.Call
 }
 
-// QueryPermissions is a helper method to define mock.On call
+// RunStrategyReconciler is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryPermissionOptions
-func (_e *MockService_Expecter) QueryPermissions(ctx interface{}, opt interface{}) *MockService_QueryPermissions_Call {
-	return &MockService_QueryPermissions_Call{Call: _e.mock.On("QueryPermissions", ctx, opt)}
+func (_e *MockService_Expecter) RunStrategyReconciler(ctx interface{}) *MockService_RunStrategyReconciler_Call {
+	return &MockService_RunStrategyReconciler_Call{Call: _e.mock.On("RunStrategyReconciler", ctx)}
 }
 
-func (_c *MockService_QueryPermissions_Call) Run(run func(ctx context.Context, opt *QueryPermissionOptions)) *MockService_QueryPermissions_Call {
+func (_c *MockService_RunStrategyReconciler_Call) Run(run func(ctx context.Context)) *MockService_RunStrategyReconciler_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryPermissionOptions
-		if args[1] != nil {
-			arg1 = args[1].(*QueryPermissionOptions)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryPermissions_Call) Return(err error) *MockService_QueryPermissions_Call {
+func (_c *MockService_RunStrategyReconciler_Call) Return(err error) *MockService_RunStrategyReconciler_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryPermissions_Call) RunAndReturn(run func(ctx context.Context, opt *QueryPermissionOptions) error) *MockService_QueryPermissions_Call {
+func (_c *MockService_RunStrategyReconciler_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_RunStrategyReconciler_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryRoles provides a mock function for the type MockService
-func (_mock *MockService) QueryRoles(ctx context.Context, opt *QueryRoleOptions) error {
-	ret := _mock.Called(ctx, opt)
+// ScrapeNodeMetrics provides a mock function for the type MockService
+func (_mock *MockService) ScrapeNodeMetrics(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRoles")
+		panic("no return value specified for ScrapeNodeMetrics")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryRoleOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryRoles'
-type MockService_QueryRoles_Call struct {
+// MockService_ScrapeNodeMetrics_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScrapeNodeMetrics'
+type MockService_ScrapeNodeMetrics_Call struct {
 	*mock.Call
 }
 
-// QueryRoles is a helper method to define mock.On call
+// ScrapeNodeMetrics is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryRoleOptions
-func (_e *MockService_Expecter) QueryRoles(ctx interface{}, opt interface{}) *MockService_QueryRoles_Call {
-	return &MockService_QueryRoles_Call{Call: _e.mock.On("QueryRoles", ctx, opt)}
+func (_e *MockService_Expecter) ScrapeNodeMetrics(ctx interface{}) *MockService_ScrapeNodeMetrics_Call {
+	return &MockService_ScrapeNodeMetrics_Call{Call: _e.mock.On("ScrapeNodeMetrics", ctx)}
 }
 
-func (_c *MockService_QueryRoles_Call) Run(run func(ctx context.Context, opt *QueryRoleOptions)) *MockService_QueryRoles_Call {
+func (_c *MockService_ScrapeNodeMetrics_Call) Run(run func(ctx context.Context)) *MockService_ScrapeNodeMetrics_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryRoleOptions
-		if args[1] != nil {
-			arg1 = args[1].(*QueryRoleOptions)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryRoles_Call) Return(err error) *MockService_QueryRoles_Call {
+func (_c *MockService_ScrapeNodeMetrics_Call) Return(err error) *MockService_ScrapeNodeMetrics_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryRoles_Call) RunAndReturn(run func(ctx context.Context, opt *QueryRoleOptions) error) *MockService_QueryRoles_Call {
+func (_c *MockService_ScrapeNodeMetrics_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_ScrapeNodeMetrics_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// QueryUsers provides a mock function for the type MockService
-func (_mock *MockService) QueryUsers(ctx context.Context, opt *QueryUserOptions) error {
-	ret := _mock.Called(ctx, opt)
+// SyncNodeRegistry provides a mock function for the type MockService
+func (_mock *MockService) SyncNodeRegistry(ctx context.Context) error {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryUsers")
+		panic("no return value specified for SyncNodeRegistry")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *QueryUserOptions) error); ok {
-		r0 = returnFunc(ctx, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = returnFunc(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_QueryUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QueryUsers'
-type MockService_QueryUsers_Call struct {
+// MockService_SyncNodeRegistry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SyncNodeRegistry'
+type MockService_SyncNodeRegistry_Call struct {
 	*mock.Call
 }
 
-// QueryUsers is a helper method to define mock.On call
+// SyncNodeRegistry is a helper method to define mock.On call
 //   - ctx context.Context
-//   - opt *QueryUserOptions
-func (_e *MockService_Expecter) QueryUsers(ctx interface{}, opt interface{}) *MockService_QueryUsers_Call {
-	return &MockService_QueryUsers_Call{Call: _e.mock.On("QueryUsers", ctx, opt)}
+func (_e *MockService_Expecter) SyncNodeRegistry(ctx interface{}) *MockService_SyncNodeRegistry_Call {
+	return &MockService_SyncNodeRegistry_Call{Call: _e.mock.On("SyncNodeRegistry", ctx)}
 }
 
-func (_c *MockService_QueryUsers_Call) Run(run func(ctx context.Context, opt *QueryUserOptions)) *MockService_QueryUsers_Call {
+func (_c *MockService_SyncNodeRegistry_Call) Run(run func(ctx context.Context)) *MockService_SyncNodeRegistry_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *QueryUserOptions
-		if args[1] != nil {
-			arg1 = args[1].(*QueryUserOptions)
-		}
 		run(
 			arg0,
-			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_QueryUsers_Call) Return(err error) *MockService_QueryUsers_Call {
+func (_c *MockService_SyncNodeRegistry_Call) Return(err error) *MockService_SyncNodeRegistry_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_QueryUsers_Call) RunAndReturn(run func(ctx context.Context, opt *QueryUserOptions) error) *MockService_QueryUsers_Call {
+func (_c *MockService_SyncNodeRegistry_Call) RunAndReturn(run func(ctx context.Context) error) *MockService_SyncNodeRegistry_Call {
 	_c.Call.Return(run)
 	return _c
-}
-
-// ResetPassword provides a mock function for the type MockService
-func (_mock *MockService) ResetPassword(ctx context.Context, operator *Claims, id string, newPassword string) error {
-	ret := _mock.Called(ctx, operator, id, newPassword)
+}
+
+// UpdateRole provides a mock function for the type MockService
+func (_mock *MockService) UpdateRole(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error {
+	ret := _mock.Called(ctx, operator, roleID, opt)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetPassword")
+		panic("no return value specified for UpdateRole")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, string) error); ok {
-		r0 = returnFunc(ctx, operator, id, newPassword)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateRoleOptions) error); ok {
+		r0 = returnFunc(ctx, operator, roleID, opt)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
-type MockService_ResetPassword_Call struct {
+// MockService_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
+type MockService_UpdateRole_Call struct {
 	*mock.Call
 }
 
-// ResetPassword is a helper method to define mock.On call
+// UpdateRole is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - id string
-//   - newPassword string
-func (_e *MockService_Expecter) ResetPassword(ctx interface{}, operator interface{}, id interface{}, newPassword interface{}) *MockService_ResetPassword_Call {
-	return &MockService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, operator, id, newPassword)}
+//   - roleID string
+//   - opt UpdateRoleOptions
+func (_e *MockService_Expecter) UpdateRole(ctx interface{}, operator interface{}, roleID interface{}, opt interface{}) *MockService_UpdateRole_Call {
+	return &MockService_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, operator, roleID, opt)}
 }
 
-func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, operator *Claims, id string, newPassword string)) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions)) *MockService_UpdateRole_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1724,9 +3335,9 @@ func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, oper
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 string
+		var arg3 UpdateRoleOptions
 		if args[3] != nil {
-			arg3 = args[3].(string)
+			arg3 = args[3].(UpdateRoleOptions)
 		}
 		run(
 			arg0,
@@ -1738,48 +3349,48 @@ func (_c *MockService_ResetPassword_Call) Run(run func(ctx context.Context, oper
 	return _c
 }
 
-func (_c *MockService_ResetPassword_Call) Return(err error) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateRole_Call) Return(err error) *MockService_UpdateRole_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_ResetPassword_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, id string, newPassword string) error) *MockService_ResetPassword_Call {
+func (_c *MockService_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error) *MockService_UpdateRole_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateRole provides a mock function for the type MockService
-func (_mock *MockService) UpdateRole(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error {
-	ret := _mock.Called(ctx, operator, roleID, opt)
+// UpdateScheduleStrategy provides a mock function for the type MockService
+func (_mock *MockService) UpdateScheduleStrategy(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error {
+	ret := _mock.Called(ctx, operator, strategyID, strategy)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateRole")
+		panic("no return value specified for UpdateScheduleStrategy")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, UpdateRoleOptions) error); ok {
-		r0 = returnFunc(ctx, operator, roleID, opt)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *Claims, string, *ScheduleStrategy) error); ok {
+		r0 = returnFunc(ctx, operator, strategyID, strategy)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockService_UpdateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateRole'
-type MockService_UpdateRole_Call struct {
+// MockService_UpdateScheduleStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateScheduleStrategy'
+type MockService_UpdateScheduleStrategy_Call struct {
 	*mock.Call
 }
 
-// UpdateRole is a helper method to define mock.On call
+// UpdateScheduleStrategy is a helper method to define mock.On call
 //   - ctx context.Context
 //   - operator *Claims
-//   - roleID string
-//   - opt UpdateRoleOptions
-func (_e *MockService_Expecter) UpdateRole(ctx interface{}, operator interface{}, roleID interface{}, opt interface{}) *MockService_UpdateRole_Call {
-	return &MockService_UpdateRole_Call{Call: _e.mock.On("UpdateRole", ctx, operator, roleID, opt)}
+//   - strategyID string
+//   - strategy *ScheduleStrategy
+func (_e *MockService_Expecter) UpdateScheduleStrategy(ctx interface{}, operator interface{}, strategyID interface{}, strategy interface{}) *MockService_UpdateScheduleStrategy_Call {
+	return &MockService_UpdateScheduleStrategy_Call{Call: _e.mock.On("UpdateScheduleStrategy", ctx, operator, strategyID, strategy)}
 }
 
-func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions)) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) Run(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy)) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -1793,9 +3404,9 @@ func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operato
 		if args[2] != nil {
 			arg2 = args[2].(string)
 		}
-		var arg3 UpdateRoleOptions
+		var arg3 *ScheduleStrategy
 		if args[3] != nil {
-			arg3 = args[3].(UpdateRoleOptions)
+			arg3 = args[3].(*ScheduleStrategy)
 		}
 		run(
 			arg0,
@@ -1807,12 +3418,12 @@ func (_c *MockService_UpdateRole_Call) Run(run func(ctx context.Context, operato
 	return _c
 }
 
-func (_c *MockService_UpdateRole_Call) Return(err error) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) Return(err error) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockService_UpdateRole_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, roleID string, opt UpdateRoleOptions) error) *MockService_UpdateRole_Call {
+func (_c *MockService_UpdateScheduleStrategy_Call) RunAndReturn(run func(ctx context.Context, operator *Claims, strategyID string, strategy *ScheduleStrategy) error) *MockService_UpdateScheduleStrategy_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2127,6 +3738,68 @@ func (_c *MockK8SAdapter_QueryPods_Call) RunAndReturn(run func(ctx context.Conte
 	return _c
 }
 
+// SubscribePodEvents provides a mock function for the type MockK8SAdapter
+func (_mock *MockK8SAdapter) SubscribePodEvents(ctx context.Context) (<-chan PodEvent, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribePodEvents")
+	}
+
+	var r0 <-chan PodEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (<-chan PodEvent, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) <-chan PodEvent); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan PodEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockK8SAdapter_SubscribePodEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscribePodEvents'
+type MockK8SAdapter_SubscribePodEvents_Call struct {
+	*mock.Call
+}
+
+// SubscribePodEvents is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockK8SAdapter_Expecter) SubscribePodEvents(ctx interface{}) *MockK8SAdapter_SubscribePodEvents_Call {
+	return &MockK8SAdapter_SubscribePodEvents_Call{Call: _e.mock.On("SubscribePodEvents", ctx)}
+}
+
+func (_c *MockK8SAdapter_SubscribePodEvents_Call) Run(run func(ctx context.Context)) *MockK8SAdapter_SubscribePodEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockK8SAdapter_SubscribePodEvents_Call) Return(podEvent <-chan PodEvent, err error) *MockK8SAdapter_SubscribePodEvents_Call {
+	_c.Call.Return(podEvent, err)
+	return _c
+}
+
+func (_c *MockK8SAdapter_SubscribePodEvents_Call) RunAndReturn(run func(ctx context.Context) (<-chan PodEvent, error)) *MockK8SAdapter_SubscribePodEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockDecisionMakerAdapter creates a new instance of MockDecisionMakerAdapter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockDecisionMakerAdapter(t interface {
@@ -2154,12 +3827,12 @@ func (_m *MockDecisionMakerAdapter) EXPECT() *MockDecisionMakerAdapter_Expecter
 	return &MockDecisionMakerAdapter_Expecter{mock: &_m.Mock}
 }
 
-// SendSchedulingIntent provides a mock function for the type MockDecisionMakerAdapter
-func (_mock *MockDecisionMakerAdapter) SendSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error {
+// DeleteSchedulingIntent provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) DeleteSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error {
 	ret := _mock.Called(ctx, decisionMaker, intents)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendSchedulingIntent")
+		panic("no return value specified for DeleteSchedulingIntent")
 	}
 
 	var r0 error
@@ -2171,6 +3844,228 @@ func (_mock *MockDecisionMakerAdapter) SendSchedulingIntent(ctx context.Context,
 	return r0
 }
 
+// MockDecisionMakerAdapter_DeleteSchedulingIntent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteSchedulingIntent'
+type MockDecisionMakerAdapter_DeleteSchedulingIntent_Call struct {
+	*mock.Call
+}
+
+// DeleteSchedulingIntent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+//   - intents []*ScheduleIntent
+func (_e *MockDecisionMakerAdapter_Expecter) DeleteSchedulingIntent(ctx interface{}, decisionMaker interface{}, intents interface{}) *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call {
+	return &MockDecisionMakerAdapter_DeleteSchedulingIntent_Call{Call: _e.mock.On("DeleteSchedulingIntent", ctx, decisionMaker, intents)}
+}
+
+func (_c *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent)) *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		var arg2 []*ScheduleIntent
+		if args[2] != nil {
+			arg2 = args[2].([]*ScheduleIntent)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call) Return(err error) *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error) *MockDecisionMakerAdapter_DeleteSchedulingIntent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMetricsHistory provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) GetMetricsHistory(ctx context.Context, decisionMaker *DecisionMakerPod, from time.Time, to time.Time) ([]*NodeMetricSample, error) {
+	ret := _mock.Called(ctx, decisionMaker, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMetricsHistory")
+	}
+
+	var r0 []*NodeMetricSample
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, time.Time, time.Time) ([]*NodeMetricSample, error)); ok {
+		return returnFunc(ctx, decisionMaker, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, time.Time, time.Time) []*NodeMetricSample); ok {
+		r0 = returnFunc(ctx, decisionMaker, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*NodeMetricSample)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, decisionMaker, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDecisionMakerAdapter_GetMetricsHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMetricsHistory'
+type MockDecisionMakerAdapter_GetMetricsHistory_Call struct {
+	*mock.Call
+}
+
+// GetMetricsHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+//   - from time.Time
+//   - to time.Time
+func (_e *MockDecisionMakerAdapter_Expecter) GetMetricsHistory(ctx interface{}, decisionMaker interface{}, from interface{}, to interface{}) *MockDecisionMakerAdapter_GetMetricsHistory_Call {
+	return &MockDecisionMakerAdapter_GetMetricsHistory_Call{Call: _e.mock.On("GetMetricsHistory", ctx, decisionMaker, from, to)}
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricsHistory_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod, from time.Time, to time.Time)) *MockDecisionMakerAdapter_GetMetricsHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricsHistory_Call) Return(nodeMetricSamples []*NodeMetricSample, err error) *MockDecisionMakerAdapter_GetMetricsHistory_Call {
+	_c.Call.Return(nodeMetricSamples, err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_GetMetricsHistory_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, from time.Time, to time.Time) ([]*NodeMetricSample, error)) *MockDecisionMakerAdapter_GetMetricsHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ProbeDecisionMaker provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) ProbeDecisionMaker(ctx context.Context, decisionMaker *DecisionMakerPod) (*DecisionMakerStatus, error) {
+	ret := _mock.Called(ctx, decisionMaker)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProbeDecisionMaker")
+	}
+
+	var r0 *DecisionMakerStatus
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod) (*DecisionMakerStatus, error)); ok {
+		return returnFunc(ctx, decisionMaker)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod) *DecisionMakerStatus); ok {
+		r0 = returnFunc(ctx, decisionMaker)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DecisionMakerStatus)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod) error); ok {
+		r1 = returnFunc(ctx, decisionMaker)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockDecisionMakerAdapter_ProbeDecisionMaker_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ProbeDecisionMaker'
+type MockDecisionMakerAdapter_ProbeDecisionMaker_Call struct {
+	*mock.Call
+}
+
+// ProbeDecisionMaker is a helper method to define mock.On call
+//   - ctx context.Context
+//   - decisionMaker *DecisionMakerPod
+func (_e *MockDecisionMakerAdapter_Expecter) ProbeDecisionMaker(ctx interface{}, decisionMaker interface{}) *MockDecisionMakerAdapter_ProbeDecisionMaker_Call {
+	return &MockDecisionMakerAdapter_ProbeDecisionMaker_Call{Call: _e.mock.On("ProbeDecisionMaker", ctx, decisionMaker)}
+}
+
+func (_c *MockDecisionMakerAdapter_ProbeDecisionMaker_Call) Run(run func(ctx context.Context, decisionMaker *DecisionMakerPod)) *MockDecisionMakerAdapter_ProbeDecisionMaker_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *DecisionMakerPod
+		if args[1] != nil {
+			arg1 = args[1].(*DecisionMakerPod)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_ProbeDecisionMaker_Call) Return(decisionMakerStatus *DecisionMakerStatus, err error) *MockDecisionMakerAdapter_ProbeDecisionMaker_Call {
+	_c.Call.Return(decisionMakerStatus, err)
+	return _c
+}
+
+func (_c *MockDecisionMakerAdapter_ProbeDecisionMaker_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod) (*DecisionMakerStatus, error)) *MockDecisionMakerAdapter_ProbeDecisionMaker_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendSchedulingIntent provides a mock function for the type MockDecisionMakerAdapter
+func (_mock *MockDecisionMakerAdapter) SendSchedulingIntent(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) ([]*ScheduleIntentResult, error) {
+	ret := _mock.Called(ctx, decisionMaker, intents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendSchedulingIntent")
+	}
+
+	var r0 []*ScheduleIntentResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, []*ScheduleIntent) ([]*ScheduleIntentResult, error)); ok {
+		return returnFunc(ctx, decisionMaker, intents)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *DecisionMakerPod, []*ScheduleIntent) []*ScheduleIntentResult); ok {
+		r0 = returnFunc(ctx, decisionMaker, intents)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ScheduleIntentResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *DecisionMakerPod, []*ScheduleIntent) error); ok {
+		r1 = returnFunc(ctx, decisionMaker, intents)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
 // MockDecisionMakerAdapter_SendSchedulingIntent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendSchedulingIntent'
 type MockDecisionMakerAdapter_SendSchedulingIntent_Call struct {
 	*mock.Call
@@ -2207,12 +4102,12 @@ func (_c *MockDecisionMakerAdapter_SendSchedulingIntent_Call) Run(run func(ctx c
 	return _c
 }
 
-func (_c *MockDecisionMakerAdapter_SendSchedulingIntent_Call) Return(err error) *MockDecisionMakerAdapter_SendSchedulingIntent_Call {
-	_c.Call.Return(err)
+func (_c *MockDecisionMakerAdapter_SendSchedulingIntent_Call) Return(scheduleIntentResults []*ScheduleIntentResult, err error) *MockDecisionMakerAdapter_SendSchedulingIntent_Call {
+	_c.Call.Return(scheduleIntentResults, err)
 	return _c
 }
 
-func (_c *MockDecisionMakerAdapter_SendSchedulingIntent_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) error) *MockDecisionMakerAdapter_SendSchedulingIntent_Call {
+func (_c *MockDecisionMakerAdapter_SendSchedulingIntent_Call) RunAndReturn(run func(ctx context.Context, decisionMaker *DecisionMakerPod, intents []*ScheduleIntent) ([]*ScheduleIntentResult, error)) *MockDecisionMakerAdapter_SendSchedulingIntent_Call {
 	_c.Call.Return(run)
 	return _c
 }
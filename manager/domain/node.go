@@ -0,0 +1,67 @@
+package domain
+
+// NodeMetricSample is the scheduler statistics the decision maker of a node received at one point in time.
+// Counters include the values of previous scheduler runs.
+type NodeMetricSample struct {
+	Timestamp           int64 // unix milliseconds
+	NrQueued            float64
+	NrScheduled         float64
+	NrRunning           float64
+	NrOnlineCPUs        float64
+	NrUserDispatches    uint64
+	NrKernelDispatches  uint64
+	NrCancelDispatches  uint64
+	NrBounceDispatches  uint64
+	NrFailedDispatches  uint64
+	NrSchedCongested    uint64
+	DispatchRate        float64
+	FailedDispatchRatio float64
+}
+
+// NodeMetrics is the outcome of the last scrape of the decision maker of a node
+type NodeMetrics struct {
+	NodeID    string
+	Host      string
+	ScrapedAt int64             // unix milliseconds
+	Error     string            // why the last scrape failed, Sample is then the one of the last successful scrape
+	Sample    *NodeMetricSample // nil until a scrape succeeded
+}
+
+// Reporting reports whether the last scrape of the node returned a sample
+func (m *NodeMetrics) Reporting() bool {
+	return m.Error == "" && m.Sample != nil
+}
+
+// ClusterMetrics aggregates the latest samples of the nodes whose last scrape succeeded
+type ClusterMetrics struct {
+	ScrapedAt          int64 // unix milliseconds
+	NodesReporting     int
+	NrQueued           float64
+	NrScheduled        float64
+	NrRunning          float64
+	NrFailedDispatches uint64
+	NrSchedCongested   uint64
+	DispatchRate       float64
+	Nodes              []*NodeMetrics
+}
+
+// Node is a node running a decision maker, as recorded in the node registry
+type Node struct {
+	BaseEntity      `bson:",inline"`
+	NodeID          string              `bson:"nodeID,omitempty"`
+	Host            string              `bson:"host,omitempty"`
+	Port            int                 `bson:"port,omitempty"`
+	State           NodeState           `bson:"state,omitempty"`
+	Health          string              `bson:"health,omitempty"`          // status reported by the /health endpoint of the decision maker
+	DMVersion       string              `bson:"dmVersion,omitempty"`       // version reported by the /version endpoint of the decision maker
+	LastContactTime int64               `bson:"lastContactTime,omitempty"` // unix milliseconds of the last successful probe
+	LastProbeTime   int64               `bson:"lastProbeTime,omitempty"`   // unix milliseconds of the last probe, successful or not
+	LastError       string              `bson:"lastError,omitempty"`       // why the node is offline
+	IntentCounts    map[IntentState]int `bson:"-"`                         // intents targeting the node per state, filled in when read
+}
+
+// DecisionMakerStatus is what a probe of a decision maker returned
+type DecisionMakerStatus struct {
+	Health  string
+	Version string
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"cmp"
+	"strings"
+
 	"github.com/Gthulhu/api/pkg/util"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -9,10 +12,42 @@ type ScheduleStrategy struct {
 	BaseEntity        `bson:",inline"`
 	StrategyNamespace string          `bson:"strategyNamespace,omitempty"`
 	LabelSelectors    []LabelSelector `bson:"labelSelectors,omitempty"`
-	K8sNamespace      []string        `bson:"k8sNamespace,omitempty"`
-	CommandRegex      string          `bson:"commandRegex,omitempty"`
-	Priority          int             `bson:"priority,omitempty"`
-	ExecutionTime     int64           `bson:"executionTime,omitempty"`
+	// MatchExpressions are set based label requirements the pods have to meet on top of LabelSelectors
+	MatchExpressions []MatchExpression `bson:"matchExpressions,omitempty"`
+	FieldFilter      *PodFieldFilter   `bson:"fieldFilter,omitempty"`
+	K8sNamespace     []string          `bson:"k8sNamespace,omitempty"`
+	CommandRegex     string            `bson:"commandRegex,omitempty"`
+	Priority         int               `bson:"priority,omitempty"`
+	ExecutionTime    int64             `bson:"executionTime,omitempty"`
+	// Weight ranks strategies selecting the same pod, see CompareStrategyPrecedence
+	Weight int `bson:"weight,omitempty"`
+}
+
+// CompareStrategyPrecedence orders two strategies selecting the same pod, a positive result means a takes precedence
+// over b. The higher weight wins, then a strategy restricted to namespaces beats a cluster-wide one, then the newest
+// strategy wins.
+func CompareStrategyPrecedence(a, b *ScheduleStrategy) int {
+	if c := cmp.Compare(a.Weight, b.Weight); c != 0 {
+		return c
+	}
+	if aScoped, bScoped := len(a.K8sNamespace) > 0, len(b.K8sNamespace) > 0; aScoped != bScoped {
+		if aScoped {
+			return 1
+		}
+		return -1
+	}
+	if c := cmp.Compare(a.CreatedTime, b.CreatedTime); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID.Hex(), b.ID.Hex())
+}
+
+// StrategyConflict is an existing strategy selecting some of the pods of a new strategy
+type StrategyConflict struct {
+	StrategyID bson.ObjectID
+	PodIDs     []string
+	// Wins reports whether the existing strategy takes precedence over the new one on those pods
+	Wins bool
 }
 
 func NewScheduleIntent(strategy *ScheduleStrategy, pod *Pod) ScheduleIntent {
@@ -32,20 +67,99 @@ func NewScheduleIntent(strategy *ScheduleStrategy, pod *Pod) ScheduleIntent {
 }
 
 type ScheduleIntent struct {
-	BaseEntity    `bson:",inline"`
-	StrategyID    bson.ObjectID     `bson:"strategyID,omitempty"`
-	PodID         string            `bson:"podID,omitempty"`
-	PodName       string            `bson:"podName,omitempty"`
-	NodeID        string            `bson:"nodeID,omitempty"`
-	K8sNamespace  string            `bson:"k8sNamespace,omitempty"`
-	CommandRegex  string            `bson:"commandRegex,omitempty"`
-	Priority      int               `bson:"priority,omitempty"`
-	ExecutionTime int64             `bson:"executionTime,omitempty"`
-	PodLabels     map[string]string `bson:"podLabels,omitempty"`
-	State         IntentState       `bson:"state,omitempty"`
+	BaseEntity      `bson:",inline"`
+	StrategyID      bson.ObjectID     `bson:"strategyID,omitempty"`
+	PodID           string            `bson:"podID,omitempty"`
+	PodName         string            `bson:"podName,omitempty"`
+	NodeID          string            `bson:"nodeID,omitempty"`
+	K8sNamespace    string            `bson:"k8sNamespace,omitempty"`
+	CommandRegex    string            `bson:"commandRegex,omitempty"`
+	Priority        int               `bson:"priority,omitempty"`
+	ExecutionTime   int64             `bson:"executionTime,omitempty"`
+	PodLabels       map[string]string `bson:"podLabels,omitempty"`
+	State           IntentState       `bson:"state,omitempty"`
+	Attempts        int               `bson:"attempts,omitempty"`        // deliveries to the decision maker, successful or not
+	LastError       string            `bson:"lastError,omitempty"`       // error of the last failed delivery
+	NextAttemptTime int64             `bson:"nextAttemptTime,omitempty"` // unix milliseconds before which delivery is not retried
+	ClaimedUntil    int64             `bson:"claimedUntil,omitempty"`    // unix milliseconds until which a delivery holds the intent
+	PIDCount        int               `bson:"pidCount,omitempty"`        // processes the decision maker applied the intent to
+	Reason          string            `bson:"reason,omitempty"`          // why the decision maker could not apply the intent
+	// WinningStrategyID is the strategy taking precedence on the pod, the intent is overridden unless it is StrategyID
+	WinningStrategyID bson.ObjectID `bson:"winningStrategyID,omitempty"`
+}
+
+// IntentResolution moves an intent to the outcome of resolving the strategies selecting its pod. It only applies
+// while the intent is still in FromState and not claimed by a delivery.
+type IntentResolution struct {
+	IntentID          bson.ObjectID
+	FromState         IntentState
+	State             IntentState
+	WinningStrategyID bson.ObjectID
+	// Superseded removes the intent instead, a retraction that would remove the winner from the decision maker
+	Superseded bool
+}
+
+// ScheduleIntentResult is reported by a decision maker for every intent it received
+type ScheduleIntentResult struct {
+	IntentID bson.ObjectID
+	State    IntentState
+	PIDCount int
+	Reason   string
+}
+
+// StrategyPreview describes what a strategy would select if it were created now
+type StrategyPreview struct {
+	MatchedPods int
+	Nodes       []*StrategyPreviewNode // sorted by node ID
+	// Strategies already have an intent for one of the matched pods
+	Strategies []*ScheduleStrategy
+}
+
+// StrategyPreviewNode holds the matched pods of a node grouped by namespace
+type StrategyPreviewNode struct {
+	NodeID                 string
+	DecisionMakerReachable bool
+	DecisionMakerError     string
+	Namespaces             []*StrategyPreviewNamespace // sorted by namespace
+}
+
+type StrategyPreviewNamespace struct {
+	K8sNamespace string
+	Pods         []*StrategyPreviewPod // sorted by pod name
+}
+
+type StrategyPreviewPod struct {
+	Pod         *Pod
+	StrategyIDs []bson.ObjectID // existing strategies with an intent for the pod
 }
 
 type LabelSelector struct {
 	Key   string `bson:"key,omitempty"`
 	Value string `bson:"value,omitempty"`
 }
+
+type MatchOperator string
+
+const (
+	MatchOperatorIn           MatchOperator = "In"
+	MatchOperatorNotIn        MatchOperator = "NotIn"
+	MatchOperatorExists       MatchOperator = "Exists"
+	MatchOperatorDoesNotExist MatchOperator = "DoesNotExist"
+)
+
+// MatchExpression is a set based label requirement as in the matchExpressions of a kubernetes label selector
+type MatchExpression struct {
+	Key      string        `bson:"key,omitempty"`
+	Operator MatchOperator `bson:"operator,omitempty"`
+	Values   []string      `bson:"values,omitempty"` // required by In and NotIn, not allowed otherwise
+}
+
+// PodFieldFilter narrows the selected pods down by their fields, an empty field matches every pod
+type PodFieldFilter struct {
+	NodeNames  []string `bson:"nodeNames,omitempty"`
+	QOSClasses []string `bson:"qosClasses,omitempty"` // Guaranteed, Burstable or BestEffort
+	Phases     []string `bson:"phases,omitempty"`     // Pending, Running, Succeeded, Failed or Unknown
+	// OwnerKind and OwnerName match an owner reference of the pod, e.g. the ReplicaSet of a Deployment
+	OwnerKind string `bson:"ownerKind,omitempty"`
+	OwnerName string `bson:"ownerName,omitempty"`
+}
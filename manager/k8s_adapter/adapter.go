@@ -3,7 +3,9 @@ package k8sadapter
 import (
 	"context"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,8 +14,11 @@ import (
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/pkg/logger"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -38,8 +43,13 @@ type Adapter struct {
 	startWatcher   sync.Once
 	stopWatcher    sync.Once
 	cacheHasSynced atomic.Bool
+	subscribers    map[chan domain.PodEvent]struct{}
+	subscribersMu  sync.RWMutex
 }
 
+// podEventBufferSize bounds the events queued per subscriber, events beyond it are dropped
+const podEventBufferSize = 256
+
 func NewAdapter(opt Options) (*Adapter, error) {
 	config, err := buildConfig(opt)
 	if err != nil {
@@ -56,9 +66,10 @@ func NewAdapter(opt Options) (*Adapter, error) {
 	}
 
 	adapter := &Adapter{
-		client:   client,
-		podCache: make(map[string]apiv1.Pod),
-		stopCh:   make(chan struct{}),
+		client:      client,
+		podCache:    make(map[string]apiv1.Pod),
+		stopCh:      make(chan struct{}),
+		subscribers: make(map[chan domain.PodEvent]struct{}),
 	}
 	adapter.startPodWatcher()
 
@@ -99,23 +110,30 @@ func (a *Adapter) startPodWatcher() {
 				}
 				logger.Logger(context.Background()).Debug().Msgf("pod added: %s/%s", pod.Namespace, pod.Name)
 				a.setPodCache(*pod)
+				a.publishPodEvent(domain.PodEventAdded, *pod)
 			},
-			UpdateFunc: func(_, newObj interface{}) {
+			UpdateFunc: func(oldObj, newObj interface{}) {
 				pod, ok := newObj.(*apiv1.Pod)
 				if !ok {
 					return
 				}
 				logger.Logger(context.Background()).Debug().Msgf("pod updated: %s/%s", pod.Namespace, pod.Name)
 				a.setPodCache(*pod)
+				if oldPod, ok := oldObj.(*apiv1.Pod); ok && !podSchedulingChanged(*oldPod, *pod) {
+					return
+				}
+				a.publishPodEvent(domain.PodEventUpdated, *pod)
 			},
 			DeleteFunc: func(obj interface{}) {
 				switch pod := obj.(type) {
 				case *apiv1.Pod:
 					logger.Logger(context.Background()).Debug().Msgf("pod deleted: %s/%s", pod.Namespace, pod.Name)
 					a.deletePodCache(string(pod.UID))
+					a.publishPodEvent(domain.PodEventDeleted, *pod)
 				case cache.DeletedFinalStateUnknown:
 					if p, ok := pod.Obj.(*apiv1.Pod); ok {
 						a.deletePodCache(string(p.UID))
+						a.publishPodEvent(domain.PodEventDeleted, *p)
 					}
 				}
 			},
@@ -137,6 +155,54 @@ func (a *Adapter) StopPodWatcher() {
 	})
 }
 
+// SubscribePodEvents returns a channel receiving pod add, update and delete events observed by the pod informer.
+// The channel is closed once ctx is done. Events are dropped when the subscriber does not keep up.
+func (a *Adapter) SubscribePodEvents(ctx context.Context) (<-chan domain.PodEvent, error) {
+	if a == nil || a.client == nil {
+		return nil, domain.ErrNoClient
+	}
+
+	ch := make(chan domain.PodEvent, podEventBufferSize)
+	a.subscribersMu.Lock()
+	if a.subscribers == nil {
+		a.subscribers = make(map[chan domain.PodEvent]struct{})
+	}
+	a.subscribers[ch] = struct{}{}
+	a.subscribersMu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-a.stopCh:
+		}
+		a.subscribersMu.Lock()
+		delete(a.subscribers, ch)
+		close(ch)
+		a.subscribersMu.Unlock()
+	}()
+	return ch, nil
+}
+
+func (a *Adapter) publishPodEvent(eventType domain.PodEventType, pod apiv1.Pod) {
+	a.subscribersMu.RLock()
+	defer a.subscribersMu.RUnlock()
+	if len(a.subscribers) == 0 {
+		return
+	}
+
+	event := domain.PodEvent{
+		Type: eventType,
+		Pod:  convertPod(pod, buildContainers(pod, nil)),
+	}
+	for ch := range a.subscribers {
+		select {
+		case ch <- event:
+		default:
+			logger.Logger(context.Background()).Warn().Msgf("pod event subscriber is full, drop %d event of pod %s/%s", eventType, pod.Namespace, pod.Name)
+		}
+	}
+}
+
 func (a *Adapter) QueryPods(ctx context.Context, opt *domain.QueryPodsOptions) ([]*domain.Pod, error) {
 	if opt == nil {
 		return nil, domain.ErrNilQueryInput
@@ -145,7 +211,10 @@ func (a *Adapter) QueryPods(ctx context.Context, opt *domain.QueryPodsOptions) (
 		return nil, domain.ErrNoClient
 	}
 
-	labelSelector := buildLabelSelector(opt.LabelSelectors)
+	labelSelector, err := buildLabelSelector(opt.LabelSelectors, opt.MatchExpressions)
+	if err != nil {
+		return nil, err
+	}
 	namespaces := opt.K8SNamespace
 	if len(namespaces) == 0 {
 		namespaces = []string{metav1.NamespaceAll}
@@ -160,28 +229,22 @@ func (a *Adapter) QueryPods(ctx context.Context, opt *domain.QueryPodsOptions) (
 		cmdRegex = re
 	}
 
-	pods, err := a.listPods(ctx, namespaces, labelSelector)
+	pods, err := a.listPods(ctx, namespaces, labelSelector, buildFieldSelector(opt.FieldFilter))
 	if err != nil {
 		return nil, err
 	}
 	results := make([]*domain.Pod, 0, len(pods))
 
 	for _, pod := range pods {
+		if !matchesFieldFilter(pod, opt.FieldFilter) {
+			continue
+		}
 		containers := buildContainers(pod, cmdRegex)
 		if cmdRegex != nil && len(containers) == 0 {
 			continue
 		}
 
-		podLabels := copyLabels(pod.Labels)
-		result := &domain.Pod{
-			Name:         pod.Name,
-			K8SNamespace: pod.Namespace,
-			Labels:       podLabels,
-			PodID:        string(pod.UID),
-			NodeID:       pod.Spec.NodeName,
-			Containers:   containers,
-		}
-		results = append(results, result)
+		results = append(results, convertPod(pod, containers))
 	}
 
 	return results, nil
@@ -196,7 +259,6 @@ func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryD
 		return nil, domain.ErrNoClient
 	}
 
-	labelSelector := buildLabelSelector([]domain.LabelSelector{opt.DecisionMakerLabel})
 	namespaces := opt.K8SNamespace
 	if len(namespaces) == 0 {
 		namespaces = []string{metav1.NamespaceAll}
@@ -207,7 +269,15 @@ func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryD
 		nodeFilters[id] = struct{}{}
 	}
 
-	pods, err := a.listPods(ctx, namespaces, labelSelector)
+	if opt.ServiceName != "" {
+		return a.queryDecisionMakerEndpoints(ctx, namespaces, opt.ServiceName, opt.PortName, nodeFilters)
+	}
+
+	labelSelector, err := buildLabelSelector(opt.DecisionMakerLabels, nil)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := a.listPods(ctx, namespaces, labelSelector, "")
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +289,12 @@ func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryD
 			}
 		}
 
+		port := containerPort(pod, opt.PortName)
+		if port == 0 && opt.PortName != "" {
+			logger.Logger(ctx).Debug().Msgf("decision maker pod %s/%s has no port named %q", pod.Namespace, pod.Name, opt.PortName)
+			continue
+		}
+
 		host := pod.Status.PodIP
 		if host == "" {
 			host = pod.Status.HostIP
@@ -226,7 +302,7 @@ func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryD
 
 		results = append(results, &domain.DecisionMakerPod{
 			NodeID: pod.Spec.NodeName,
-			Port:   firstContainerPort(pod),
+			Port:   port,
 			Host:   host,
 			State:  mapPodState(pod.Status.Phase),
 		})
@@ -235,17 +311,52 @@ func (a *Adapter) QueryDecisionMakerPods(ctx context.Context, opt *domain.QueryD
 	return results, nil
 }
 
-func (a *Adapter) listPods(ctx context.Context, namespaces []string, labelSelector string) ([]apiv1.Pod, error) {
-	selector, err := labels.Parse(labelSelector)
-	if err != nil {
-		return nil, fmt.Errorf("parse label selector %q: %w", labelSelector, err)
+// queryDecisionMakerEndpoints resolves the decision makers through the EndpointSlices of a headless Service, so that
+// only the pods and ports selected by the Service are used
+func (a *Adapter) queryDecisionMakerEndpoints(ctx context.Context, namespaces []string, serviceName string, portName string, nodeFilters map[string]struct{}) ([]*domain.DecisionMakerPod, error) {
+	results := make([]*domain.DecisionMakerPod, 0)
+	for _, ns := range namespaces {
+		endpointSlices, err := a.client.DiscoveryV1().EndpointSlices(ns).List(ctx, metav1.ListOptions{
+			LabelSelector: discoveryv1.LabelServiceName + "=" + serviceName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list endpoint slices of service %s in namespace %s: %w", serviceName, ns, err)
+		}
+		for _, endpointSlice := range endpointSlices.Items {
+			port := endpointSlicePort(endpointSlice, portName)
+			if port == 0 {
+				logger.Logger(ctx).Debug().Msgf("endpoint slice %s/%s has no port named %q", endpointSlice.Namespace, endpointSlice.Name, portName)
+				continue
+			}
+			for _, endpoint := range endpointSlice.Endpoints {
+				if endpoint.NodeName == nil || len(endpoint.Addresses) == 0 {
+					continue
+				}
+				if len(nodeFilters) > 0 {
+					if _, ok := nodeFilters[*endpoint.NodeName]; !ok {
+						continue
+					}
+				}
+				results = append(results, &domain.DecisionMakerPod{
+					NodeID: *endpoint.NodeName,
+					Port:   port,
+					Host:   endpoint.Addresses[0],
+					State:  mapEndpointState(endpoint.Conditions),
+				})
+			}
+		}
 	}
+	return results, nil
+}
 
+// listPods returns the pods matching the label selector from the informer cache once it has synced, or lists them
+// from the API server with the field selector narrowing the list down. Callers still filter the fields themselves.
+func (a *Adapter) listPods(ctx context.Context, namespaces []string, selector labels.Selector, fieldSelector string) ([]apiv1.Pod, error) {
 	if a.cacheHasSynced.Load() {
 		return a.podsFromCache(namespaces, selector), nil
 	}
 
-	return a.listPodsLive(ctx, namespaces, labelSelector)
+	return a.listPodsLive(ctx, namespaces, selector.String(), fieldSelector)
 }
 
 func (a *Adapter) podsFromCache(namespaces []string, selector labels.Selector) []apiv1.Pod {
@@ -265,7 +376,7 @@ func (a *Adapter) podsFromCache(namespaces []string, selector labels.Selector) [
 				continue
 			}
 		}
-		if selector.String() != "" && !selector.Matches(labels.Set(pod.Labels)) {
+		if !selector.Empty() && !selector.Matches(labels.Set(pod.Labels)) {
 			continue
 		}
 		pods = append(pods, pod)
@@ -273,11 +384,12 @@ func (a *Adapter) podsFromCache(namespaces []string, selector labels.Selector) [
 	return pods
 }
 
-func (a *Adapter) listPodsLive(ctx context.Context, namespaces []string, labelSelector string) ([]apiv1.Pod, error) {
+func (a *Adapter) listPodsLive(ctx context.Context, namespaces []string, labelSelector string, fieldSelector string) ([]apiv1.Pod, error) {
 	results := make([]apiv1.Pod, 0)
 	for _, ns := range namespaces {
 		pods, err := a.client.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
 			LabelSelector: labelSelector,
+			FieldSelector: fieldSelector,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("list pods in namespace %s: %w", ns, err)
@@ -302,19 +414,84 @@ func (a *Adapter) deletePodCache(uid string) {
 	a.podCacheMu.Unlock()
 }
 
-func buildLabelSelector(selectors []domain.LabelSelector) string {
-	labels := make([]string, 0, len(selectors))
+var matchOperators = map[domain.MatchOperator]selection.Operator{
+	domain.MatchOperatorIn:           selection.In,
+	domain.MatchOperatorNotIn:        selection.NotIn,
+	domain.MatchOperatorExists:       selection.Exists,
+	domain.MatchOperatorDoesNotExist: selection.DoesNotExist,
+}
+
+// buildLabelSelector combines the key/value selectors, a selector without value only requires the key, and the
+// match expressions into a single label selector
+func buildLabelSelector(selectors []domain.LabelSelector, expressions []domain.MatchExpression) (labels.Selector, error) {
+	labelSelector := labels.NewSelector()
 	for _, selector := range selectors {
 		if selector.Key == "" {
 			continue
 		}
-		if selector.Value == "" {
-			labels = append(labels, selector.Key)
-			continue
+		operator, values := selection.Exists, []string(nil)
+		if selector.Value != "" {
+			operator, values = selection.Equals, []string{selector.Value}
+		}
+		requirement, err := labels.NewRequirement(selector.Key, operator, values)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidSelector, err)
+		}
+		labelSelector = labelSelector.Add(*requirement)
+	}
+	for _, expression := range expressions {
+		operator, ok := matchOperators[expression.Operator]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown operator %q of key %q", domain.ErrInvalidSelector, expression.Operator, expression.Key)
 		}
-		labels = append(labels, fmt.Sprintf("%s=%s", selector.Key, selector.Value))
+		requirement, err := labels.NewRequirement(expression.Key, operator, expression.Values)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidSelector, err)
+		}
+		labelSelector = labelSelector.Add(*requirement)
+	}
+	return labelSelector, nil
+}
+
+// buildFieldSelector narrows a live list down by the single valued node name and phase of the filter, the API server
+// supports no set based field selectors
+func buildFieldSelector(filter *domain.PodFieldFilter) string {
+	if filter == nil {
+		return ""
+	}
+	set := fields.Set{}
+	if len(filter.NodeNames) == 1 {
+		set["spec.nodeName"] = filter.NodeNames[0]
 	}
-	return strings.Join(labels, ",")
+	if len(filter.Phases) == 1 {
+		set["status.phase"] = filter.Phases[0]
+	}
+	if len(set) == 0 {
+		return ""
+	}
+	return set.AsSelector().String()
+}
+
+// matchesFieldFilter reports whether the pod meets every field of the filter
+func matchesFieldFilter(pod apiv1.Pod, filter *domain.PodFieldFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if len(filter.NodeNames) > 0 && !slices.Contains(filter.NodeNames, pod.Spec.NodeName) {
+		return false
+	}
+	if len(filter.QOSClasses) > 0 && !slices.Contains(filter.QOSClasses, string(pod.Status.QOSClass)) {
+		return false
+	}
+	if len(filter.Phases) > 0 && !slices.Contains(filter.Phases, string(pod.Status.Phase)) {
+		return false
+	}
+	if filter.OwnerKind != "" || filter.OwnerName != "" {
+		return slices.ContainsFunc(pod.OwnerReferences, func(owner metav1.OwnerReference) bool {
+			return (filter.OwnerKind == "" || owner.Kind == filter.OwnerKind) && (filter.OwnerName == "" || owner.Name == filter.OwnerName)
+		})
+	}
+	return true
 }
 
 func buildContainers(pod apiv1.Pod, cmdRegex *regexp.Regexp) []domain.Container {
@@ -341,6 +518,42 @@ func buildContainers(pod apiv1.Pod, cmdRegex *regexp.Regexp) []domain.Container
 	return result
 }
 
+func convertPod(pod apiv1.Pod, containers []domain.Container) *domain.Pod {
+	return &domain.Pod{
+		Name:         pod.Name,
+		K8SNamespace: pod.Namespace,
+		Labels:       copyLabels(pod.Labels),
+		PodID:        string(pod.UID),
+		NodeID:       pod.Spec.NodeName,
+		Containers:   containers,
+	}
+}
+
+// podSchedulingChanged reports whether an update touches fields that decide which strategies select the pod
+// or where its intents have to be delivered, so other status updates do not trigger reconciliation.
+func podSchedulingChanged(oldPod, newPod apiv1.Pod) bool {
+	if oldPod.Spec.NodeName != newPod.Spec.NodeName || oldPod.Status.Phase != newPod.Status.Phase {
+		return true
+	}
+	if !slices.EqualFunc(oldPod.OwnerReferences, newPod.OwnerReferences, func(a, b metav1.OwnerReference) bool {
+		return a.UID == b.UID
+	}) {
+		return true
+	}
+	if !maps.Equal(oldPod.Labels, newPod.Labels) {
+		return true
+	}
+	if len(oldPod.Status.ContainerStatuses) != len(newPod.Status.ContainerStatuses) {
+		return true
+	}
+	for i, status := range newPod.Status.ContainerStatuses {
+		if oldPod.Status.ContainerStatuses[i].ContainerID != status.ContainerID {
+			return true
+		}
+	}
+	return false
+}
+
 func copyLabels(labels map[string]string) map[string]string {
 	if len(labels) == 0 {
 		return nil
@@ -353,16 +566,43 @@ func copyLabels(labels map[string]string) map[string]string {
 	return cloned
 }
 
-func firstContainerPort(pod apiv1.Pod) int {
+// containerPort returns the container port of the pod named name, or its first port when name is empty
+func containerPort(pod apiv1.Pod, name string) int {
 	for _, container := range pod.Spec.Containers {
-		if len(container.Ports) == 0 {
+		for _, port := range container.Ports {
+			if name == "" || port.Name == name {
+				return int(port.ContainerPort)
+			}
+		}
+	}
+	return 0
+}
+
+// endpointSlicePort returns the port of the endpoint slice named name, or its first port when name is empty
+func endpointSlicePort(endpointSlice discoveryv1.EndpointSlice, name string) int {
+	for _, port := range endpointSlice.Ports {
+		if port.Port == nil {
 			continue
 		}
-		return int(container.Ports[0].ContainerPort)
+		if name == "" || (port.Name != nil && *port.Name == name) {
+			return int(*port.Port)
+		}
 	}
 	return 0
 }
 
+func mapEndpointState(conditions discoveryv1.EndpointConditions) domain.NodeState {
+	switch {
+	case conditions.Terminating != nil && *conditions.Terminating:
+		return domain.NodeStateOffline
+	// a missing ready condition is to be taken as ready
+	case conditions.Ready == nil || *conditions.Ready:
+		return domain.NodeStateOnline
+	default:
+		return domain.NodeStateUnknown
+	}
+}
+
 func mapPodState(phase apiv1.PodPhase) domain.NodeState {
 	switch phase {
 	case apiv1.PodRunning:
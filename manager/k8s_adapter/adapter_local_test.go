@@ -253,10 +253,10 @@ func TestQueryDecisionMakerPodsWithLocalKubeconfig(t *testing.T) {
 
 		opt := &domain.QueryDecisionMakerPodsOptions{
 			K8SNamespace: []string{metav1.NamespaceAll},
-			DecisionMakerLabel: domain.LabelSelector{
+			DecisionMakerLabels: []domain.LabelSelector{{
 				Key:   "dm",
 				Value: "true",
-			},
+			}},
 		}
 
 		waitForLocal(t, func(ctx context.Context) (bool, error) {
@@ -275,7 +275,7 @@ func TestQueryDecisionMakerPodsWithLocalKubeconfig(t *testing.T) {
 				t.Fatalf("failed to update pod: %v", err)
 			}
 
-			opt.DecisionMakerLabel = domain.LabelSelector{Key: "dm", Value: "dm2"}
+			opt.DecisionMakerLabels = []domain.LabelSelector{{Key: "dm", Value: "dm2"}}
 			waitForLocal(t, func(ctx context.Context) (bool, error) {
 
 				results, err := adapter.QueryDecisionMakerPods(ctx, opt)
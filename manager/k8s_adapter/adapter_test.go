@@ -5,12 +5,16 @@ package k8sadapter
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/Gthulhu/api/manager/domain"
 	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/fake"
 )
@@ -141,6 +145,88 @@ func TestPodWatcherCacheLifecycle(t *testing.T) {
 	}
 }
 
+func TestSubscribePodEvents(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewSimpleClientset()
+	adapter := &Adapter{
+		client:      client,
+		podCache:    make(map[string]apiv1.Pod),
+		stopCh:      make(chan struct{}),
+		subscribers: make(map[chan domain.PodEvent]struct{}),
+	}
+	adapter.startPodWatcher()
+	t.Cleanup(adapter.StopPodWatcher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := adapter.SubscribePodEvents(ctx)
+	if err != nil {
+		t.Fatalf("subscribe pod events: %v", err)
+	}
+
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-1",
+			Namespace: "ns",
+			UID:       "uid-1",
+			Labels:    map[string]string{"app": "demo"},
+		},
+		Spec: apiv1.PodSpec{NodeName: "node-1"},
+	}
+	nextEvent := func() domain.PodEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no pod event received")
+		}
+		return domain.PodEvent{}
+	}
+
+	if _, err := client.CoreV1().Pods("ns").Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("failed to create pod: %v", err)
+	}
+	if event := nextEvent(); event.Type != domain.PodEventAdded || event.Pod.PodID != "uid-1" || event.Pod.NodeID != "node-1" {
+		t.Fatalf("unexpected add event %+v", event)
+	}
+
+	// status updates other than the phase are not relevant for strategies
+	pod.Status.PodIP = "10.0.0.1"
+	if _, err := client.CoreV1().Pods("ns").Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update pod status: %v", err)
+	}
+	pod.Labels["app"] = "demo2"
+	if _, err := client.CoreV1().Pods("ns").Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update pod labels: %v", err)
+	}
+	if event := nextEvent(); event.Type != domain.PodEventUpdated || event.Pod.Labels["app"] != "demo2" {
+		t.Fatalf("unexpected update event %+v", event)
+	}
+
+	// strategies may filter on the phase
+	pod.Status.Phase = apiv1.PodRunning
+	if _, err := client.CoreV1().Pods("ns").Update(context.Background(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("failed to update pod phase: %v", err)
+	}
+	if event := nextEvent(); event.Type != domain.PodEventUpdated {
+		t.Fatalf("unexpected update event %+v", event)
+	}
+
+	if err := client.CoreV1().Pods("ns").Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("failed to delete pod: %v", err)
+	}
+	if event := nextEvent(); event.Type != domain.PodEventDeleted || event.Pod.PodID != "uid-1" {
+		t.Fatalf("unexpected delete event %+v", event)
+	}
+
+	cancel()
+	waitFor(t, func() bool {
+		_, ok := <-events
+		return !ok
+	})
+}
+
 func waitFor(t *testing.T, cond func() bool) {
 	t.Helper()
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -256,10 +342,10 @@ func TestQueryDecisionMakerPodsUsesCache(t *testing.T) {
 	opt := &domain.QueryDecisionMakerPodsOptions{
 		K8SNamespace: []string{"ns1"},
 		NodeIDs:      []string{"node-1"},
-		DecisionMakerLabel: domain.LabelSelector{
+		DecisionMakerLabels: []domain.LabelSelector{{
 			Key:   "dm",
 			Value: "true",
-		},
+		}},
 	}
 
 	results, err := adapter.QueryDecisionMakerPods(context.Background(), opt)
@@ -285,3 +371,226 @@ func TestQueryDecisionMakerPodsUsesCache(t *testing.T) {
 		t.Fatalf("unexpected state %v", got.State)
 	}
 }
+
+func TestQueryDecisionMakerPodsNamedPort(t *testing.T) {
+	t.Parallel()
+
+	adapter := &Adapter{
+		client:   fake.NewSimpleClientset(),
+		podCache: make(map[string]apiv1.Pod),
+	}
+	adapter.cacheHasSynced.Store(true)
+
+	newPod := func(uid, nodeName string, containers ...apiv1.Container) apiv1.Pod {
+		return apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:       types.UID(uid),
+				Namespace: "kube-system",
+				Labels:    map[string]string{"app": "decisionmaker"},
+			},
+			Spec:   apiv1.PodSpec{NodeName: nodeName, Containers: containers},
+			Status: apiv1.PodStatus{Phase: apiv1.PodRunning, PodIP: "10.0.0.1"},
+		}
+	}
+	// the sidecar port comes first and must not be taken
+	adapter.setPodCache(newPod("uid-dm-1", "node-1",
+		apiv1.Container{Name: "sidecar", Ports: []apiv1.ContainerPort{{Name: "metrics", ContainerPort: 9090}}},
+		apiv1.Container{Name: "decisionmaker", Ports: []apiv1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+	))
+	adapter.setPodCache(newPod("uid-dm-2", "node-2",
+		apiv1.Container{Name: "sidecar", Ports: []apiv1.ContainerPort{{Name: "metrics", ContainerPort: 9090}}},
+	))
+
+	opt := &domain.QueryDecisionMakerPodsOptions{
+		K8SNamespace:        []string{"kube-system"},
+		DecisionMakerLabels: []domain.LabelSelector{{Key: "app", Value: "decisionmaker"}},
+		PortName:            "http",
+	}
+	results, err := adapter.QueryDecisionMakerPods(context.Background(), opt)
+	if err != nil {
+		t.Fatalf("QueryDecisionMakerPods returned error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 decision maker pod, got %d", len(results))
+	}
+	if results[0].NodeID != "node-1" || results[0].Port != 8080 {
+		t.Fatalf("unexpected decision maker %s", results[0])
+	}
+}
+
+func TestQueryDecisionMakerPodsThroughEndpointSlices(t *testing.T) {
+	t.Parallel()
+
+	portName, port := "http", int32(8080)
+	ready, notReady := true, false
+	node1, node2, node3 := "node-1", "node-2", "node-3"
+	client := fake.NewSimpleClientset(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "decisionmaker-abcde",
+			Namespace: "kube-system",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "decisionmaker"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, NodeName: &node1, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			{Addresses: []string{"10.0.0.2"}, NodeName: &node2, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			{Addresses: []string{"10.0.0.3"}, NodeName: &node3},
+		},
+	}, &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-abcde",
+			Namespace: "kube-system",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "other"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Name: &portName, Port: &port}},
+		Endpoints:   []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.9"}, NodeName: &node1}},
+	})
+	adapter := &Adapter{client: client}
+
+	opt := &domain.QueryDecisionMakerPodsOptions{
+		K8SNamespace: []string{"kube-system"},
+		NodeIDs:      []string{"node-1", "node-2"},
+		PortName:     "http",
+		ServiceName:  "decisionmaker",
+	}
+	results, err := adapter.QueryDecisionMakerPods(context.Background(), opt)
+	if err != nil {
+		t.Fatalf("QueryDecisionMakerPods returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 decision makers, got %d", len(results))
+	}
+	if got := results[0]; got.NodeID != "node-1" || got.Host != "10.0.0.1" || got.Port != 8080 || got.State != domain.NodeStateOnline {
+		t.Fatalf("unexpected decision maker %s state %v", got, got.State)
+	}
+	if got := results[1]; got.NodeID != "node-2" || got.State != domain.NodeStateUnknown {
+		t.Fatalf("unexpected decision maker %s state %v", got, got.State)
+	}
+
+	opt.PortName = "grpc"
+	results, err = adapter.QueryDecisionMakerPods(context.Background(), opt)
+	if err != nil {
+		t.Fatalf("QueryDecisionMakerPods returned error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no decision makers without the named port, got %d", len(results))
+	}
+}
+
+func TestDecisionMakerPodString(t *testing.T) {
+	t.Parallel()
+
+	dm := &domain.DecisionMakerPod{NodeID: "node-1", Host: "10.0.0.1", Port: 8080}
+	if got := dm.String(); got != "(node-1)10.0.0.1:8080" {
+		t.Fatalf("unexpected string %q", got)
+	}
+}
+
+func TestQueryPodsMatchExpressionsAndFieldFilters(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(uid string, podLabels map[string]string, nodeName string, phase apiv1.PodPhase, qosClass apiv1.PodQOSClass, ownerKind, ownerName string) *apiv1.Pod {
+		return &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:             types.UID(uid),
+				Name:            uid,
+				Namespace:       "ns1",
+				Labels:          podLabels,
+				OwnerReferences: []metav1.OwnerReference{{Kind: ownerKind, Name: ownerName}},
+			},
+			Spec:   apiv1.PodSpec{NodeName: nodeName},
+			Status: apiv1.PodStatus{Phase: phase, QOSClass: qosClass},
+		}
+	}
+	pods := []*apiv1.Pod{
+		newPod("web-1", map[string]string{"app": "web", "tier": "frontend"}, "node-1", apiv1.PodRunning, apiv1.PodQOSBurstable, "ReplicaSet", "web-abc"),
+		newPod("web-2", map[string]string{"app": "web", "tier": "backend"}, "node-2", apiv1.PodRunning, apiv1.PodQOSBestEffort, "ReplicaSet", "web-def"),
+		newPod("web-3", map[string]string{"app": "web", "tier": "frontend", "canary": "true"}, "node-1", apiv1.PodRunning, apiv1.PodQOSBurstable, "ReplicaSet", "web-abc"),
+		newPod("db-1", map[string]string{"app": "db"}, "node-1", apiv1.PodPending, apiv1.PodQOSGuaranteed, "StatefulSet", "db"),
+	}
+
+	testCases := []struct {
+		name     string
+		opt      *domain.QueryPodsOptions
+		expected []string
+	}{
+		{
+			name: "match expressions",
+			opt: &domain.QueryPodsOptions{
+				MatchExpressions: []domain.MatchExpression{
+					{Key: "app", Operator: domain.MatchOperatorIn, Values: []string{"web", "cache"}},
+					{Key: "tier", Operator: domain.MatchOperatorNotIn, Values: []string{"backend"}},
+					{Key: "canary", Operator: domain.MatchOperatorDoesNotExist},
+				},
+			},
+			expected: []string{"web-1"},
+		},
+		{
+			name: "label selector with qos class",
+			opt: &domain.QueryPodsOptions{
+				LabelSelectors:   []domain.LabelSelector{{Key: "app", Value: "web"}},
+				MatchExpressions: []domain.MatchExpression{{Key: "tier", Operator: domain.MatchOperatorExists}},
+				FieldFilter:      &domain.PodFieldFilter{QOSClasses: []string{"Burstable"}},
+			},
+			expected: []string{"web-1", "web-3"},
+		},
+		{
+			name: "node name and phase",
+			opt: &domain.QueryPodsOptions{
+				FieldFilter: &domain.PodFieldFilter{NodeNames: []string{"node-1"}, Phases: []string{"Running"}},
+			},
+			expected: []string{"web-1", "web-3"},
+		},
+		{
+			name: "owner",
+			opt: &domain.QueryPodsOptions{
+				FieldFilter: &domain.PodFieldFilter{OwnerKind: "ReplicaSet", OwnerName: "web-def"},
+			},
+			expected: []string{"web-2"},
+		},
+	}
+
+	for _, cached := range []bool{true, false} {
+		client := fake.NewSimpleClientset()
+		adapter := &Adapter{client: client, podCache: make(map[string]apiv1.Pod)}
+		for _, pod := range pods {
+			if cached {
+				adapter.setPodCache(*pod)
+				continue
+			}
+			if _, err := client.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("failed to create pod: %v", err)
+			}
+		}
+		adapter.cacheHasSynced.Store(cached)
+
+		for _, tc := range testCases {
+			results, err := adapter.QueryPods(context.Background(), tc.opt)
+			if err != nil {
+				t.Fatalf("%s (cached:%t): QueryPods returned error: %v", tc.name, cached, err)
+			}
+			got := make([]string, 0, len(results))
+			for _, pod := range results {
+				got = append(got, pod.PodID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tc.expected) {
+				t.Fatalf("%s (cached:%t): expected pods %v, got %v", tc.name, cached, tc.expected, got)
+			}
+		}
+	}
+
+	adapter := &Adapter{client: fake.NewSimpleClientset(), podCache: make(map[string]apiv1.Pod)}
+	for _, expression := range []domain.MatchExpression{
+		{Key: "app", Operator: "Like", Values: []string{"web"}},
+		{Key: "app", Operator: domain.MatchOperatorIn},
+		{Key: "app", Operator: domain.MatchOperatorExists, Values: []string{"web"}},
+	} {
+		_, err := adapter.QueryPods(context.Background(), &domain.QueryPodsOptions{MatchExpressions: []domain.MatchExpression{expression}})
+		if !errors.Is(err, domain.ErrInvalidSelector) {
+			t.Fatalf("expected an invalid selector error for %+v, got %v", expression, err)
+		}
+	}
+}
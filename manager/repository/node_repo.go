@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func (r *repo) UpsertNodes(ctx context.Context, nodes []*domain.Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	models := make([]mongo.WriteModel, 0, len(nodes))
+	for _, node := range nodes {
+		if node.NodeID == "" {
+			return errors.New("node id is required")
+		}
+		if node.CreatedTime == 0 {
+			node.CreatedTime = now
+		}
+		node.UpdatedTime = now
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"nodeID": node.NodeID}).
+			SetReplacement(node).
+			SetUpsert(true))
+	}
+	_, err := r.db.Collection(nodeCollection).BulkWrite(ctx, models)
+	if err != nil {
+		return fmt.Errorf("upsert nodes, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) QueryNodes(ctx context.Context, opt *domain.QueryNodeOptions) error {
+	if opt == nil {
+		return errors.New("nil query options")
+	}
+	filter := bson.M{}
+	if len(opt.NodeIDs) > 0 {
+		filter["nodeID"] = bson.M{"$in": opt.NodeIDs}
+	}
+	cursor, err := r.db.Collection(nodeCollection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "nodeID", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var node domain.Node
+		if err := cursor.Decode(&node); err != nil {
+			return err
+		}
+		opt.Result = append(opt.Result, &node)
+	}
+	return cursor.Err()
+}
+
+// CountIntentsByNode returns the number of intents per state targeting each of the nodes, all nodes when nodeIDs is empty
+func (r *repo) CountIntentsByNode(ctx context.Context, nodeIDs []string) (map[string]map[domain.IntentState]int, error) {
+	match := bson.M{}
+	if len(nodeIDs) > 0 {
+		match["nodeID"] = bson.M{"$in": nodeIDs}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"nodeID": "$nodeID", "state": "$state"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.db.Collection(scheduleIntentCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("count intents by node, err: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	counts := make(map[string]map[domain.IntentState]int)
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				NodeID string             `bson:"nodeID"`
+				State  domain.IntentState `bson:"state"`
+			} `bson:"_id"`
+			Count int `bson:"count"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return nil, err
+		}
+		if counts[row.ID.NodeID] == nil {
+			counts[row.ID.NodeID] = make(map[domain.IntentState]int)
+		}
+		counts[row.ID.NodeID][row.ID.State] = row.Count
+	}
+	return counts, cursor.Err()
+}
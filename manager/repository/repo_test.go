@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
@@ -147,3 +148,65 @@ func (suite *RepositoryTestSuite) TestCreateRoleAndPermission() {
 	suite.Len(permOpts.Result, 1, "expect one permission")
 	suite.Equal(perm.Description, permOpts.Result[0].Description, "permission description should match")
 }
+
+func (suite *RepositoryTestSuite) TestClaimIntents() {
+	now := time.Now().UnixMilli()
+	initialized := &domain.ScheduleIntent{PodID: "pod-a", State: domain.IntentStateInitialized}
+	retracting := &domain.ScheduleIntent{PodID: "pod-b", State: domain.IntentStateRetracting}
+	backingOff := &domain.ScheduleIntent{PodID: "pod-c", State: domain.IntentStateInitialized, NextAttemptTime: now + 60000}
+	sent := &domain.ScheduleIntent{PodID: "pod-d", State: domain.IntentStateSent}
+	err := suite.repo.InsertIntents(suite.ctx, []*domain.ScheduleIntent{initialized, retracting, backingOff, sent})
+	suite.Require().NoError(err, "insert intents")
+	ids := []bson.ObjectID{initialized.ID, retracting.ID, backingOff.ID, sent.ID}
+
+	claimed, err := suite.repo.ClaimIntents(suite.ctx, ids, now, now+30000)
+	suite.Require().NoError(err, "claim intents")
+	suite.Require().Len(claimed, 2, "only due pending intents should be claimed")
+	suite.ElementsMatch([]bson.ObjectID{initialized.ID, retracting.ID}, []bson.ObjectID{claimed[0].ID, claimed[1].ID}, "claimed intents mismatch")
+	suite.Equal(now+30000, claimed[0].ClaimedUntil, "claimed intent should be leased")
+
+	claimed, err = suite.repo.ClaimIntents(suite.ctx, ids, now, now+30000)
+	suite.Require().NoError(err, "claim intents again")
+	suite.Empty(claimed, "leased intents should not be claimed twice")
+}
+
+func (suite *RepositoryTestSuite) TestResolveIntentsSkipsClaimedIntents() {
+	now := time.Now().UnixMilli()
+	winnerID, loserID := bson.NewObjectID(), bson.NewObjectID()
+	loser := &domain.ScheduleIntent{PodID: "pod-a", StrategyID: loserID, State: domain.IntentStateInitialized}
+	retracting := &domain.ScheduleIntent{PodID: "pod-a", StrategyID: winnerID, State: domain.IntentStateRetracting}
+	err := suite.repo.InsertIntents(suite.ctx, []*domain.ScheduleIntent{loser, retracting})
+	suite.Require().NoError(err, "insert intents")
+
+	// a delivery claims the intents between the read and the resolution
+	claimed, err := suite.repo.ClaimIntents(suite.ctx, []bson.ObjectID{loser.ID, retracting.ID}, now, now+30000)
+	suite.Require().NoError(err, "claim intents")
+	suite.Require().Len(claimed, 2, "expect both intents claimed")
+
+	resolutions := []*domain.IntentResolution{
+		{IntentID: loser.ID, FromState: domain.IntentStateInitialized, State: domain.IntentStateOverridden, WinningStrategyID: winnerID},
+		{IntentID: retracting.ID, FromState: domain.IntentStateRetracting, Superseded: true},
+	}
+	applied, err := suite.repo.ResolveIntents(suite.ctx, resolutions, now)
+	suite.Require().NoError(err, "resolve claimed intents")
+	suite.Empty(applied, "claimed intents should not be resolved")
+
+	// the delivery completes, the resolution read before no longer applies
+	err = suite.repo.MarkIntentsDelivered(suite.ctx, []bson.ObjectID{loser.ID})
+	suite.Require().NoError(err, "mark intents delivered")
+	applied, err = suite.repo.ResolveIntents(suite.ctx, resolutions[:1], now)
+	suite.Require().NoError(err, "resolve delivered intent")
+	suite.Empty(applied, "resolution of a stale state should not apply")
+
+	opts := &domain.QueryIntentOptions{IDs: []bson.ObjectID{loser.ID}}
+	err = suite.repo.QueryIntents(suite.ctx, opts)
+	suite.Require().NoError(err, "query intents")
+	suite.Require().Len(opts.Result, 1, "expect one intent")
+	suite.Equal(domain.IntentStateSent, opts.Result[0].State, "delivered state should be kept")
+	suite.Zero(opts.Result[0].ClaimedUntil, "delivered intent should be released")
+
+	resolutions[0].FromState = domain.IntentStateSent
+	applied, err = suite.repo.ResolveIntents(suite.ctx, resolutions[:1], now)
+	suite.Require().NoError(err, "resolve intent")
+	suite.Equal([]bson.ObjectID{loser.ID}, applied, "resolution should apply to the current state")
+}
@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Gthulhu/api/manager/domain"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func (r *repo) InsertStrategyAndIntents(ctx context.Context, strategy *domain.ScheduleStrategy, intents []*domain.ScheduleIntent) error {
@@ -48,11 +51,96 @@ func (r *repo) InsertStrategyAndIntents(ctx context.Context, strategy *domain.Sc
 	return nil
 }
 
+func (r *repo) UpdateStrategy(ctx context.Context, strategy *domain.ScheduleStrategy) error {
+	if strategy == nil {
+		return errors.New("nil strategy")
+	}
+	if strategy.ID.IsZero() {
+		return errors.New("strategy id is required")
+	}
+
+	strategy.UpdatedTime = time.Now().UnixMilli()
+	res, err := r.db.Collection(scheduleStrategyCollection).ReplaceOne(ctx, bson.M{"_id": strategy.ID}, strategy)
+	if err != nil {
+		return fmt.Errorf("update strategy, err: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) DeleteStrategy(ctx context.Context, strategyID bson.ObjectID) error {
+	if strategyID.IsZero() {
+		return errors.New("strategy id is required")
+	}
+	res, err := r.db.Collection(scheduleStrategyCollection).DeleteOne(ctx, bson.M{"_id": strategyID})
+	if err != nil {
+		return fmt.Errorf("delete strategy, err: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *repo) InsertIntents(ctx context.Context, intents []*domain.ScheduleIntent) error {
+	if len(intents) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	for _, intent := range intents {
+		if intent.ID.IsZero() {
+			intent.ID = bson.NewObjectID()
+		}
+		if intent.CreatedTime == 0 {
+			intent.CreatedTime = now
+		}
+		intent.UpdatedTime = now
+	}
+	_, err := r.db.Collection(scheduleIntentCollection).InsertMany(ctx, intents)
+	if err != nil {
+		return fmt.Errorf("insert intents, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) UpdateIntents(ctx context.Context, intents []*domain.ScheduleIntent) error {
+	now := time.Now().UnixMilli()
+	for _, intent := range intents {
+		if intent.ID.IsZero() {
+			return errors.New("intent id is required")
+		}
+		intent.UpdatedTime = now
+		res, err := r.db.Collection(scheduleIntentCollection).ReplaceOne(ctx, bson.M{"_id": intent.ID}, intent)
+		if err != nil {
+			return fmt.Errorf("update intent %s, err: %w", intent.ID.Hex(), err)
+		}
+		if res.MatchedCount == 0 {
+			return domain.ErrNotFound
+		}
+	}
+	return nil
+}
+
+func (r *repo) DeleteIntents(ctx context.Context, intentIDs []bson.ObjectID) error {
+	if len(intentIDs) == 0 {
+		return nil
+	}
+	_, err := r.db.Collection(scheduleIntentCollection).DeleteMany(ctx, bson.M{
+		"_id": bson.M{"$in": intentIDs},
+	})
+	if err != nil {
+		return fmt.Errorf("delete intents, err: %w", err)
+	}
+	return nil
+}
+
 func (r *repo) BatchUpdateIntentsState(ctx context.Context, intentIDs []bson.ObjectID, newState domain.IntentState) error {
 	update := bson.M{
 		"$set": bson.M{
-			"state":      newState,
-			"updateTime": time.Now().UnixMilli(),
+			"state":       newState,
+			"updatedTime": time.Now().UnixMilli(),
 		},
 	}
 	_, err := r.db.Collection(scheduleIntentCollection).UpdateMany(ctx, bson.M{
@@ -64,6 +152,165 @@ func (r *repo) BatchUpdateIntentsState(ctx context.Context, intentIDs []bson.Obj
 	return nil
 }
 
+func (r *repo) MarkIntentsDelivered(ctx context.Context, intentIDs []bson.ObjectID) error {
+	if len(intentIDs) == 0 {
+		return nil
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"state":       domain.IntentStateSent,
+			"updatedTime": time.Now().UnixMilli(),
+		},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"lastError": "", "nextAttemptTime": "", "claimedUntil": ""},
+	}
+	_, err := r.db.Collection(scheduleIntentCollection).UpdateMany(ctx, bson.M{
+		"_id":   bson.M{"$in": intentIDs},
+		"state": domain.IntentStateInitialized,
+	}, update)
+	if err != nil {
+		return fmt.Errorf("mark intents delivered, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) BatchUpdateIntentsResult(ctx context.Context, results []*domain.ScheduleIntentResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	now := time.Now().UnixMilli()
+	models := make([]mongo.WriteModel, 0, len(results))
+	for _, result := range results {
+		if result == nil || result.IntentID.IsZero() {
+			continue
+		}
+		update := bson.M{
+			"$set": bson.M{
+				"state":       result.State,
+				"pidCount":    result.PIDCount,
+				"reason":      result.Reason,
+				"updatedTime": now,
+			},
+		}
+		// only delivered intents take the result, a concurrent change or retraction wins over it
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": result.IntentID, "state": domain.IntentStateSent}).
+			SetUpdate(update))
+	}
+	if len(models) == 0 {
+		return nil
+	}
+	_, err := r.db.Collection(scheduleIntentCollection).BulkWrite(ctx, models)
+	if err != nil {
+		return fmt.Errorf("update intents result, err: %w", err)
+	}
+	return nil
+}
+
+func (r *repo) MarkIntentsDeliveryFailed(ctx context.Context, intentIDs []bson.ObjectID, lastError string, nextAttemptTime int64) error {
+	if len(intentIDs) == 0 {
+		return nil
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"lastError":       lastError,
+			"nextAttemptTime": nextAttemptTime,
+			"updatedTime":     time.Now().UnixMilli(),
+		},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"claimedUntil": ""},
+	}
+	_, err := r.db.Collection(scheduleIntentCollection).UpdateMany(ctx, bson.M{
+		"_id": bson.M{"$in": intentIDs},
+	}, update)
+	if err != nil {
+		return fmt.Errorf("mark intents delivery failed, err: %w", err)
+	}
+	return nil
+}
+
+// unclaimedFilter matches intents no delivery holds at the given unix time in milliseconds
+func unclaimedFilter(now int64) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"claimedUntil": bson.M{"$exists": false}},
+		bson.M{"claimedUntil": bson.M{"$lte": now}},
+	}}
+}
+
+// ResolveIntents applies every resolution with a single targeted update, filtered on the state it was decided on
+// and skipped while a delivery holds the intent. A full replace would reset the outcome of a concurrent delivery.
+func (r *repo) ResolveIntents(ctx context.Context, resolutions []*domain.IntentResolution, now int64) ([]bson.ObjectID, error) {
+	applied := make([]bson.ObjectID, 0, len(resolutions))
+	for _, resolution := range resolutions {
+		filter := bson.M{
+			"_id":   resolution.IntentID,
+			"state": resolution.FromState,
+			"$and":  bson.A{unclaimedFilter(now)},
+		}
+		if resolution.Superseded {
+			res, err := r.db.Collection(scheduleIntentCollection).DeleteOne(ctx, filter)
+			if err != nil {
+				return nil, fmt.Errorf("delete superseded intent %s, err: %w", resolution.IntentID.Hex(), err)
+			}
+			if res.DeletedCount > 0 {
+				applied = append(applied, resolution.IntentID)
+			}
+			continue
+		}
+		update := bson.M{
+			"$set": bson.M{
+				"state":             resolution.State,
+				"winningStrategyID": resolution.WinningStrategyID,
+				"updatedTime":       now,
+			},
+		}
+		if resolution.FromState == domain.IntentStateOverridden && resolution.State == domain.IntentStateInitialized {
+			// promoted intents are delivered right away
+			update["$unset"] = bson.M{"nextAttemptTime": ""}
+		}
+		res, err := r.db.Collection(scheduleIntentCollection).UpdateOne(ctx, filter, update)
+		if err != nil {
+			return nil, fmt.Errorf("resolve intent %s, err: %w", resolution.IntentID.Hex(), err)
+		}
+		if res.MatchedCount > 0 {
+			applied = append(applied, resolution.IntentID)
+		}
+	}
+	return applied, nil
+}
+
+// ClaimIntents holds every due initialized or retracting intent for a delivery until leaseUntil, so that no other
+// delivery or resolution touches it meanwhile. Each intent is claimed atomically, those already claimed or no
+// longer pending are left out. The claimed intents are returned as stored.
+func (r *repo) ClaimIntents(ctx context.Context, intentIDs []bson.ObjectID, now, leaseUntil int64) ([]*domain.ScheduleIntent, error) {
+	claimed := make([]*domain.ScheduleIntent, 0, len(intentIDs))
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	for _, intentID := range intentIDs {
+		filter := bson.M{
+			"_id":   intentID,
+			"state": bson.M{"$in": []domain.IntentState{domain.IntentStateInitialized, domain.IntentStateRetracting}},
+			"$and": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"nextAttemptTime": bson.M{"$exists": false}},
+					bson.M{"nextAttemptTime": bson.M{"$lte": now}},
+				}},
+				unclaimedFilter(now),
+			},
+		}
+		update := bson.M{"$set": bson.M{"claimedUntil": leaseUntil}}
+		var intent domain.ScheduleIntent
+		err := r.db.Collection(scheduleIntentCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&intent)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("claim intent %s, err: %w", intentID.Hex(), err)
+		}
+		claimed = append(claimed, &intent)
+	}
+	return claimed, nil
+}
+
 func (r *repo) QueryStrategies(ctx context.Context, opt *domain.QueryStrategyOptions) error {
 	if opt == nil {
 		return errors.New("nil query options")
@@ -75,6 +322,9 @@ func (r *repo) QueryStrategies(ctx context.Context, opt *domain.QueryStrategyOpt
 	if len(opt.K8SNamespaces) > 0 {
 		filter["k8sNamespace"] = bson.M{"$in": opt.K8SNamespaces}
 	}
+	if len(opt.CreatorIDs) > 0 {
+		filter["creatorID"] = bson.M{"$in": opt.CreatorIDs}
+	}
 	cursor, err := r.db.Collection(scheduleStrategyCollection).Find(ctx, filter)
 	if err != nil {
 		return err
@@ -105,12 +355,21 @@ func (r *repo) QueryIntents(ctx context.Context, opt *domain.QueryIntentOptions)
 	if len(opt.StrategyIDs) > 0 {
 		filter["strategyID"] = bson.M{"$in": opt.StrategyIDs}
 	}
+	if len(opt.CreatorIDs) > 0 {
+		filter["creatorID"] = bson.M{"$in": opt.CreatorIDs}
+	}
 	if len(opt.PodIDs) > 0 {
 		filter["podID"] = bson.M{"$in": opt.PodIDs}
 	}
 	if len(opt.States) > 0 {
 		filter["state"] = bson.M{"$in": opt.States}
 	}
+	if opt.DueBefore > 0 {
+		filter["$or"] = bson.A{
+			bson.M{"nextAttemptTime": bson.M{"$exists": false}},
+			bson.M{"nextAttemptTime": bson.M{"$lte": opt.DueBefore}},
+		}
+	}
 	cursor, err := r.db.Collection(scheduleIntentCollection).Find(ctx, filter)
 	if err != nil {
 		return err
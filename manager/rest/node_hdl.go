@@ -0,0 +1,199 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/Gthulhu/api/manager/domain"
+)
+
+type ListNodesResponse struct {
+	Nodes []*Node `json:"nodes"`
+}
+
+type Node struct {
+	NodeID          string         `json:"nodeID"`
+	Host            string         `json:"host"`
+	Port            int            `json:"port"`
+	State           string         `json:"state"`
+	Health          string         `json:"health,omitempty"`
+	DMVersion       string         `json:"dmVersion,omitempty"`
+	LastContactTime int64          `json:"lastContactTime,omitempty"`
+	LastProbeTime   int64          `json:"lastProbeTime,omitempty"`
+	LastError       string         `json:"lastError,omitempty"`
+	IntentCount     int            `json:"intentCount"`
+	IntentCounts    map[string]int `json:"intentCounts"` // keyed by intent state
+}
+
+// ListNodes godoc
+// @Summary List nodes
+// @Description List the nodes running a decision maker with their online/offline state, decision maker version, last contact time and intent counts.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListNodesResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/nodes [get]
+func (h *Handler) ListNodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	queryOpt := &domain.QueryNodeOptions{}
+	err := h.Svc.ListNodes(ctx, queryOpt)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListNodesResponse{
+		Nodes: make([]*Node, len(queryOpt.Result)),
+	}
+	for i, node := range queryOpt.Result {
+		resp.Nodes[i] = h.convertDomainNodeToResponseNode(node)
+	}
+	response := NewSuccessResponse[ListNodesResponse](&resp)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// GetNode godoc
+// @Summary Get node
+// @Description Get a node running a decision maker by its Kubernetes node name.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Node name"
+// @Success 200 {object} SuccessResponse[Node]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/nodes/{id} [get]
+func (h *Handler) GetNode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	node, err := h.Svc.GetNode(ctx, r.PathValue("id"))
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+	response := NewSuccessResponse[Node](h.convertDomainNodeToResponseNode(node))
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+func (h *Handler) convertDomainNodeToResponseNode(node *domain.Node) *Node {
+	resp := &Node{
+		NodeID:          node.NodeID,
+		Host:            node.Host,
+		Port:            node.Port,
+		State:           node.State.String(),
+		Health:          node.Health,
+		DMVersion:       node.DMVersion,
+		LastContactTime: node.LastContactTime,
+		LastProbeTime:   node.LastProbeTime,
+		LastError:       node.LastError,
+		IntentCounts:    make(map[string]int, len(node.IntentCounts)),
+	}
+	for state, count := range node.IntentCounts {
+		resp.IntentCounts[state.String()] += count
+		resp.IntentCount += count
+	}
+	return resp
+}
+
+type ListNodeMetricsResponse struct {
+	ScrapedAt          int64          `json:"scrapedAt"`
+	NodesReporting     int            `json:"nodesReporting"`
+	NrQueued           float64        `json:"nrQueued"`
+	NrScheduled        float64        `json:"nrScheduled"`
+	NrRunning          float64        `json:"nrRunning"`
+	NrFailedDispatches uint64         `json:"nrFailedDispatches"`
+	NrSchedCongested   uint64         `json:"nrSchedCongested"`
+	DispatchRate       float64        `json:"dispatchRate"`
+	Nodes              []*NodeMetrics `json:"nodes"`
+}
+
+type NodeMetrics struct {
+	NodeID    string            `json:"nodeID"`
+	Host      string            `json:"host"`
+	ScrapedAt int64             `json:"scrapedAt"`
+	Error     string            `json:"error,omitempty"`
+	Sample    *NodeMetricSample `json:"sample,omitempty"`
+}
+
+type NodeMetricSample struct {
+	Timestamp           int64   `json:"timestamp"`
+	NrQueued            float64 `json:"nrQueued"`
+	NrScheduled         float64 `json:"nrScheduled"`
+	NrRunning           float64 `json:"nrRunning"`
+	NrOnlineCPUs        float64 `json:"nrOnlineCPUs"`
+	NrUserDispatches    uint64  `json:"nrUserDispatches"`
+	NrKernelDispatches  uint64  `json:"nrKernelDispatches"`
+	NrCancelDispatches  uint64  `json:"nrCancelDispatches"`
+	NrBounceDispatches  uint64  `json:"nrBounceDispatches"`
+	NrFailedDispatches  uint64  `json:"nrFailedDispatches"`
+	NrSchedCongested    uint64  `json:"nrSchedCongested"`
+	DispatchRate        float64 `json:"dispatchRate"`
+	FailedDispatchRatio float64 `json:"failedDispatchRatio"`
+}
+
+// ListNodeMetrics godoc
+// @Summary List node scheduler metrics
+// @Description List the scheduler metrics last scraped from the decision maker of every node, with their cluster totals. Totals only include nodes whose last scrape succeeded.
+// @Tags Nodes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SuccessResponse[ListNodeMetricsResponse]
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/nodes/metrics [get]
+func (h *Handler) ListNodeMetrics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	cluster, err := h.Svc.ListNodeMetrics(ctx)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := ListNodeMetricsResponse{
+		ScrapedAt:          cluster.ScrapedAt,
+		NodesReporting:     cluster.NodesReporting,
+		NrQueued:           cluster.NrQueued,
+		NrScheduled:        cluster.NrScheduled,
+		NrRunning:          cluster.NrRunning,
+		NrFailedDispatches: cluster.NrFailedDispatches,
+		NrSchedCongested:   cluster.NrSchedCongested,
+		DispatchRate:       cluster.DispatchRate,
+		Nodes:              make([]*NodeMetrics, len(cluster.Nodes)),
+	}
+	for i, nodeMetrics := range cluster.Nodes {
+		resp.Nodes[i] = h.convertDomainNodeMetricsToResponse(nodeMetrics)
+	}
+	response := NewSuccessResponse[ListNodeMetricsResponse](&resp)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+func (h *Handler) convertDomainNodeMetricsToResponse(nodeMetrics *domain.NodeMetrics) *NodeMetrics {
+	resp := &NodeMetrics{
+		NodeID:    nodeMetrics.NodeID,
+		Host:      nodeMetrics.Host,
+		ScrapedAt: nodeMetrics.ScrapedAt,
+		Error:     nodeMetrics.Error,
+	}
+	if sample := nodeMetrics.Sample; sample != nil {
+		resp.Sample = &NodeMetricSample{
+			Timestamp:           sample.Timestamp,
+			NrQueued:            sample.NrQueued,
+			NrScheduled:         sample.NrScheduled,
+			NrRunning:           sample.NrRunning,
+			NrOnlineCPUs:        sample.NrOnlineCPUs,
+			NrUserDispatches:    sample.NrUserDispatches,
+			NrKernelDispatches:  sample.NrKernelDispatches,
+			NrCancelDispatches:  sample.NrCancelDispatches,
+			NrBounceDispatches:  sample.NrBounceDispatches,
+			NrFailedDispatches:  sample.NrFailedDispatches,
+			NrSchedCongested:    sample.NrSchedCongested,
+			DispatchRate:        sample.DispatchRate,
+			FailedDispatchRatio: sample.FailedDispatchRatio,
+		}
+	}
+	return resp
+}
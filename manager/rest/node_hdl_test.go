@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/rest"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *HandlerTestSuite) TestIntegrationNodeMetrics() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080, State: domain.NodeStateOnline}
+	dmPod2 := &domain.DecisionMakerPod{Host: "dm-host-2", NodeID: "node-2", Port: 8080, State: domain.NodeStateOnline}
+	sample1 := &domain.NodeMetricSample{Timestamp: 1000, NrQueued: 3, NrFailedDispatches: 2, NrSchedCongested: 1, DispatchRate: 100}
+	sample2 := &domain.NodeMetricSample{Timestamp: 2000, NrQueued: 5, NrFailedDispatches: 4, NrSchedCongested: 6, DispatchRate: 50}
+
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1, dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().GetMetricsHistory(mock.Anything, dmPod1, mock.Anything, mock.Anything).Return([]*domain.NodeMetricSample{sample1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().GetMetricsHistory(mock.Anything, dmPod2, mock.Anything, mock.Anything).Return([]*domain.NodeMetricSample{sample2}, nil).Once()
+	suite.Require().NoError(suite.Handler.Svc.ScrapeNodeMetrics(suite.Ctx))
+
+	metrics := suite.listNodeMetrics(adminToken, http.StatusOK)
+	suite.Require().Len(metrics.Nodes, 2, "Expected two nodes")
+	suite.Require().Equal(2, metrics.NodesReporting, "NodesReporting mismatch")
+	suite.Require().Equal(8.0, metrics.NrQueued, "NrQueued mismatch")
+	suite.Require().Equal(uint64(6), metrics.NrFailedDispatches, "NrFailedDispatches mismatch")
+	suite.Require().Equal(uint64(7), metrics.NrSchedCongested, "NrSchedCongested mismatch")
+	suite.Require().Equal(150.0, metrics.DispatchRate, "DispatchRate mismatch")
+
+	// node-2 becomes unreachable, it keeps its last sample but leaves the totals
+	// node-1 is rolling out its decision maker, the terminating pod is not scraped
+	terminatingDMPod1 := &domain.DecisionMakerPod{Host: "dm-host-1-old", NodeID: "node-1", Port: 8080, State: domain.NodeStateOffline}
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{terminatingDMPod1, dmPod1, dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().GetMetricsHistory(mock.Anything, dmPod1, mock.Anything, mock.Anything).Return([]*domain.NodeMetricSample{sample1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().GetMetricsHistory(mock.Anything, dmPod2, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	suite.Require().NoError(suite.Handler.Svc.ScrapeNodeMetrics(suite.Ctx))
+
+	metrics = suite.listNodeMetrics(adminToken, http.StatusOK)
+	suite.Require().Len(metrics.Nodes, 2, "Expected two nodes")
+	suite.Require().Equal(1, metrics.NodesReporting, "NodesReporting mismatch")
+	suite.Require().Equal(3.0, metrics.NrQueued, "NrQueued mismatch")
+	suite.Require().Equal("node-2", metrics.Nodes[1].NodeID, "Nodes should be sorted by ID")
+	suite.Require().Contains(metrics.Nodes[1].Error, "connection refused", "Error mismatch")
+	suite.Require().NotNil(metrics.Nodes[1].Sample, "Last sample should be kept")
+	suite.Require().Equal(int64(2000), metrics.Nodes[1].Sample.Timestamp, "Sample mismatch")
+
+	suite.listNodeMetrics("", http.StatusUnauthorized)
+}
+
+func (suite *HandlerTestSuite) TestIntegrationNodeRegistry() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "cache"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}
+	podA := &domain.Pod{PodID: "pod-cache-a", Labels: map[string]string{"app": "cache"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-cache-b", Labels: map[string]string{"app": "cache"}, NodeID: "node-1"}
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080, State: domain.NodeStateOnline}
+	dmPod2 := &domain.DecisionMakerPod{Host: "dm-host-2", NodeID: "node-2", Port: 8080, State: domain.NodeStateOnline}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA, podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1, dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().ProbeDecisionMaker(mock.Anything, dmPod1).Return(&domain.DecisionMakerStatus{Health: "healthy", Version: "1.0.0"}, nil).Once()
+	suite.MockDMAdapter.EXPECT().ProbeDecisionMaker(mock.Anything, dmPod2).Return(nil, errors.New("connection refused")).Once()
+	suite.Require().NoError(suite.Handler.Svc.SyncNodeRegistry(suite.Ctx))
+
+	nodes := suite.listNodes(adminToken, http.StatusOK)
+	suite.Require().Len(nodes.Nodes, 2, "Expected two nodes")
+	suite.Require().Equal("node-1", nodes.Nodes[0].NodeID, "Nodes should be sorted by ID")
+	suite.Require().Equal("online", nodes.Nodes[0].State, "State mismatch")
+	suite.Require().Equal("1.0.0", nodes.Nodes[0].DMVersion, "DMVersion mismatch")
+	suite.Require().NotZero(nodes.Nodes[0].LastContactTime, "LastContactTime should be set")
+	suite.Require().Equal(2, nodes.Nodes[0].IntentCount, "IntentCount mismatch")
+	suite.Require().Equal(2, nodes.Nodes[0].IntentCounts["sent"], "IntentCounts mismatch")
+	suite.Require().Equal("offline", nodes.Nodes[1].State, "State mismatch")
+	suite.Require().Contains(nodes.Nodes[1].LastError, "connection refused", "LastError mismatch")
+	suite.Require().Zero(nodes.Nodes[1].IntentCount, "IntentCount mismatch")
+
+	// the decision maker of node-1 is gone, the node is kept as offline with its last contact
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().ProbeDecisionMaker(mock.Anything, dmPod2).Return(&domain.DecisionMakerStatus{Health: "degraded", Version: "1.0.0"}, nil).Once()
+	suite.Require().NoError(suite.Handler.Svc.SyncNodeRegistry(suite.Ctx))
+
+	node := suite.getNode(adminToken, "node-1", http.StatusOK)
+	suite.Require().Equal("offline", node.State, "State mismatch")
+	suite.Require().Equal("1.0.0", node.DMVersion, "DMVersion should be kept")
+	suite.Require().NotZero(node.LastContactTime, "LastContactTime should be kept")
+	node = suite.getNode(adminToken, "node-2", http.StatusOK)
+	suite.Require().Equal("online", node.State, "State mismatch")
+	suite.Require().Equal("degraded", node.Health, "Health mismatch")
+	suite.Require().Empty(node.LastError, "LastError should be cleared")
+
+	suite.getNode(adminToken, "node-3", http.StatusNotFound)
+	suite.listNodes("", http.StatusUnauthorized)
+}
+
+func (suite *HandlerTestSuite) listNodes(token string, expectedStatus int) *rest.ListNodesResponse {
+	listNodesResp := rest.SuccessResponse[rest.ListNodesResponse]{}
+	_, resp := suite.sendV1Request("GET", "/nodes", nil, &listNodesResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on list nodes")
+	return listNodesResp.Data
+}
+
+func (suite *HandlerTestSuite) getNode(token string, nodeID string, expectedStatus int) *rest.Node {
+	getNodeResp := rest.SuccessResponse[rest.Node]{}
+	_, resp := suite.sendV1Request("GET", "/nodes/"+nodeID, nil, &getNodeResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on get node")
+	return getNodeResp.Data
+}
+
+func (suite *HandlerTestSuite) listNodeMetrics(token string, expectedStatus int) *rest.ListNodeMetricsResponse {
+	listNodeMetricsResp := rest.SuccessResponse[rest.ListNodeMetricsResponse]{}
+	_, resp := suite.sendV1Request("GET", "/nodes/metrics", nil, &listNodeMetricsResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on list node metrics")
+	return listNodeMetricsResp.Data
+}
@@ -6,6 +6,7 @@ import (
 	docs "github.com/Gthulhu/api/docs/manager"
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -14,6 +15,7 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) {
 	engine.GET("/version", h.echoHandler(h.Version))
 	docs.SwaggerInfo.BasePath = "/"
 	engine.GET("/swagger/*", echoSwagger.WrapHandler)
+	engine.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
 	api := engine.Group("/api", echo.WrapMiddleware(LoggerMiddleware))
 	// v1 routes
@@ -39,12 +41,31 @@ func (h *Handler) SetupRoutes(engine *echo.Echo) {
 
 		// strategy routes
 		apiV1.POST("/strategies", h.echoHandler(h.CreateScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyCreate)))
+		apiV1.POST("/strategies/preview", h.echoHandler(h.PreviewScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyCreate)))
 		apiV1.GET("/strategies/self", h.echoHandler(h.ListSelfScheduleStrategies), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyRead)))
+		apiV1.PUT("/strategies/:id", h.echoHandler(h.UpdateScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyUpdate)))
+		apiV1.DELETE("/strategies/:id", h.echoHandler(h.DeleteScheduleStrategy), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleStrategyDelete)))
 		apiV1.GET("/intents/self", h.echoHandler(h.ListSelfScheduleIntents), echo.WrapMiddleware(h.GetAuthMiddleware(domain.ScheduleIntentRead)))
+
+		// node routes
+		apiV1.GET("/nodes", h.echoHandler(h.ListNodes), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeRead)))
+		apiV1.GET("/nodes/metrics", h.echoHandler(h.ListNodeMetrics), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeRead)))
+		apiV1.GET("/nodes/:id", h.echoHandler(h.GetNode), echo.WrapMiddleware(h.GetAuthMiddleware(domain.NodeRead)))
 	}
 
 }
 
+// echoHandler adapts a net/http handler to echo and exposes echo path parameters through r.PathValue.
 func (h *Handler) echoHandler(handlerFunc func(w http.ResponseWriter, r *http.Request)) echo.HandlerFunc {
-	return echo.WrapHandler(http.HandlerFunc(handlerFunc))
+	return func(c echo.Context) error {
+		r := c.Request()
+		values := c.ParamValues()
+		for i, name := range c.ParamNames() {
+			if i < len(values) {
+				r.SetPathValue(name, values[i])
+			}
+		}
+		handlerFunc(c.Response(), r)
+		return nil
+	}
 }
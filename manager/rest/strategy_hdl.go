@@ -1,9 +1,13 @@
 package rest
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -12,27 +16,63 @@ type LabelSelector struct {
 	Value string `json:"value,omitempty"`
 }
 
+// MatchExpression is a set based label requirement, the operator is one of In, NotIn, Exists and DoesNotExist
+type MatchExpression struct {
+	Key      string   `json:"key,omitempty"`
+	Operator string   `json:"operator,omitempty"`
+	Values   []string `json:"values,omitempty"`
+}
+
+// PodFieldFilter narrows the selected pods down by their fields, an empty field matches every pod
+type PodFieldFilter struct {
+	NodeNames  []string `json:"nodeNames,omitempty"`
+	QOSClasses []string `json:"qosClasses,omitempty"`
+	Phases     []string `json:"phases,omitempty"`
+	// OwnerKind and OwnerName match the direct owner of the pod, e.g. the ReplicaSet of a Deployment
+	OwnerKind string `json:"ownerKind,omitempty"`
+	OwnerName string `json:"ownerName,omitempty"`
+}
+
+type CreateScheduleStrategyResponse struct {
+	// Conflicts are the existing strategies selecting some of the pods of the new strategy
+	Conflicts []*StrategyConflict `json:"conflicts"`
+}
+
+type StrategyConflict struct {
+	StrategyID bson.ObjectID `json:"strategyID"`
+	PodIDs     []string      `json:"podIDs"`
+	// Wins reports whether the existing strategy takes precedence over the new one on those pods
+	Wins bool `json:"wins"`
+}
+
 type CreateScheduleStrategyRequest struct {
 	StrategyNamespace string          `json:"strategyNamespace,omitempty"`
 	LabelSelectors    []LabelSelector `json:"labelSelectors,omitempty"`
-	K8sNamespace      []string        `json:"k8sNamespace,omitempty"`
-	CommandRegex      string          `json:"commandRegex,omitempty"`
-	Priority          int             `json:"priority,omitempty"`
-	ExecutionTime     int64           `json:"executionTime,omitempty"`
+	// MatchExpressions are label requirements the pods have to meet on top of LabelSelectors
+	MatchExpressions []MatchExpression `json:"matchExpressions,omitempty"`
+	FieldFilter      *PodFieldFilter   `json:"fieldFilter,omitempty"`
+	K8sNamespace     []string          `json:"k8sNamespace,omitempty"`
+	CommandRegex     string            `json:"commandRegex,omitempty"`
+	Priority         int               `json:"priority,omitempty"`
+	ExecutionTime    int64             `json:"executionTime,omitempty"`
+	// Weight decides between strategies selecting the same pod, the higher weight wins
+	Weight int `json:"weight,omitempty"`
 }
 
 // CreateScheduleStrategy godoc
 // @Summary Create schedule strategy
-// @Description Create a new schedule strategy.
+// @Description Create a new schedule strategy. Existing strategies selecting the same pods are reported as conflicts, the strategy of highest precedence wins each pod: higher weight, then namespace-specific over cluster-wide, then newest. Conflicts are rejected with 409 when the manager is configured to.
 // @Tags Strategies
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body CreateScheduleStrategyRequest true "Schedule strategy payload"
-// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Success 200 {object} SuccessResponse[CreateScheduleStrategyResponse]
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
 // @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/strategies [post]
 func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request) {
@@ -44,13 +84,45 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	strategy := req.toDomainStrategy()
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	conflicts, err := h.Svc.CreateScheduleStrategy(ctx, &claims, strategy)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := CreateScheduleStrategyResponse{
+		Conflicts: make([]*StrategyConflict, len(conflicts)),
+	}
+	for i, conflict := range conflicts {
+		resp.Conflicts[i] = &StrategyConflict{
+			StrategyID: conflict.StrategyID,
+			PodIDs:     conflict.PodIDs,
+			Wins:       conflict.Wins,
+		}
+	}
+	response := NewSuccessResponse[CreateScheduleStrategyResponse](&resp)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+func (req CreateScheduleStrategyRequest) toDomainStrategy() *domain.ScheduleStrategy {
 	strategy := &domain.ScheduleStrategy{
 		StrategyNamespace: req.StrategyNamespace,
 		LabelSelectors:    make([]domain.LabelSelector, len(req.LabelSelectors)),
+		MatchExpressions:  convertMatchExpressionsToDomainMatchExpressions(req.MatchExpressions),
+		FieldFilter:       convertPodFieldFilterToDomainPodFieldFilter(req.FieldFilter),
 		K8sNamespace:      req.K8sNamespace,
 		CommandRegex:      req.CommandRegex,
 		Priority:          req.Priority,
 		ExecutionTime:     req.ExecutionTime,
+		Weight:            req.Weight,
 	}
 	for i, ls := range req.LabelSelectors {
 		strategy.LabelSelectors[i] = domain.LabelSelector{
@@ -58,14 +130,154 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 			Value: ls.Value,
 		}
 	}
+	return strategy
+}
+
+type PreviewScheduleStrategyResponse struct {
+	MatchedPods int                    `json:"matchedPods"`
+	Nodes       []*StrategyPreviewNode `json:"nodes"`
+	// Strategies already have an intent for one of the matched pods
+	Strategies []*ScheduleStrategy `json:"strategies"`
+}
+
+type StrategyPreviewNode struct {
+	NodeID                 string                      `json:"nodeID"`
+	DecisionMakerReachable bool                        `json:"decisionMakerReachable"`
+	DecisionMakerError     string                      `json:"decisionMakerError,omitempty"`
+	Namespaces             []*StrategyPreviewNamespace `json:"namespaces"`
+}
+
+type StrategyPreviewNamespace struct {
+	K8sNamespace string                `json:"k8sNamespace"`
+	Pods         []*StrategyPreviewPod `json:"pods"`
+}
+
+type StrategyPreviewPod struct {
+	PodID       string            `json:"podID"`
+	PodName     string            `json:"podName"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	StrategyIDs []bson.ObjectID   `json:"strategyIDs"`
+}
+
+// PreviewScheduleStrategy godoc
+// @Summary Preview schedule strategy
+// @Description Resolve the pods a schedule strategy would select, grouped by node and namespace, together with the reachability of the decision makers of those nodes and the existing strategies already targeting the pods. Nothing is stored.
+// @Tags Strategies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateScheduleStrategyRequest true "Schedule strategy payload"
+// @Success 200 {object} SuccessResponse[PreviewScheduleStrategyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/strategies/preview [post]
+func (h *Handler) PreviewScheduleStrategy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req CreateScheduleStrategyRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	preview, err := h.Svc.PreviewScheduleStrategy(ctx, req.toDomainStrategy())
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	resp := PreviewScheduleStrategyResponse{
+		MatchedPods: preview.MatchedPods,
+		Nodes:       make([]*StrategyPreviewNode, len(preview.Nodes)),
+		Strategies:  make([]*ScheduleStrategy, len(preview.Strategies)),
+	}
+	for i, node := range preview.Nodes {
+		respNode := &StrategyPreviewNode{
+			NodeID:                 node.NodeID,
+			DecisionMakerReachable: node.DecisionMakerReachable,
+			DecisionMakerError:     node.DecisionMakerError,
+			Namespaces:             make([]*StrategyPreviewNamespace, len(node.Namespaces)),
+		}
+		for j, namespace := range node.Namespaces {
+			respNamespace := &StrategyPreviewNamespace{
+				K8sNamespace: namespace.K8sNamespace,
+				Pods:         make([]*StrategyPreviewPod, len(namespace.Pods)),
+			}
+			for k, pod := range namespace.Pods {
+				strategyIDs := pod.StrategyIDs
+				if strategyIDs == nil {
+					strategyIDs = []bson.ObjectID{}
+				}
+				respNamespace.Pods[k] = &StrategyPreviewPod{
+					PodID:       pod.Pod.PodID,
+					PodName:     pod.Pod.Name,
+					Labels:      pod.Pod.Labels,
+					StrategyIDs: strategyIDs,
+				}
+			}
+			respNode.Namespaces[j] = respNamespace
+		}
+		resp.Nodes[i] = respNode
+	}
+	for i, strategy := range preview.Strategies {
+		resp.Strategies[i] = h.convertDomainStrategyToResponseStrategy(strategy)
+	}
+	response := NewSuccessResponse[PreviewScheduleStrategyResponse](&resp)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// UpdateScheduleStrategyRequest replaces the criteria of a strategy, they are the same as on creation
+type UpdateScheduleStrategyRequest struct {
+	CreateScheduleStrategyRequest
+}
+
+// UpdateScheduleStrategy godoc
+// @Summary Update schedule strategy
+// @Description Replace the criteria of a schedule strategy and push the resulting intent changes to the decision makers.
+// @Tags Strategies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Strategy ID"
+// @Param request body UpdateScheduleStrategyRequest true "Schedule strategy payload"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/strategies/{id} [put]
+func (h *Handler) UpdateScheduleStrategy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	strategyID := r.PathValue("id")
+	var req UpdateScheduleStrategyRequest
+	err := h.JSONBind(r, &req)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
 
 	claims, ok := h.GetClaimsFromContext(ctx)
 	if !ok {
 		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
 		return
 	}
+	err = h.verifyStrategyPolicy(ctx, strategyID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
 
-	err = h.Svc.CreateScheduleStrategy(ctx, &claims, strategy)
+	strategy := req.toDomainStrategy()
+	strategy.ID, err = bson.ObjectIDFromHex(strategyID)
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid strategy ID", err)
+		return
+	}
+
+	err = h.Svc.UpdateScheduleStrategy(ctx, &claims, strategyID, strategy)
 	if err != nil {
 		h.HandleError(ctx, w, err)
 		return
@@ -75,18 +287,80 @@ func (h *Handler) CreateScheduleStrategy(w http.ResponseWriter, r *http.Request)
 	h.JSONResponse(ctx, w, http.StatusOK, response)
 }
 
+// DeleteScheduleStrategy godoc
+// @Summary Delete schedule strategy
+// @Description Delete a schedule strategy and retract its intents from the decision makers.
+// @Tags Strategies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Strategy ID"
+// @Success 200 {object} SuccessResponse[EmptyResponse]
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/strategies/{id} [delete]
+func (h *Handler) DeleteScheduleStrategy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	strategyID := r.PathValue("id")
+
+	claims, ok := h.GetClaimsFromContext(ctx)
+	if !ok {
+		h.ErrorResponse(ctx, w, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+	err := h.verifyStrategyPolicy(ctx, strategyID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	err = h.Svc.DeleteScheduleStrategy(ctx, &claims, strategyID)
+	if err != nil {
+		h.HandleError(ctx, w, err)
+		return
+	}
+
+	response := NewSuccessResponse[string](nil)
+	h.JSONResponse(ctx, w, http.StatusOK, response)
+}
+
+// verifyStrategyPolicy checks that the role policy of the caller allows access to the strategy's owner.
+func (h *Handler) verifyStrategyPolicy(ctx context.Context, strategyID string) error {
+	id, err := bson.ObjectIDFromHex(strategyID)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid strategy ID", err)
+	}
+	queryOpt := &domain.QueryStrategyOptions{
+		IDs: []bson.ObjectID{id},
+	}
+	err = h.Svc.ListScheduleStrategies(ctx, queryOpt)
+	if err != nil {
+		return err
+	}
+	if len(queryOpt.Result) == 0 {
+		return errs.NewHTTPStatusError(http.StatusNotFound, "strategy not found", fmt.Errorf("strategy with ID %s not found", strategyID))
+	}
+	return h.VerifyResourcePolicy(ctx, queryOpt.Result[0].CreatorID.Hex())
+}
+
 type ListSchedulerStrategiesResponse struct {
 	Strategies []*ScheduleStrategy `json:"strategies"`
 }
 
 type ScheduleStrategy struct {
-	ID                bson.ObjectID   `bson:"_id,omitempty"`
-	StrategyNamespace string          `bson:"strategyNamespace,omitempty"`
-	LabelSelectors    []LabelSelector `bson:"labelSelectors,omitempty"`
-	K8sNamespace      []string        `bson:"k8sNamespace,omitempty"`
-	CommandRegex      string          `bson:"commandRegex,omitempty"`
-	Priority          int             `bson:"priority,omitempty"`
-	ExecutionTime     int64           `bson:"executionTime,omitempty"`
+	ID                bson.ObjectID     `bson:"_id,omitempty"`
+	StrategyNamespace string            `bson:"strategyNamespace,omitempty"`
+	LabelSelectors    []LabelSelector   `bson:"labelSelectors,omitempty"`
+	MatchExpressions  []MatchExpression `bson:"matchExpressions,omitempty"`
+	FieldFilter       *PodFieldFilter   `bson:"fieldFilter,omitempty"`
+	K8sNamespace      []string          `bson:"k8sNamespace,omitempty"`
+	CommandRegex      string            `bson:"commandRegex,omitempty"`
+	Priority          int               `bson:"priority,omitempty"`
+	ExecutionTime     int64             `bson:"executionTime,omitempty"`
+	Weight            int               `bson:"weight,omitempty"`
 }
 
 // ListSelfScheduleStrategies godoc
@@ -140,10 +414,13 @@ func (h *Handler) convertDomainStrategyToResponseStrategy(domainStrategy *domain
 		ID:                domainStrategy.ID,
 		StrategyNamespace: domainStrategy.StrategyNamespace,
 		LabelSelectors:    convertDomainLabelSelectorsToResponseLabelSelectors(domainStrategy.LabelSelectors),
+		MatchExpressions:  convertDomainMatchExpressionsToResponseMatchExpressions(domainStrategy.MatchExpressions),
+		FieldFilter:       convertDomainPodFieldFilterToResponsePodFieldFilter(domainStrategy.FieldFilter),
 		K8sNamespace:      domainStrategy.K8sNamespace,
 		CommandRegex:      domainStrategy.CommandRegex,
 		Priority:          domainStrategy.Priority,
 		ExecutionTime:     domainStrategy.ExecutionTime,
+		Weight:            domainStrategy.Weight,
 	}
 }
 
@@ -158,30 +435,94 @@ func convertDomainLabelSelectorsToResponseLabelSelectors(domainLabelSelectors []
 	return responseLabelSelectors
 }
 
+func convertMatchExpressionsToDomainMatchExpressions(matchExpressions []MatchExpression) []domain.MatchExpression {
+	if len(matchExpressions) == 0 {
+		return nil
+	}
+	domainMatchExpressions := make([]domain.MatchExpression, len(matchExpressions))
+	for i, me := range matchExpressions {
+		domainMatchExpressions[i] = domain.MatchExpression{
+			Key:      me.Key,
+			Operator: domain.MatchOperator(me.Operator),
+			Values:   me.Values,
+		}
+	}
+	return domainMatchExpressions
+}
+
+func convertDomainMatchExpressionsToResponseMatchExpressions(domainMatchExpressions []domain.MatchExpression) []MatchExpression {
+	if len(domainMatchExpressions) == 0 {
+		return nil
+	}
+	responseMatchExpressions := make([]MatchExpression, len(domainMatchExpressions))
+	for i, dme := range domainMatchExpressions {
+		responseMatchExpressions[i] = MatchExpression{
+			Key:      dme.Key,
+			Operator: string(dme.Operator),
+			Values:   dme.Values,
+		}
+	}
+	return responseMatchExpressions
+}
+
+func convertPodFieldFilterToDomainPodFieldFilter(filter *PodFieldFilter) *domain.PodFieldFilter {
+	if filter == nil {
+		return nil
+	}
+	return &domain.PodFieldFilter{
+		NodeNames:  filter.NodeNames,
+		QOSClasses: filter.QOSClasses,
+		Phases:     filter.Phases,
+		OwnerKind:  filter.OwnerKind,
+		OwnerName:  filter.OwnerName,
+	}
+}
+
+func convertDomainPodFieldFilterToResponsePodFieldFilter(domainFilter *domain.PodFieldFilter) *PodFieldFilter {
+	if domainFilter == nil {
+		return nil
+	}
+	return &PodFieldFilter{
+		NodeNames:  domainFilter.NodeNames,
+		QOSClasses: domainFilter.QOSClasses,
+		Phases:     domainFilter.Phases,
+		OwnerKind:  domainFilter.OwnerKind,
+		OwnerName:  domainFilter.OwnerName,
+	}
+}
+
 type ListScheduleIntentsResponse struct {
 	Intents []*ScheduleIntent `json:"intents"`
 }
 
 type ScheduleIntent struct {
-	ID            bson.ObjectID      `bson:"_id,omitempty"`
-	StrategyID    bson.ObjectID      `bson:"strategyID,omitempty"`
-	PodID         string             `bson:"podID,omitempty"`
-	NodeID        string             `bson:"nodeID,omitempty"`
-	K8sNamespace  string             `bson:"k8sNamespace,omitempty"`
-	CommandRegex  string             `bson:"commandRegex,omitempty"`
-	Priority      int                `bson:"priority,omitempty"`
-	ExecutionTime int64              `bson:"executionTime,omitempty"`
-	PodLabels     map[string]string  `bson:"podLabels,omitempty"`
-	State         domain.IntentState `bson:"state,omitempty"`
+	ID              bson.ObjectID      `bson:"_id,omitempty"`
+	StrategyID      bson.ObjectID      `bson:"strategyID,omitempty"`
+	PodID           string             `bson:"podID,omitempty"`
+	NodeID          string             `bson:"nodeID,omitempty"`
+	K8sNamespace    string             `bson:"k8sNamespace,omitempty"`
+	CommandRegex    string             `bson:"commandRegex,omitempty"`
+	Priority        int                `bson:"priority,omitempty"`
+	ExecutionTime   int64              `bson:"executionTime,omitempty"`
+	PodLabels       map[string]string  `bson:"podLabels,omitempty"`
+	State           domain.IntentState `bson:"state,omitempty" swaggertype:"string" enums:"unknown,initialized,sent,retracting,applied,failed,pod_not_found,overridden"`
+	Attempts        int                `bson:"attempts,omitempty"`
+	LastError       string             `bson:"lastError,omitempty"`
+	NextAttemptTime int64              `bson:"nextAttemptTime,omitempty"`
+	PIDCount        int                `bson:"pidCount,omitempty"`
+	Reason          string             `bson:"reason,omitempty"`
+	// WinningStrategyID is the strategy taking precedence on the pod, the intent is overridden unless it is StrategyID
+	WinningStrategyID bson.ObjectID `bson:"winningStrategyID,omitempty"`
 }
 
 // ListSelfScheduleIntents godoc
 // @Summary List self schedule intents
-// @Description List schedule intents created by the authenticated user.
+// @Description List schedule intents created by the authenticated user, optionally filtered by state.
 // @Tags Strategies
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param state query string false "Comma separated intent states to filter by (e.g. applied,failed,pod_not_found)"
 // @Success 200 {object} SuccessResponse[ListScheduleIntentsResponse]
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -201,8 +542,14 @@ func (h *Handler) ListSelfScheduleIntents(w http.ResponseWriter, r *http.Request
 		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid user ID in token", err)
 		return
 	}
+	states, err := parseIntentStates(r.URL.Query()["state"])
+	if err != nil {
+		h.ErrorResponse(ctx, w, http.StatusBadRequest, "Invalid intent state", err)
+		return
+	}
 	queryOpt := &domain.QueryIntentOptions{
 		CreatorIDs: []bson.ObjectID{uid},
+		States:     states,
 	}
 
 	err = h.Svc.ListScheduleIntents(ctx, queryOpt)
@@ -223,15 +570,40 @@ func (h *Handler) ListSelfScheduleIntents(w http.ResponseWriter, r *http.Request
 
 func (h *Handler) convertDomainIntentToResponseIntent(domainIntent *domain.ScheduleIntent) *ScheduleIntent {
 	return &ScheduleIntent{
-		ID:            domainIntent.ID,
-		StrategyID:    domainIntent.StrategyID,
-		PodID:         domainIntent.PodID,
-		NodeID:        domainIntent.NodeID,
-		K8sNamespace:  domainIntent.K8sNamespace,
-		CommandRegex:  domainIntent.CommandRegex,
-		Priority:      domainIntent.Priority,
-		ExecutionTime: domainIntent.ExecutionTime,
-		PodLabels:     domainIntent.PodLabels,
-		State:         domainIntent.State,
+		ID:                domainIntent.ID,
+		StrategyID:        domainIntent.StrategyID,
+		PodID:             domainIntent.PodID,
+		NodeID:            domainIntent.NodeID,
+		K8sNamespace:      domainIntent.K8sNamespace,
+		CommandRegex:      domainIntent.CommandRegex,
+		Priority:          domainIntent.Priority,
+		ExecutionTime:     domainIntent.ExecutionTime,
+		PodLabels:         domainIntent.PodLabels,
+		State:             domainIntent.State,
+		Attempts:          domainIntent.Attempts,
+		LastError:         domainIntent.LastError,
+		NextAttemptTime:   domainIntent.NextAttemptTime,
+		PIDCount:          domainIntent.PIDCount,
+		Reason:            domainIntent.Reason,
+		WinningStrategyID: domainIntent.WinningStrategyID,
+	}
+}
+
+// parseIntentStates parses state query values, each of which may hold a comma separated list of state names
+func parseIntentStates(values []string) ([]domain.IntentState, error) {
+	var states []domain.IntentState
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			state, err := domain.ParseIntentState(name)
+			if err != nil {
+				return nil, err
+			}
+			states = append(states, state)
+		}
 	}
+	return states, nil
 }
@@ -1,7 +1,10 @@
 package rest_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
@@ -25,7 +28,7 @@ func (suite *HandlerTestSuite) TestIntegrationStrategyHandler() {
 
 	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{{PodID: "Test", Labels: map[string]string{"test": "test"}, NodeID: "test"}}, nil).Once()
 	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{{Host: "dm-host", NodeID: "test", Port: 8080}}, nil).Once()
-	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(1)
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Times(1)
 	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
 
 	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
@@ -42,12 +45,492 @@ func (suite *HandlerTestSuite) TestIntegrationStrategyHandler() {
 	suite.Require().Equal(domain.IntentStateSent, intents.Intents[0].State, "State mismatch")
 	suite.Require().Equal(strategyReq.Priority, intents.Intents[0].Priority, "Priority mismatch")
 	suite.Require().Equal(strategyReq.ExecutionTime, intents.Intents[0].ExecutionTime, "ExecutionTime mismatch")
+
+	invalidRegexReq := strategyReq
+	invalidRegexReq.CommandRegex = "nginx("
+	suite.createStrategy(adminToken, &invalidRegexReq, http.StatusBadRequest)
+}
+
+func (suite *HandlerTestSuite) TestIntegrationUpdateAndDeleteStrategy() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "web"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}
+	podA := &domain.Pod{PodID: "pod-a", Labels: map[string]string{"app": "web"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-b", Labels: map[string]string{"app": "web"}, NodeID: "node-1"}
+	dmPod := &domain.DecisionMakerPod{Host: "dm-host", NodeID: "node-1", Port: 8080}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 1, "Expected one strategy")
+	strategyID := strategies.Strategies[0].ID.Hex()
+
+	// pod-a changes priority and pod-b is newly selected, both are pushed to the decision maker
+	updateReq := rest.UpdateScheduleStrategyRequest{
+		CreateScheduleStrategyRequest: rest.CreateScheduleStrategyRequest{
+			LabelSelectors: strategyReq.LabelSelectors,
+			Priority:       20,
+			ExecutionTime:  100,
+		},
+	}
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA, podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 2
+	})).Return(nil, nil).Once()
+	suite.updateStrategy(adminToken, strategyID, &updateReq, http.StatusOK)
+
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 2, "Expected two intents")
+	for _, intent := range intents.Intents {
+		suite.Require().Equal(updateReq.Priority, intent.Priority, "Priority mismatch")
+		suite.Require().Equal(domain.IntentStateSent, intent.State, "State mismatch")
+	}
+
+	// pod-a is no longer selected and gets retracted, pod-b is unchanged
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().DeleteSchedulingIntent(mock.Anything, dmPod, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].PodID == podA.PodID
+	})).Return(nil).Once()
+	suite.updateStrategy(adminToken, strategyID, &updateReq, http.StatusOK)
+
+	intents = suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one intent")
+	suite.Require().Equal(podB.PodID, intents.Intents[0].PodID, "PodID mismatch")
+
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().DeleteSchedulingIntent(mock.Anything, dmPod, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].PodID == podB.PodID
+	})).Return(nil).Once()
+	suite.deleteStrategy(adminToken, strategyID, http.StatusOK)
+
+	strategies = suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Empty(strategies.Strategies, "Expected no strategies")
+	intents = suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Empty(intents.Intents, "Expected no intents")
+
+	suite.deleteStrategy(adminToken, strategyID, http.StatusNotFound)
+}
+
+func (suite *HandlerTestSuite) TestIntegrationReconcileStrategies() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "web"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}
+	podA := &domain.Pod{PodID: "pod-a", Labels: map[string]string{"app": "web"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-b", Labels: map[string]string{"app": "web"}, NodeID: "node-2"}
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080}
+	dmPod2 := &domain.DecisionMakerPod{Host: "dm-host-2", NodeID: "node-2", Port: 8080}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	// nothing changed in the cluster, reconciliation is a no-op
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA}, nil).Once()
+	suite.Require().NoError(suite.Handler.Svc.ReconcileScheduleStrategies(suite.Ctx))
+
+	// a rollout replaced pod-a with pod-b on another node
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1, dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().DeleteSchedulingIntent(mock.Anything, dmPod1, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].PodID == podA.PodID
+	})).Return(nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod2, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].PodID == podB.PodID
+	})).Return(nil, nil).Once()
+	suite.Require().NoError(suite.Handler.Svc.ReconcileScheduleStrategies(suite.Ctx))
+
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one intent")
+	suite.Require().Equal(podB.PodID, intents.Intents[0].PodID, "PodID mismatch")
+	suite.Require().Equal("node-2", intents.Intents[0].NodeID, "NodeID mismatch")
+	suite.Require().Equal(domain.IntentStateSent, intents.Intents[0].State, "State mismatch")
+}
+
+func (suite *HandlerTestSuite) TestIntegrationIntentDeliveryRetry() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "web"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}
+	podA := &domain.Pod{PodID: "pod-a", Labels: map[string]string{"app": "web"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-b", Labels: map[string]string{"app": "web"}, NodeID: "node-2"}
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080}
+	dmPod2 := &domain.DecisionMakerPod{Host: "dm-host-2", NodeID: "node-2", Port: 8080}
+
+	// the decision maker on node-1 is flaky, node-2 must still be notified
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA, podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1, dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod2, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 2, "Expected two intents")
+	for _, intent := range intents.Intents {
+		suite.Require().Equal(1, intent.Attempts, "Attempts mismatch")
+		switch intent.PodID {
+		case podA.PodID:
+			suite.Require().Equal(domain.IntentStateInitialized, intent.State, "State mismatch")
+			suite.Require().Contains(intent.LastError, "connection refused", "LastError mismatch")
+			suite.Require().NotZero(intent.NextAttemptTime, "NextAttemptTime should be set")
+		case podB.PodID:
+			suite.Require().Equal(domain.IntentStateSent, intent.State, "State mismatch")
+			suite.Require().Empty(intent.LastError, "LastError should be empty")
+		}
+	}
+
+	// the worker retries the failed intent once its backoff expired
+	time.Sleep(10 * time.Millisecond)
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].PodID == podA.PodID
+	})).Return(nil, nil).Once()
+	suite.Require().NoError(suite.Handler.Svc.DeliverPendingIntents(suite.Ctx))
+
+	intents = suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 2, "Expected two intents")
+	for _, intent := range intents.Intents {
+		suite.Require().Equal(domain.IntentStateSent, intent.State, "State mismatch")
+		suite.Require().Empty(intent.LastError, "LastError should be cleared")
+		if intent.PodID == podA.PodID {
+			suite.Require().Equal(2, intent.Attempts, "Attempts mismatch")
+		}
+	}
+
+	// nothing is pending anymore
+	suite.Require().NoError(suite.Handler.Svc.DeliverPendingIntents(suite.Ctx))
+}
+
+func (suite *HandlerTestSuite) TestIntegrationRetractionGiveUp() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	podA := &domain.Pod{PodID: "pod-a", Labels: map[string]string{"app": "web"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-b", Labels: map[string]string{"app": "db"}, NodeID: "node-2"}
+	kubeProxy := &domain.Pod{PodID: "kube-proxy", NodeID: "node-1"}
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080}
+	dmPod2 := &domain.DecisionMakerPod{Host: "dm-host-2", NodeID: "node-2", Port: 8080}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.Anything).Return(nil, nil).Once()
+	webResp := suite.createStrategy(adminToken, &rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "web"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}, http.StatusOK)
+	suite.Require().Empty(webResp.Conflicts, "Expected no conflicts")
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod2}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod2, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "db"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}, http.StatusOK)
+	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 2, "Expected two strategies")
+
+	// node-1 is still up but its decision maker is gone, the retraction is retried until the attempts run out
+	// node-2 was deleted, nothing is left to retract from it
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{}, nil).Times(3)
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.MatchedBy(func(opt *domain.QueryPodsOptions) bool {
+		return opt.FieldFilter != nil && len(opt.FieldFilter.NodeNames) == 1 && opt.FieldFilter.NodeNames[0] == "node-1"
+	})).Return([]*domain.Pod{kubeProxy}, nil).Times(2)
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.MatchedBy(func(opt *domain.QueryPodsOptions) bool {
+		return opt.FieldFilter != nil && len(opt.FieldFilter.NodeNames) == 1 && opt.FieldFilter.NodeNames[0] == "node-2"
+	})).Return([]*domain.Pod{}, nil).Once()
+	for _, strategy := range strategies.Strategies {
+		suite.deleteStrategy(adminToken, strategy.ID.Hex(), http.StatusOK)
+	}
+
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "The retraction of the gone node should be dropped")
+	suite.Require().Equal(podA.PodID, intents.Intents[0].PodID, "PodID mismatch")
+	suite.Require().Equal(domain.IntentStateRetracting, intents.Intents[0].State, "State mismatch")
+	suite.Require().Equal(2, intents.Intents[0].Attempts, "Attempts mismatch")
+
+	time.Sleep(10 * time.Millisecond)
+	suite.Require().NoError(suite.Handler.Svc.DeliverPendingIntents(suite.Ctx))
+	intents = suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one intent")
+	suite.Require().Equal(domain.IntentStateFailed, intents.Intents[0].State, "Retraction should be given up")
+	suite.Require().Equal(3, intents.Intents[0].Attempts, "Attempts mismatch")
+	suite.Require().Contains(intents.Intents[0].LastError, "no decision maker pod found", "LastError mismatch")
+
+	// failed intents are not retried
+	suite.Require().NoError(suite.Handler.Svc.DeliverPendingIntents(suite.Ctx))
+}
+
+func (suite *HandlerTestSuite) TestIntegrationIntentResults() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "db"}},
+		Priority:       5,
+		ExecutionTime:  200,
+	}
+	podA := &domain.Pod{PodID: "pod-db-a", Labels: map[string]string{"app": "db"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-db-b", Labels: map[string]string{"app": "db"}, NodeID: "node-1"}
+	dmPod := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA, podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) ([]*domain.ScheduleIntentResult, error) {
+			results := make([]*domain.ScheduleIntentResult, 0, len(intents))
+			for _, intent := range intents {
+				switch intent.PodID {
+				case podA.PodID:
+					results = append(results, &domain.ScheduleIntentResult{IntentID: intent.ID, State: domain.IntentStateApplied, PIDCount: 3})
+				case podB.PodID:
+					results = append(results, &domain.ScheduleIntentResult{IntentID: intent.ID, State: domain.IntentStatePodNotFound, Reason: "pod not found on node"})
+				}
+			}
+			return results, nil
+		}).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	intents := suite.listSelfIntentsByState(adminToken, "applied", http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one applied intent")
+	suite.Require().Equal(podA.PodID, intents.Intents[0].PodID, "PodID mismatch")
+	suite.Require().Equal(domain.IntentStateApplied, intents.Intents[0].State, "State mismatch")
+	suite.Require().Equal(3, intents.Intents[0].PIDCount, "PIDCount mismatch")
+
+	intents = suite.listSelfIntentsByState(adminToken, "failed,pod_not_found", http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one unresolved intent")
+	suite.Require().Equal(podB.PodID, intents.Intents[0].PodID, "PodID mismatch")
+	suite.Require().Equal(domain.IntentStatePodNotFound, intents.Intents[0].State, "State mismatch")
+	suite.Require().Equal("pod not found on node", intents.Intents[0].Reason, "Reason mismatch")
+
+	// the state is reported by the name the filter takes
+	_, resp := suite.sendV1Request("GET", "/intents/self?state=pod_not_found", nil, nil, adminToken)
+	suite.Require().Equal(http.StatusOK, resp.Code, "Unexpected status code on list intents")
+	suite.Require().Contains(resp.Body.String(), `"State":"pod_not_found"`, "State should be serialized by name")
+
+	suite.listSelfIntentsByState(adminToken, "bogus", http.StatusBadRequest)
 }
 
-func (suite *HandlerTestSuite) createStrategy(token string, strategyReq *rest.CreateScheduleStrategyRequest, expectedStatus int) {
-	createStrategyResp := rest.SuccessResponse[string]{}
+func (suite *HandlerTestSuite) TestIntegrationPreviewStrategy() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "db"}},
+		Priority:       10,
+		ExecutionTime:  100,
+	}
+	podA := &domain.Pod{PodID: "pod-db-a", Name: "db-a", K8SNamespace: "prod", Labels: map[string]string{"app": "db"}, NodeID: "node-1"}
+	podB := &domain.Pod{PodID: "pod-db-b", Name: "db-b", K8SNamespace: "dev", Labels: map[string]string{"app": "db"}, NodeID: "node-1"}
+	podC := &domain.Pod{PodID: "pod-db-c", Name: "db-c", K8SNamespace: "prod", Labels: map[string]string{"app": "db"}, NodeID: "node-2"}
+	dmPod1 := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080, State: domain.NodeStateOnline}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podA}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod1, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 1, "Expected one strategy")
+
+	// node-2 runs no decision maker
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{podC, podA, podB}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod1}, nil).Once()
+	suite.MockDMAdapter.EXPECT().ProbeDecisionMaker(mock.Anything, dmPod1).Return(&domain.DecisionMakerStatus{Health: "healthy"}, nil).Once()
+	preview := suite.previewStrategy(adminToken, &strategyReq, http.StatusOK)
+	suite.Require().Equal(3, preview.MatchedPods, "MatchedPods mismatch")
+	suite.Require().Len(preview.Nodes, 2, "Expected two nodes")
+	suite.Require().Equal("node-1", preview.Nodes[0].NodeID, "Nodes should be sorted by ID")
+	suite.Require().True(preview.Nodes[0].DecisionMakerReachable, "Decision maker of node-1 should be reachable")
+	suite.Require().Len(preview.Nodes[0].Namespaces, 2, "Expected two namespaces on node-1")
+	suite.Require().Equal("dev", preview.Nodes[0].Namespaces[0].K8sNamespace, "Namespaces should be sorted")
+	suite.Require().Empty(preview.Nodes[0].Namespaces[0].Pods[0].StrategyIDs, "pod-db-b is not targeted yet")
+	suite.Require().Equal("prod", preview.Nodes[0].Namespaces[1].K8sNamespace, "Namespaces should be sorted")
+	suite.Require().Equal(strategies.Strategies[0].ID, preview.Nodes[0].Namespaces[1].Pods[0].StrategyIDs[0], "pod-db-a is targeted by the existing strategy")
+	suite.Require().False(preview.Nodes[1].DecisionMakerReachable, "node-2 runs no decision maker")
+	suite.Require().NotEmpty(preview.Nodes[1].DecisionMakerError, "DecisionMakerError should be set")
+	suite.Require().Len(preview.Strategies, 1, "Expected the existing strategy")
+
+	// nothing was stored by the preview
+	strategies = suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 1, "Expected one strategy")
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one intent")
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{}, nil).Once()
+	preview = suite.previewStrategy(adminToken, &strategyReq, http.StatusOK)
+	suite.Require().Zero(preview.MatchedPods, "MatchedPods mismatch")
+	suite.Require().Empty(preview.Nodes, "Expected no nodes")
+
+	invalidRegexReq := strategyReq
+	invalidRegexReq.CommandRegex = "nginx("
+	suite.previewStrategy(adminToken, &invalidRegexReq, http.StatusBadRequest)
+}
+
+func (suite *HandlerTestSuite) previewStrategy(token string, strategyReq *rest.CreateScheduleStrategyRequest, expectedStatus int) *rest.PreviewScheduleStrategyResponse {
+	previewStrategyResp := rest.SuccessResponse[rest.PreviewScheduleStrategyResponse]{}
+	_, resp := suite.sendV1Request("POST", "/strategies/preview", strategyReq, &previewStrategyResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on preview strategy")
+	return previewStrategyResp.Data
+}
+
+func (suite *HandlerTestSuite) TestIntegrationStrategyMatchExpressions() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	strategyReq := rest.CreateScheduleStrategyRequest{
+		MatchExpressions: []rest.MatchExpression{
+			{Key: "tier", Operator: "In", Values: []string{"backend", "cache"}},
+			{Key: "canary", Operator: "DoesNotExist"},
+		},
+		FieldFilter: &rest.PodFieldFilter{
+			NodeNames:  []string{"node-1"},
+			QOSClasses: []string{"Guaranteed"},
+			Phases:     []string{"Running"},
+			OwnerKind:  "ReplicaSet",
+		},
+		Priority:      10,
+		ExecutionTime: 100,
+	}
+	pod := &domain.Pod{PodID: "pod-backend", Name: "backend", K8SNamespace: "prod", Labels: map[string]string{"tier": "backend"}, NodeID: "node-1"}
+	dmPod := &domain.DecisionMakerPod{Host: "dm-host-1", NodeID: "node-1", Port: 8080, State: domain.NodeStateOnline}
+
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.MatchedBy(func(opt *domain.QueryPodsOptions) bool {
+		return len(opt.MatchExpressions) == 2 && opt.MatchExpressions[0].Operator == domain.MatchOperatorIn &&
+			opt.FieldFilter != nil && opt.FieldFilter.OwnerKind == "ReplicaSet"
+	})).Return([]*domain.Pod{pod}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.Anything).Return(nil, nil).Once()
+	suite.createStrategy(adminToken, &strategyReq, http.StatusOK)
+
+	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 1, "Expected one strategy")
+	suite.Require().Equal(strategyReq.MatchExpressions, strategies.Strategies[0].MatchExpressions, "MatchExpressions mismatch")
+	suite.Require().Equal(strategyReq.FieldFilter, strategies.Strategies[0].FieldFilter, "FieldFilter mismatch")
+
+	invalidOperatorReq := strategyReq
+	invalidOperatorReq.MatchExpressions = []rest.MatchExpression{{Key: "tier", Operator: "Gt", Values: []string{"1"}}}
+	suite.createStrategy(adminToken, &invalidOperatorReq, http.StatusBadRequest)
+
+	missingValuesReq := strategyReq
+	missingValuesReq.MatchExpressions = []rest.MatchExpression{{Key: "tier", Operator: "NotIn"}}
+	suite.previewStrategy(adminToken, &missingValuesReq, http.StatusBadRequest)
+
+	unknownQOSReq := strategyReq
+	unknownQOSReq.FieldFilter = &rest.PodFieldFilter{QOSClasses: []string{"Premium"}}
+	suite.previewStrategy(adminToken, &unknownQOSReq, http.StatusBadRequest)
+
+	// label keys and values are validated by kubernetes
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidSelector).Once()
+	suite.previewStrategy(adminToken, &strategyReq, http.StatusBadRequest)
+}
+
+func (suite *HandlerTestSuite) TestIntegrationStrategyConflicts() {
+	adminUser, adminPwd := config.GetManagerConfig().Account.AdminEmail, config.GetManagerConfig().Account.AdminPassword
+	adminToken := suite.login(adminUser, adminPwd.Value(), http.StatusOK)
+
+	pod := &domain.Pod{PodID: "pod-shared", Labels: map[string]string{"app": "shared"}, NodeID: "node-1"}
+	dmPod := &domain.DecisionMakerPod{Host: "dm-host", NodeID: "node-1", Port: 8080, State: domain.NodeStateOnline}
+	clusterWideReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: []rest.LabelSelector{{Key: "app", Value: "shared"}},
+		Priority:       1,
+		ExecutionTime:  100,
+	}
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{pod}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.Anything).Return(nil, nil).Once()
+	created := suite.createStrategy(adminToken, &clusterWideReq, http.StatusOK)
+	suite.Require().Empty(created.Conflicts, "Expected no conflicts")
+	strategies := suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 1, "Expected one strategy")
+	clusterWideID := strategies.Strategies[0].ID
+
+	// the weightier strategy wins the pod, only its intent is pushed
+	weightyReq := rest.CreateScheduleStrategyRequest{
+		LabelSelectors: clusterWideReq.LabelSelectors,
+		Priority:       0,
+		ExecutionTime:  500,
+		Weight:         10,
+	}
+	suite.MockK8SAdapter.EXPECT().QueryPods(mock.Anything, mock.Anything).Return([]*domain.Pod{pod}, nil).Once()
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].StrategyID != clusterWideID && intents[0].ExecutionTime == 500
+	})).Return(nil, nil).Once()
+	created = suite.createStrategy(adminToken, &weightyReq, http.StatusOK)
+	suite.Require().Len(created.Conflicts, 1, "Expected one conflict")
+	suite.Require().Equal(clusterWideID, created.Conflicts[0].StrategyID, "Conflicting strategy mismatch")
+	suite.Require().Equal([]string{pod.PodID}, created.Conflicts[0].PodIDs, "Conflicting pods mismatch")
+	suite.Require().False(created.Conflicts[0].Wins, "The existing strategy should lose")
+
+	strategies = suite.listSelfStrategies(adminToken, http.StatusOK)
+	suite.Require().Len(strategies.Strategies, 2, "Expected two strategies")
+	weightyID := strategies.Strategies[0].ID
+	if weightyID == clusterWideID {
+		weightyID = strategies.Strategies[1].ID
+	}
+	intents := suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 2, "Expected two intents")
+	for _, intent := range intents.Intents {
+		suite.Require().Equal(weightyID, intent.WinningStrategyID, "WinningStrategyID mismatch")
+		if intent.StrategyID == clusterWideID {
+			suite.Require().Equal(domain.IntentStateOverridden, intent.State, "The losing intent should be overridden")
+		} else {
+			suite.Require().Equal(domain.IntentStateSent, intent.State, "The winning intent should be sent")
+		}
+	}
+
+	// deleting the winner hands the pod back to the cluster-wide strategy without retracting it from the decision maker
+	suite.MockK8SAdapter.EXPECT().QueryDecisionMakerPods(mock.Anything, mock.Anything).Return([]*domain.DecisionMakerPod{dmPod}, nil).Once()
+	suite.MockDMAdapter.EXPECT().SendSchedulingIntent(mock.Anything, dmPod, mock.MatchedBy(func(intents []*domain.ScheduleIntent) bool {
+		return len(intents) == 1 && intents[0].StrategyID == clusterWideID
+	})).Return(nil, nil).Once()
+	suite.deleteStrategy(adminToken, weightyID.Hex(), http.StatusOK)
+
+	intents = suite.listSelfIntents(adminToken, http.StatusOK)
+	suite.Require().Len(intents.Intents, 1, "Expected one intent")
+	suite.Require().Equal(clusterWideID, intents.Intents[0].StrategyID, "StrategyID mismatch")
+	suite.Require().Equal(clusterWideID, intents.Intents[0].WinningStrategyID, "WinningStrategyID mismatch")
+	suite.Require().Equal(domain.IntentStateSent, intents.Intents[0].State, "The promoted intent should be sent")
+}
+
+func (suite *HandlerTestSuite) createStrategy(token string, strategyReq *rest.CreateScheduleStrategyRequest, expectedStatus int) *rest.CreateScheduleStrategyResponse {
+	createStrategyResp := rest.SuccessResponse[rest.CreateScheduleStrategyResponse]{}
 	_, resp := suite.sendV1Request("POST", "/strategies", strategyReq, &createStrategyResp, token)
 	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on create strategy")
+	return createStrategyResp.Data
+}
+
+func (suite *HandlerTestSuite) updateStrategy(token string, strategyID string, strategyReq *rest.UpdateScheduleStrategyRequest, expectedStatus int) {
+	updateStrategyResp := rest.SuccessResponse[string]{}
+	_, resp := suite.sendV1Request("PUT", "/strategies/"+strategyID, strategyReq, &updateStrategyResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on update strategy")
+}
+
+func (suite *HandlerTestSuite) deleteStrategy(token string, strategyID string, expectedStatus int) {
+	deleteStrategyResp := rest.SuccessResponse[string]{}
+	_, resp := suite.sendV1Request("DELETE", "/strategies/"+strategyID, nil, &deleteStrategyResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on delete strategy")
 }
 
 func (suite *HandlerTestSuite) listSelfStrategies(token string, expectedStatus int) *rest.ListSchedulerStrategiesResponse {
@@ -63,3 +546,10 @@ func (suite *HandlerTestSuite) listSelfIntents(token string, expectedStatus int)
 	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on create strategy")
 	return listStrategiesResp.Data
 }
+
+func (suite *HandlerTestSuite) listSelfIntentsByState(token string, states string, expectedStatus int) *rest.ListScheduleIntentsResponse {
+	listIntentsResp := rest.SuccessResponse[rest.ListScheduleIntentsResponse]{}
+	_, resp := suite.sendV1Request("GET", "/intents/self?state="+states, nil, &listIntentsResp, token)
+	suite.Require().Equal(expectedStatus, resp.Code, "Unexpected status code on list intents")
+	return listIntentsResp.Data
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Gthulhu/api/config"
+	"github.com/Gthulhu/api/manager/domain"
+)
+
+const (
+	defaultDecisionMakerLabelSelector = "app=decisionmaker"
+	defaultDecisionMakerServiceName   = "decisionmaker"
+)
+
+// decisionMakerDiscovery describes where the decision makers run, one runs on every node
+type decisionMakerDiscovery struct {
+	namespace   string
+	labels      []domain.LabelSelector
+	portName    string
+	serviceName string
+}
+
+func newDecisionMakerDiscovery(cfg config.DecisionMakerDiscoveryConfig) (decisionMakerDiscovery, error) {
+	selector := cfg.LabelSelector
+	if strings.TrimSpace(selector) == "" {
+		selector = defaultDecisionMakerLabelSelector
+	}
+	labels, err := parseLabelSelector(selector)
+	if err != nil {
+		return decisionMakerDiscovery{}, err
+	}
+	discovery := decisionMakerDiscovery{
+		namespace: cfg.Namespace,
+		labels:    labels,
+		portName:  cfg.PortName,
+	}
+	if cfg.UseEndpointSlices {
+		discovery.serviceName = cfg.ServiceName
+		if discovery.serviceName == "" {
+			discovery.serviceName = defaultDecisionMakerServiceName
+		}
+	}
+	return discovery, nil
+}
+
+// queryOptions returns the options finding the decision makers of the given nodes, of every node when empty
+func (discovery decisionMakerDiscovery) queryOptions(nodeIDs []string) *domain.QueryDecisionMakerPodsOptions {
+	opt := &domain.QueryDecisionMakerPodsOptions{
+		NodeIDs:             nodeIDs,
+		DecisionMakerLabels: discovery.labels,
+		PortName:            discovery.portName,
+		ServiceName:         discovery.serviceName,
+	}
+	if discovery.namespace != "" {
+		opt.K8SNamespace = []string{discovery.namespace}
+	}
+	return opt
+}
+
+// matchesPod reports whether pod is a decision maker pod
+func (discovery decisionMakerDiscovery) matchesPod(pod *domain.Pod) bool {
+	if discovery.namespace != "" && pod.K8SNamespace != discovery.namespace {
+		return false
+	}
+	for _, selector := range discovery.labels {
+		value, ok := pod.Labels[selector.Key]
+		if !ok || (selector.Value != "" && value != selector.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// decisionMakersByNode picks the decision maker of every node. During a rolling update a node runs two decision
+// maker pods, the running one is preferred.
+func decisionMakersByNode(dms []*domain.DecisionMakerPod) map[string]*domain.DecisionMakerPod {
+	dmByNode := make(map[string]*domain.DecisionMakerPod, len(dms))
+	for _, dmPod := range dms {
+		if current, ok := dmByNode[dmPod.NodeID]; !ok || (current.State != domain.NodeStateOnline && dmPod.State == domain.NodeStateOnline) {
+			dmByNode[dmPod.NodeID] = dmPod
+		}
+	}
+	return dmByNode
+}
+
+// parseLabelSelector parses an equality based label selector such as "app=decisionmaker,tier", a key without a
+// value requires the label to exist
+func parseLabelSelector(selector string) ([]domain.LabelSelector, error) {
+	labels := []domain.LabelSelector{}
+	for _, requirement := range strings.Split(selector, ",") {
+		requirement = strings.TrimSpace(requirement)
+		if requirement == "" {
+			continue
+		}
+		if strings.ContainsAny(requirement, "!() ") {
+			return nil, fmt.Errorf("label selector %q: only equality based requirements are supported", selector)
+		}
+		key, value, _ := strings.Cut(requirement, "=")
+		value = strings.TrimPrefix(value, "=")
+		if key == "" || strings.Contains(value, "=") {
+			return nil, fmt.Errorf("label selector %q: invalid requirement %q", selector, requirement)
+		}
+		labels = append(labels, domain.LabelSelector{Key: key, Value: value})
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("label selector %q selects nothing", selector)
+	}
+	return labels, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	_ prometheus.Collector = (*nodeMetricsCollector)(nil)
+)
+
+// metricNamespace prefixes every metric exported by the manager, as it does for the decision makers
+const metricNamespace = "gthulhu"
+
+// nodeMetricsCollector exports the last scrape of the decision makers, per node and summed over the cluster
+type nodeMetricsCollector struct {
+	store *nodeMetricsStore
+
+	clusterNodes              *prometheus.Desc
+	clusterNodesReporting     *prometheus.Desc
+	clusterNrQueued           *prometheus.Desc
+	clusterNrScheduled        *prometheus.Desc
+	clusterNrRunning          *prometheus.Desc
+	clusterFailedDispatches   *prometheus.Desc
+	clusterSchedCongested     *prometheus.Desc
+	clusterDispatchRate       *prometheus.Desc
+	nodeScrapeSuccess         *prometheus.Desc
+	nodeNrQueued              *prometheus.Desc
+	nodeFailedDispatches      *prometheus.Desc
+	nodeSchedCongested        *prometheus.Desc
+	nodeDispatchRate          *prometheus.Desc
+	nodeFailedDispatchRatio   *prometheus.Desc
+	nodeSampleTimestampSecond *prometheus.Desc
+}
+
+func newNodeMetricsCollector(store *nodeMetricsStore) *nodeMetricsCollector {
+	clusterDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricNamespace, "cluster", name), help, nil, nil)
+	}
+	nodeDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricNamespace, "node", name), help, []string{"node_id"}, nil)
+	}
+	return &nodeMetricsCollector{
+		store:                     store,
+		clusterNodes:              clusterDesc("nodes", "number of nodes with a decision maker"),
+		clusterNodesReporting:     clusterDesc("nodes_reporting", "number of nodes whose last metrics scrape succeeded"),
+		clusterNrQueued:           clusterDesc("nr_queued", "tasks queued in the userspace schedulers of the reporting nodes"),
+		clusterNrScheduled:        clusterDesc("nr_scheduled", "tasks scheduled by the userspace schedulers of the reporting nodes"),
+		clusterNrRunning:          clusterDesc("nr_running", "tasks running in the userspace schedulers of the reporting nodes"),
+		clusterFailedDispatches:   clusterDesc("failed_dispatches_total", "failed dispatches of the reporting nodes"),
+		clusterSchedCongested:     clusterDesc("sched_congested_total", "scheduler congestion events of the reporting nodes"),
+		clusterDispatchRate:       clusterDesc("dispatches_per_second", "dispatches per second of the reporting nodes"),
+		nodeScrapeSuccess:         nodeDesc("scrape_success", "whether the last metrics scrape of the decision maker succeeded"),
+		nodeNrQueued:              nodeDesc("nr_queued", "tasks queued in the userspace scheduler of the node"),
+		nodeFailedDispatches:      nodeDesc("failed_dispatches_total", "failed dispatches of the node"),
+		nodeSchedCongested:        nodeDesc("sched_congested_total", "scheduler congestion events of the node"),
+		nodeDispatchRate:          nodeDesc("dispatches_per_second", "dispatches per second of the node"),
+		nodeFailedDispatchRatio:   nodeDesc("failed_dispatch_ratio", "share of the dispatch attempts of the node that failed"),
+		nodeSampleTimestampSecond: nodeDesc("sample_timestamp_seconds", "when the decision maker received the last scraped sample"),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (collector *nodeMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- collector.clusterNodes
+	ch <- collector.clusterNodesReporting
+	ch <- collector.clusterNrQueued
+	ch <- collector.clusterNrScheduled
+	ch <- collector.clusterNrRunning
+	ch <- collector.clusterFailedDispatches
+	ch <- collector.clusterSchedCongested
+	ch <- collector.clusterDispatchRate
+	ch <- collector.nodeScrapeSuccess
+	ch <- collector.nodeNrQueued
+	ch <- collector.nodeFailedDispatches
+	ch <- collector.nodeSchedCongested
+	ch <- collector.nodeDispatchRate
+	ch <- collector.nodeFailedDispatchRatio
+	ch <- collector.nodeSampleTimestampSecond
+}
+
+// Collect implements prometheus.Collector
+func (collector *nodeMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	cluster := collector.store.cluster()
+	ch <- prometheus.MustNewConstMetric(collector.clusterNodes, prometheus.GaugeValue, float64(len(cluster.Nodes)))
+	ch <- prometheus.MustNewConstMetric(collector.clusterNodesReporting, prometheus.GaugeValue, float64(cluster.NodesReporting))
+	ch <- prometheus.MustNewConstMetric(collector.clusterNrQueued, prometheus.GaugeValue, cluster.NrQueued)
+	ch <- prometheus.MustNewConstMetric(collector.clusterNrScheduled, prometheus.GaugeValue, cluster.NrScheduled)
+	ch <- prometheus.MustNewConstMetric(collector.clusterNrRunning, prometheus.GaugeValue, cluster.NrRunning)
+	ch <- prometheus.MustNewConstMetric(collector.clusterFailedDispatches, prometheus.CounterValue, float64(cluster.NrFailedDispatches))
+	ch <- prometheus.MustNewConstMetric(collector.clusterSchedCongested, prometheus.CounterValue, float64(cluster.NrSchedCongested))
+	ch <- prometheus.MustNewConstMetric(collector.clusterDispatchRate, prometheus.GaugeValue, cluster.DispatchRate)
+
+	for _, nodeMetrics := range cluster.Nodes {
+		success := 0.0
+		if nodeMetrics.Reporting() {
+			success = 1
+		}
+		ch <- prometheus.MustNewConstMetric(collector.nodeScrapeSuccess, prometheus.GaugeValue, success, nodeMetrics.NodeID)
+		sample := nodeMetrics.Sample
+		if sample == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(collector.nodeNrQueued, prometheus.GaugeValue, sample.NrQueued, nodeMetrics.NodeID)
+		ch <- prometheus.MustNewConstMetric(collector.nodeFailedDispatches, prometheus.CounterValue, float64(sample.NrFailedDispatches), nodeMetrics.NodeID)
+		ch <- prometheus.MustNewConstMetric(collector.nodeSchedCongested, prometheus.CounterValue, float64(sample.NrSchedCongested), nodeMetrics.NodeID)
+		ch <- prometheus.MustNewConstMetric(collector.nodeDispatchRate, prometheus.GaugeValue, sample.DispatchRate, nodeMetrics.NodeID)
+		ch <- prometheus.MustNewConstMetric(collector.nodeFailedDispatchRatio, prometheus.GaugeValue, sample.FailedDispatchRatio, nodeMetrics.NodeID)
+		ch <- prometheus.MustNewConstMetric(collector.nodeSampleTimestampSecond, prometheus.GaugeValue, float64(sample.Timestamp)/1000, nodeMetrics.NodeID)
+	}
+}
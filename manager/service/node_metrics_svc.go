@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+const (
+	defaultNodeMetricsScrapeInterval = 15 * time.Second
+	defaultNodeMetricsScrapeTimeout  = 5 * time.Second
+)
+
+// nodeMetricsStore holds the outcome of the last scrape of every decision maker
+type nodeMetricsStore struct {
+	mu        sync.RWMutex
+	scrapedAt int64
+	nodes     map[string]*domain.NodeMetrics
+}
+
+func newNodeMetricsStore() *nodeMetricsStore {
+	return &nodeMetricsStore{nodes: make(map[string]*domain.NodeMetrics)}
+}
+
+// replace stores the outcome of a scrape, nodes that were not scraped are dropped
+func (store *nodeMetricsStore) replace(nodes map[string]*domain.NodeMetrics, scrapedAt int64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.nodes = nodes
+	store.scrapedAt = scrapedAt
+}
+
+func (store *nodeMetricsStore) get(nodeID string) (*domain.NodeMetrics, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	nodeMetrics, ok := store.nodes[nodeID]
+	return nodeMetrics, ok
+}
+
+// cluster aggregates the stored node metrics, the nodes are sorted by ID
+func (store *nodeMetricsStore) cluster() *domain.ClusterMetrics {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	cluster := &domain.ClusterMetrics{
+		ScrapedAt: store.scrapedAt,
+		Nodes:     make([]*domain.NodeMetrics, 0, len(store.nodes)),
+	}
+	for _, nodeMetrics := range store.nodes {
+		cluster.Nodes = append(cluster.Nodes, nodeMetrics)
+		if !nodeMetrics.Reporting() {
+			continue
+		}
+		cluster.NodesReporting++
+		cluster.NrQueued += nodeMetrics.Sample.NrQueued
+		cluster.NrScheduled += nodeMetrics.Sample.NrScheduled
+		cluster.NrRunning += nodeMetrics.Sample.NrRunning
+		cluster.NrFailedDispatches += nodeMetrics.Sample.NrFailedDispatches
+		cluster.NrSchedCongested += nodeMetrics.Sample.NrSchedCongested
+		cluster.DispatchRate += nodeMetrics.Sample.DispatchRate
+	}
+	slices.SortFunc(cluster.Nodes, func(a, b *domain.NodeMetrics) int {
+		return strings.Compare(a.NodeID, b.NodeID)
+	})
+	return cluster
+}
+
+// ListNodeMetrics returns the scheduler metrics of every node from the last scrape together with their cluster totals
+func (svc *Service) ListNodeMetrics(ctx context.Context) (*domain.ClusterMetrics, error) {
+	return svc.nodeMetrics.cluster(), nil
+}
+
+// ScrapeNodeMetrics fetches the latest scheduler metrics from the decision maker of every node. A node whose
+// decision maker cannot be reached keeps its last sample and reports the error. Only discovery errors are returned.
+func (svc *Service) ScrapeNodeMetrics(ctx context.Context) error {
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, svc.dmDiscovery.queryOptions(nil))
+	if err != nil {
+		return fmt.Errorf("query decision maker pods: %w", err)
+	}
+
+	interval, timeout := svc.nodeMetricsScrapeSettings()
+	now := time.Now()
+	dmByNode := decisionMakersByNode(dms)
+	nodes := make(map[string]*domain.NodeMetrics, len(dmByNode))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dmPod := range dmByNode {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scrapeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			nodeMetrics := svc.scrapeNodeMetrics(scrapeCtx, dmPod, now, interval)
+			mu.Lock()
+			nodes[dmPod.NodeID] = nodeMetrics
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	svc.nodeMetrics.replace(nodes, now.UnixMilli())
+	return nil
+}
+
+// scrapeNodeMetrics takes the latest sample the decision maker received within the last two scrape intervals
+func (svc *Service) scrapeNodeMetrics(ctx context.Context, dmPod *domain.DecisionMakerPod, now time.Time, interval time.Duration) *domain.NodeMetrics {
+	nodeMetrics := &domain.NodeMetrics{
+		NodeID:    dmPod.NodeID,
+		Host:      dmPod.Host,
+		ScrapedAt: now.UnixMilli(),
+	}
+	if previous, ok := svc.nodeMetrics.get(dmPod.NodeID); ok {
+		nodeMetrics.Sample = previous.Sample
+	}
+
+	window := 2 * interval
+	samples, err := svc.DMAdapter.GetMetricsHistory(ctx, dmPod, now.Add(-window), now)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("scrape metrics of decision maker %s", dmPod)
+		nodeMetrics.Error = err.Error()
+		return nodeMetrics
+	}
+	if len(samples) == 0 {
+		nodeMetrics.Error = fmt.Sprintf("scheduler pushed no metrics in the last %s", window)
+		return nodeMetrics
+	}
+	nodeMetrics.Sample = samples[len(samples)-1]
+	return nodeMetrics
+}
+
+func (svc *Service) nodeMetricsScrapeSettings() (interval, timeout time.Duration) {
+	interval = svc.nodeMetricsConfig.ScrapeInterval
+	if interval <= 0 {
+		interval = defaultNodeMetricsScrapeInterval
+	}
+	timeout = svc.nodeMetricsConfig.ScrapeTimeout
+	if timeout <= 0 {
+		timeout = defaultNodeMetricsScrapeTimeout
+	}
+	return interval, timeout
+}
+
+// RunNodeMetricsScraper scrapes the decision makers on start and then periodically until ctx is done.
+func (svc *Service) RunNodeMetricsScraper(ctx context.Context) error {
+	interval, timeout := svc.nodeMetricsScrapeSettings()
+	logger.Logger(ctx).Info().Msgf("starting node metrics scraper, interval:%s timeout:%s", interval, timeout)
+
+	scrape := func() {
+		if err := svc.ScrapeNodeMetrics(ctx); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msg("scrape node metrics")
+		}
+	}
+	scrape()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			scrape()
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/manager/errs"
+	"github.com/Gthulhu/api/pkg/logger"
+)
+
+const (
+	defaultNodeProbeInterval = 30 * time.Second
+	defaultNodeProbeTimeout  = 5 * time.Second
+)
+
+// ListNodes returns the nodes of the registry together with the number of intents targeting them
+func (svc *Service) ListNodes(ctx context.Context, opt *domain.QueryNodeOptions) error {
+	err := svc.Repo.QueryNodes(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("query nodes: %w", err)
+	}
+	if len(opt.Result) == 0 {
+		return nil
+	}
+	counts, err := svc.Repo.CountIntentsByNode(ctx, opt.NodeIDs)
+	if err != nil {
+		return err
+	}
+	for _, node := range opt.Result {
+		node.IntentCounts = counts[node.NodeID]
+	}
+	return nil
+}
+
+// GetNode returns a node of the registry together with the number of intents targeting it
+func (svc *Service) GetNode(ctx context.Context, nodeID string) (*domain.Node, error) {
+	opt := &domain.QueryNodeOptions{NodeIDs: []string{nodeID}}
+	err := svc.ListNodes(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if len(opt.Result) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, "node not found", fmt.Errorf("node %s not found", nodeID))
+	}
+	return opt.Result[0], nil
+}
+
+// SyncNodeRegistry records every node running a decision maker and probes the decision makers for their health and
+// version. Nodes that no longer run a decision maker stay in the registry as offline.
+func (svc *Service) SyncNodeRegistry(ctx context.Context) error {
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, svc.dmDiscovery.queryOptions(nil))
+	if err != nil {
+		return fmt.Errorf("query decision maker pods: %w", err)
+	}
+	opt := &domain.QueryNodeOptions{}
+	err = svc.Repo.QueryNodes(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("query nodes: %w", err)
+	}
+
+	nodes := make(map[string]*domain.Node, len(opt.Result))
+	for _, node := range opt.Result {
+		nodes[node.NodeID] = node
+	}
+	dmByNode := decisionMakersByNode(dms)
+
+	_, timeout := svc.nodeRegistrySettings()
+	now := time.Now().UnixMilli()
+	var wg sync.WaitGroup
+	for nodeID, dmPod := range dmByNode {
+		node, ok := nodes[nodeID]
+		if !ok {
+			node = &domain.Node{NodeID: nodeID}
+			nodes[nodeID] = node
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			svc.probeNode(probeCtx, node, dmPod, now)
+		}()
+	}
+	wg.Wait()
+
+	updated := make([]*domain.Node, 0, len(nodes))
+	for nodeID, node := range nodes {
+		if _, ok := dmByNode[nodeID]; !ok {
+			node.State = domain.NodeStateOffline
+			node.LastError = "no decision maker pod runs on the node"
+		}
+		updated = append(updated, node)
+	}
+	err = svc.Repo.UpsertNodes(ctx, updated)
+	if err != nil {
+		return fmt.Errorf("upsert nodes: %w", err)
+	}
+	return nil
+}
+
+// probeNode updates node with the pod state of its decision maker and the outcome of a probe
+func (svc *Service) probeNode(ctx context.Context, node *domain.Node, dmPod *domain.DecisionMakerPod, now int64) {
+	node.Host = dmPod.Host
+	node.Port = dmPod.Port
+	node.LastProbeTime = now
+	if dmPod.State != domain.NodeStateOnline {
+		node.State = dmPod.State
+		node.LastError = "decision maker pod is not running"
+		return
+	}
+
+	status, err := svc.DMAdapter.ProbeDecisionMaker(ctx, dmPod)
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("probe decision maker %s", dmPod)
+		node.State = domain.NodeStateOffline
+		node.LastError = err.Error()
+		return
+	}
+	node.State = domain.NodeStateOnline
+	node.Health = status.Health
+	node.DMVersion = status.Version
+	node.LastContactTime = now
+	node.LastError = ""
+}
+
+func (svc *Service) nodeRegistrySettings() (interval, timeout time.Duration) {
+	interval = svc.nodeRegistryConfig.ProbeInterval
+	if interval <= 0 {
+		interval = defaultNodeProbeInterval
+	}
+	timeout = svc.nodeRegistryConfig.ProbeTimeout
+	if timeout <= 0 {
+		timeout = defaultNodeProbeTimeout
+	}
+	return interval, timeout
+}
+
+// RunNodeRegistry syncs the node registry on start, whenever the pod informer reports a change of a decision maker
+// pod and periodically. It blocks until ctx is done.
+func (svc *Service) RunNodeRegistry(ctx context.Context) error {
+	interval, timeout := svc.nodeRegistrySettings()
+
+	events, err := svc.K8SAdapter.SubscribePodEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribe pod events: %w", err)
+	}
+
+	sync := func() {
+		if err := svc.SyncNodeRegistry(ctx); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msg("sync node registry")
+		}
+	}
+
+	logger.Logger(ctx).Info().Msgf("starting node registry, probe interval:%s timeout:%s", interval, timeout)
+	sync()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Pod == nil || !svc.dmDiscovery.matchesPod(event.Pod) {
+				continue
+			}
+			logger.Logger(ctx).Debug().Msgf("decision maker pod event %d received for node %s", event.Type, event.Pod.NodeID)
+			sync()
+		case <-ticker.C:
+			sync()
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultOutboxPollInterval       = 5 * time.Second
+	defaultOutboxInitialBackoff     = time.Second
+	defaultOutboxMaxBackoff         = 5 * time.Minute
+	defaultOutboxMaxRetractAttempts = 20
+	// outboxClaimLease keeps a claimed intent from being delivered twice, it outlasts any delivery attempt and
+	// expires when the manager dies before recording the outcome
+	outboxClaimLease = time.Minute
+)
+
+// DeliverPendingIntents delivers every initialized or retracting intent whose next attempt is due. It holds strategyMu
+// so that no conflict resolution changes the intents while they are on their way to the decision makers.
+func (svc *Service) DeliverPendingIntents(ctx context.Context) error {
+	svc.strategyMu.Lock()
+	defer svc.strategyMu.Unlock()
+
+	opt := &domain.QueryIntentOptions{
+		States:    []domain.IntentState{domain.IntentStateInitialized, domain.IntentStateRetracting},
+		DueBefore: time.Now().UnixMilli(),
+	}
+	err := svc.Repo.QueryIntents(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("query pending intents: %w", err)
+	}
+	if len(opt.Result) == 0 {
+		return nil
+	}
+	logger.Logger(ctx).Debug().Msgf("delivering %d pending intents", len(opt.Result))
+	return svc.deliverIntents(ctx, opt.Result)
+}
+
+// RunIntentDeliveryWorker periodically delivers pending intents until ctx is done.
+func (svc *Service) RunIntentDeliveryWorker(ctx context.Context) error {
+	pollInterval := svc.outboxConfig.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultOutboxPollInterval
+	}
+
+	logger.Logger(ctx).Info().Msgf("starting intent delivery worker, poll interval:%s", pollInterval)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := svc.DeliverPendingIntents(ctx); err != nil {
+				logger.Logger(ctx).Warn().Err(err).Msg("deliver pending intents")
+			}
+		}
+	}
+}
+
+// deliverIntents pushes initialized intents to and retracts retracting intents from the decision makers
+// running on their nodes. The intents are claimed first, intents being delivered elsewhere are skipped.
+// Delivered intents are marked as sent, retracted intents are removed from the repository and failed
+// deliveries are recorded on the intents to be retried with exponential backoff.
+// Only repository errors are returned.
+func (svc *Service) deliverIntents(ctx context.Context, intents []*domain.ScheduleIntent) error {
+	pending := make([]*domain.ScheduleIntent, 0, len(intents))
+	for _, intent := range intents {
+		if intent.State == domain.IntentStateInitialized || intent.State == domain.IntentStateRetracting {
+			pending = append(pending, intent)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	now := time.Now()
+	claimed, err := svc.Repo.ClaimIntents(ctx, intentIDs(pending), now.UnixMilli(), now.Add(outboxClaimLease).UnixMilli())
+	if err != nil {
+		return fmt.Errorf("claim intents in repository: %w", err)
+	}
+
+	intentsByNode := make(map[string][]*domain.ScheduleIntent)
+	nodeIDs := make([]string, 0)
+	for _, intent := range claimed {
+		if _, exists := intentsByNode[intent.NodeID]; !exists {
+			nodeIDs = append(nodeIDs, intent.NodeID)
+		}
+		intentsByNode[intent.NodeID] = append(intentsByNode[intent.NodeID], intent)
+	}
+	if len(nodeIDs) == 0 {
+		return nil
+	}
+
+	dmQueryOpt := svc.dmDiscovery.queryOptions(nodeIDs)
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, dmQueryOpt)
+	if err != nil {
+		var errs []error
+		for _, nodeID := range nodeIDs {
+			errs = append(errs, svc.recordDeliveryFailure(ctx, intentsByNode[nodeID], fmt.Errorf("query decision maker pods: %w", err)))
+		}
+		return errors.Join(errs...)
+	}
+	logger.Logger(ctx).Debug().Msgf("found %d decision maker pods for scheduling intents", len(dms))
+
+	dmByNode := decisionMakersByNode(dms)
+
+	var errs []error
+	for _, nodeID := range nodeIDs {
+		nodeIntents := intentsByNode[nodeID]
+		dmPod, ok := dmByNode[nodeID]
+		if !ok {
+			nodeIntents, err = svc.dropRetractionsOfGoneNode(ctx, nodeID, nodeIntents)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, svc.recordDeliveryFailure(ctx, nodeIntents, fmt.Errorf("no decision maker pod found on node %q", nodeID)))
+			continue
+		}
+
+		sent := make([]*domain.ScheduleIntent, 0, len(nodeIntents))
+		retracted := make([]*domain.ScheduleIntent, 0)
+		for _, intent := range nodeIntents {
+			if intent.State == domain.IntentStateRetracting {
+				retracted = append(retracted, intent)
+			} else {
+				sent = append(sent, intent)
+			}
+		}
+
+		if len(retracted) > 0 {
+			errs = append(errs, svc.retractNodeIntents(ctx, dmPod, retracted))
+		}
+		if len(sent) > 0 {
+			errs = append(errs, svc.sendNodeIntents(ctx, dmPod, sent))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (svc *Service) sendNodeIntents(ctx context.Context, dmPod *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
+	results, err := svc.DMAdapter.SendSchedulingIntent(ctx, dmPod, intents)
+	if err != nil {
+		return svc.recordDeliveryFailure(ctx, intents, fmt.Errorf("send scheduling intents to decision maker %s: %w", dmPod.Host, err))
+	}
+	err = svc.Repo.MarkIntentsDelivered(ctx, intentIDs(intents))
+	if err != nil {
+		return fmt.Errorf("mark intents delivered in repository: %w", err)
+	}
+	for _, intent := range intents {
+		intent.State = domain.IntentStateSent
+		intent.Attempts++
+		intent.LastError = ""
+		intent.NextAttemptTime = 0
+	}
+	logger.Logger(ctx).Info().Msgf("sent %d scheduling intents to decision maker %s", len(intents), dmPod.Host)
+
+	if len(results) == 0 {
+		return nil
+	}
+	err = svc.Repo.BatchUpdateIntentsResult(ctx, results)
+	if err != nil {
+		return fmt.Errorf("update intents result in repository: %w", err)
+	}
+	resultByID := make(map[bson.ObjectID]*domain.ScheduleIntentResult, len(results))
+	for _, result := range results {
+		resultByID[result.IntentID] = result
+	}
+	for _, intent := range intents {
+		if result, ok := resultByID[intent.ID]; ok {
+			intent.State = result.State
+			intent.PIDCount = result.PIDCount
+			intent.Reason = result.Reason
+			if result.State != domain.IntentStateApplied {
+				logger.Logger(ctx).Warn().Msgf("decision maker %s could not apply intent %s of pod %s: %s", dmPod.Host, intent.ID.Hex(), intent.PodID, result.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+func (svc *Service) retractNodeIntents(ctx context.Context, dmPod *domain.DecisionMakerPod, intents []*domain.ScheduleIntent) error {
+	err := svc.DMAdapter.DeleteSchedulingIntent(ctx, dmPod, intents)
+	if err != nil {
+		return svc.recordDeliveryFailure(ctx, intents, fmt.Errorf("delete scheduling intents from decision maker %s: %w", dmPod.Host, err))
+	}
+	err = svc.Repo.DeleteIntents(ctx, intentIDs(intents))
+	if err != nil {
+		return fmt.Errorf("delete retracted intents from repository: %w", err)
+	}
+	logger.Logger(ctx).Info().Msgf("retracted %d scheduling intents from decision maker %s", len(intents), dmPod.Host)
+	return nil
+}
+
+// dropRetractionsOfGoneNode removes the retracting intents of a node no pod runs on anymore, the node is gone
+// together with its decision maker and nothing is left to retract. It returns the intents still to be delivered.
+func (svc *Service) dropRetractionsOfGoneNode(ctx context.Context, nodeID string, intents []*domain.ScheduleIntent) ([]*domain.ScheduleIntent, error) {
+	remaining := make([]*domain.ScheduleIntent, 0, len(intents))
+	retracted := make([]*domain.ScheduleIntent, 0)
+	for _, intent := range intents {
+		if intent.State == domain.IntentStateRetracting {
+			retracted = append(retracted, intent)
+		} else {
+			remaining = append(remaining, intent)
+		}
+	}
+	if len(retracted) == 0 {
+		return intents, nil
+	}
+	// every node runs daemonset pods, the informer lists none once the node is deleted
+	pods, err := svc.K8SAdapter.QueryPods(ctx, &domain.QueryPodsOptions{
+		FieldFilter: &domain.PodFieldFilter{NodeNames: []string{nodeID}},
+	})
+	if err != nil {
+		logger.Logger(ctx).Warn().Err(err).Msgf("query pods of node %s", nodeID)
+		return intents, nil
+	}
+	if len(pods) > 0 {
+		return intents, nil
+	}
+	err = svc.Repo.DeleteIntents(ctx, intentIDs(retracted))
+	if err != nil {
+		return nil, fmt.Errorf("delete retracting intents of gone node %s from repository: %w", nodeID, err)
+	}
+	logger.Logger(ctx).Info().Msgf("dropped %d retracting intents of gone node %s", len(retracted), nodeID)
+	return remaining, nil
+}
+
+// recordDeliveryFailure stores the failure on the intents and schedules their next attempt. Retractions that
+// failed max retract attempts times are marked failed and no longer retried.
+func (svc *Service) recordDeliveryFailure(ctx context.Context, intents []*domain.ScheduleIntent, cause error) error {
+	if len(intents) == 0 {
+		return nil
+	}
+	attempts := 0
+	for _, intent := range intents {
+		attempts = max(attempts, intent.Attempts+1)
+	}
+	backoff := svc.deliveryBackoff(attempts)
+	nextAttemptTime := time.Now().Add(backoff).UnixMilli()
+	logger.Logger(ctx).Warn().Err(cause).Msgf("deliver %d scheduling intents failed on attempt %d, retry in %s", len(intents), attempts, backoff)
+
+	err := svc.Repo.MarkIntentsDeliveryFailed(ctx, intentIDs(intents), cause.Error(), nextAttemptTime)
+	if err != nil {
+		return fmt.Errorf("mark intents delivery failed in repository: %w", err)
+	}
+	exhausted := make([]*domain.ScheduleIntent, 0)
+	for _, intent := range intents {
+		intent.Attempts++
+		intent.LastError = cause.Error()
+		intent.NextAttemptTime = nextAttemptTime
+		if intent.State == domain.IntentStateRetracting && intent.Attempts >= svc.maxRetractAttempts() {
+			exhausted = append(exhausted, intent)
+		}
+	}
+	if len(exhausted) == 0 {
+		return nil
+	}
+	err = svc.Repo.BatchUpdateIntentsState(ctx, intentIDs(exhausted), domain.IntentStateFailed)
+	if err != nil {
+		return fmt.Errorf("mark exhausted retractions failed in repository: %w", err)
+	}
+	for _, intent := range exhausted {
+		intent.State = domain.IntentStateFailed
+	}
+	logger.Logger(ctx).Warn().Msgf("gave up retracting %d scheduling intents after %d attempts", len(exhausted), svc.maxRetractAttempts())
+	return nil
+}
+
+func (svc *Service) maxRetractAttempts() int {
+	if svc.outboxConfig.MaxRetractAttempts <= 0 {
+		return defaultOutboxMaxRetractAttempts
+	}
+	return svc.outboxConfig.MaxRetractAttempts
+}
+
+// deliveryBackoff doubles the initial backoff for every failed attempt, capped at the max backoff.
+func (svc *Service) deliveryBackoff(attempts int) time.Duration {
+	backoff := svc.outboxConfig.InitialBackoff
+	if backoff <= 0 {
+		backoff = defaultOutboxInitialBackoff
+	}
+	maxBackoff := svc.outboxConfig.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultOutboxMaxBackoff
+	}
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultReconcileDebounce       = 2 * time.Second
+	defaultReconcileResyncInterval = 5 * time.Minute
+)
+
+// ReconcileScheduleStrategies re-evaluates the pods selected by every stored strategy,
+// creates or retires their intents accordingly and pushes the delta to the decision makers.
+func (svc *Service) ReconcileScheduleStrategies(ctx context.Context) error {
+	opt := &domain.QueryStrategyOptions{}
+	err := svc.Repo.QueryStrategies(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("query strategies: %w", err)
+	}
+
+	var errs []error
+	for _, strategy := range opt.Result {
+		err = svc.reconcileScheduleStrategy(ctx, strategy.ID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// reconcileScheduleStrategy syncs the intents of a single strategy. The strategy is loaded again under strategyMu,
+// it may have been updated or deleted since the strategies were listed.
+func (svc *Service) reconcileScheduleStrategy(ctx context.Context, strategyID bson.ObjectID) error {
+	svc.strategyMu.Lock()
+	defer svc.strategyMu.Unlock()
+
+	opt := &domain.QueryStrategyOptions{IDs: []bson.ObjectID{strategyID}}
+	err := svc.Repo.QueryStrategies(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("query strategy %s: %w", strategyID.Hex(), err)
+	}
+	if len(opt.Result) == 0 {
+		return nil
+	}
+	strategy := opt.Result[0]
+	pods, err := svc.queryStrategyPods(ctx, strategy)
+	if err != nil {
+		return fmt.Errorf("query pods of strategy %s: %w", strategyID.Hex(), err)
+	}
+	err = svc.syncStrategyIntents(ctx, strategy, pods, false)
+	if err != nil {
+		return fmt.Errorf("sync intents of strategy %s: %w", strategyID.Hex(), err)
+	}
+	return nil
+}
+
+// RunStrategyReconciler reconciles all strategies on start, after pod events reported by the K8SAdapter
+// and periodically. Bursts of pod events are coalesced into a single pass. It blocks until ctx is done.
+func (svc *Service) RunStrategyReconciler(ctx context.Context) error {
+	debounce := svc.reconcilerConfig.Debounce
+	if debounce <= 0 {
+		debounce = defaultReconcileDebounce
+	}
+	resyncInterval := svc.reconcilerConfig.ResyncInterval
+	if resyncInterval <= 0 {
+		resyncInterval = defaultReconcileResyncInterval
+	}
+
+	events, err := svc.K8SAdapter.SubscribePodEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("subscribe pod events: %w", err)
+	}
+
+	reconcile := func() {
+		start := time.Now()
+		if err := svc.ReconcileScheduleStrategies(ctx); err != nil {
+			logger.Logger(ctx).Warn().Err(err).Msg("reconcile schedule strategies")
+			return
+		}
+		logger.Logger(ctx).Debug().Msgf("reconciled schedule strategies in %s", time.Since(start))
+	}
+
+	logger.Logger(ctx).Info().Msgf("starting strategy reconciler, debounce:%s resync interval:%s", debounce, resyncInterval)
+	reconcile()
+
+	timer := time.NewTimer(resyncInterval)
+	defer timer.Stop()
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if event.Pod != nil {
+				logger.Logger(ctx).Debug().Msgf("pod event %d received for %s/%s", event.Type, event.Pod.K8SNamespace, event.Pod.Name)
+			}
+			if !pending {
+				pending = true
+				timer.Reset(debounce)
+			}
+		case <-timer.C:
+			pending = false
+			reconcile()
+			timer.Reset(resyncInterval)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"github.com/Gthulhu/api/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// strategyConflicts returns the existing strategies selecting some of the pods, together with whether they take
+// precedence over strategy on them
+func (svc *Service) strategyConflicts(ctx context.Context, strategy *domain.ScheduleStrategy, pods []*domain.Pod) ([]*domain.StrategyConflict, error) {
+	strategyIDsByPod, err := svc.strategyIDsByPod(ctx, pods)
+	if err != nil {
+		return nil, err
+	}
+	conflictByStrategy := make(map[bson.ObjectID]*domain.StrategyConflict)
+	conflicts := []*domain.StrategyConflict{}
+	for _, pod := range pods {
+		for _, strategyID := range strategyIDsByPod[pod.PodID] {
+			if strategyID == strategy.ID {
+				continue
+			}
+			conflict, ok := conflictByStrategy[strategyID]
+			if !ok {
+				conflict = &domain.StrategyConflict{StrategyID: strategyID}
+				conflictByStrategy[strategyID] = conflict
+				conflicts = append(conflicts, conflict)
+			}
+			conflict.PodIDs = append(conflict.PodIDs, pod.PodID)
+		}
+	}
+	if len(conflicts) == 0 {
+		return conflicts, nil
+	}
+
+	strategyOpt := &domain.QueryStrategyOptions{}
+	for _, conflict := range conflicts {
+		strategyOpt.IDs = append(strategyOpt.IDs, conflict.StrategyID)
+	}
+	err = svc.Repo.QueryStrategies(ctx, strategyOpt)
+	if err != nil {
+		return nil, fmt.Errorf("query conflicting strategies: %w", err)
+	}
+	for _, existing := range strategyOpt.Result {
+		conflictByStrategy[existing.ID].Wins = domain.CompareStrategyPrecedence(existing, strategy) > 0
+	}
+	return conflicts, nil
+}
+
+// resolveIntentConflicts lets the strategy of highest precedence win every given pod. The decision makers hold a
+// single intent per pod, so only the winning intent is delivered and the others are marked overridden. A retraction
+// is dropped while another intent holds the pod, delivering it would remove the winner from the decision maker.
+// Intents a delivery holds or whose state changed since they were read are left as they are.
+// It returns the intents to deliver: those of pending still to be delivered plus the intents newly winning a pod.
+func (svc *Service) resolveIntentConflicts(ctx context.Context, podIDs []string, pending []*domain.ScheduleIntent) ([]*domain.ScheduleIntent, error) {
+	if len(podIDs) == 0 {
+		return pending, nil
+	}
+	intentOpt := &domain.QueryIntentOptions{PodIDs: podIDs}
+	err := svc.Repo.QueryIntents(ctx, intentOpt)
+	if err != nil {
+		return nil, fmt.Errorf("query intents of pods: %w", err)
+	}
+
+	strategyOpt := &domain.QueryStrategyOptions{}
+	intentsByPod := make(map[string][]*domain.ScheduleIntent)
+	seen := make(map[bson.ObjectID]struct{})
+	for _, intent := range intentOpt.Result {
+		intentsByPod[intent.PodID] = append(intentsByPod[intent.PodID], intent)
+		if _, ok := seen[intent.StrategyID]; ok || intent.State == domain.IntentStateRetracting {
+			continue
+		}
+		seen[intent.StrategyID] = struct{}{}
+		strategyOpt.IDs = append(strategyOpt.IDs, intent.StrategyID)
+	}
+	strategies := make(map[bson.ObjectID]*domain.ScheduleStrategy, len(strategyOpt.IDs))
+	if len(strategyOpt.IDs) > 0 {
+		err = svc.Repo.QueryStrategies(ctx, strategyOpt)
+		if err != nil {
+			return nil, fmt.Errorf("query strategies of pods: %w", err)
+		}
+		for _, strategy := range strategyOpt.Result {
+			strategies[strategy.ID] = strategy
+		}
+	}
+
+	pendingIDs := make(map[bson.ObjectID]struct{}, len(pending))
+	for _, intent := range pending {
+		pendingIDs[intent.ID] = struct{}{}
+	}
+	deliver := make([]*domain.ScheduleIntent, 0, len(pending))
+	resolutions := make([]*domain.IntentResolution, 0)
+	resolved := make(map[bson.ObjectID]*domain.ScheduleIntent)
+	resolve := func(intent *domain.ScheduleIntent, resolution *domain.IntentResolution) {
+		resolution.IntentID = intent.ID
+		resolution.FromState = intent.State
+		resolutions = append(resolutions, resolution)
+		resolved[intent.ID] = intent
+	}
+	for _, intents := range intentsByPod {
+		var winner *domain.ScheduleStrategy
+		for _, intent := range intents {
+			strategy, ok := strategies[intent.StrategyID]
+			if !ok || intent.State == domain.IntentStateRetracting {
+				continue
+			}
+			if winner == nil || domain.CompareStrategyPrecedence(strategy, winner) > 0 {
+				winner = strategy
+			}
+		}
+
+		for _, intent := range intents {
+			_, isPending := pendingIDs[intent.ID]
+			switch {
+			case intent.State == domain.IntentStateRetracting && winner != nil:
+				resolve(intent, &domain.IntentResolution{Superseded: true})
+			case winner == nil:
+				if isPending {
+					deliver = append(deliver, intent)
+				}
+			case intent.StrategyID == winner.ID:
+				switch {
+				case intent.State == domain.IntentStateOverridden:
+					resolve(intent, &domain.IntentResolution{State: domain.IntentStateInitialized, WinningStrategyID: winner.ID})
+				case intent.WinningStrategyID != winner.ID:
+					resolve(intent, &domain.IntentResolution{State: intent.State, WinningStrategyID: winner.ID})
+				}
+				if intent.State == domain.IntentStateInitialized && isPending {
+					deliver = append(deliver, intent)
+				}
+			default:
+				if intent.State != domain.IntentStateOverridden || intent.WinningStrategyID != winner.ID {
+					resolve(intent, &domain.IntentResolution{State: domain.IntentStateOverridden, WinningStrategyID: winner.ID})
+				}
+			}
+		}
+	}
+
+	applied, err := svc.Repo.ResolveIntents(ctx, resolutions, time.Now().UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("resolve intents in repository: %w", err)
+	}
+	if len(applied) < len(resolutions) {
+		logger.Logger(ctx).Warn().Msgf("%d of %d intent resolutions skipped, the intents are being delivered or changed meanwhile", len(resolutions)-len(applied), len(resolutions))
+	}
+	appliedIDs := make(map[bson.ObjectID]struct{}, len(applied))
+	for _, id := range applied {
+		appliedIDs[id] = struct{}{}
+	}
+	for _, resolution := range resolutions {
+		if _, ok := appliedIDs[resolution.IntentID]; !ok || resolution.Superseded {
+			continue
+		}
+		intent := resolved[resolution.IntentID]
+		intent.State = resolution.State
+		intent.WinningStrategyID = resolution.WinningStrategyID
+		if resolution.FromState == domain.IntentStateOverridden && resolution.State == domain.IntentStateInitialized {
+			intent.NextAttemptTime = 0
+			deliver = append(deliver, intent)
+		}
+	}
+	return deliver, nil
+}
+
+// intentPodIDs returns the distinct pods of the intents and of the pods
+func intentPodIDs(intents []*domain.ScheduleIntent, pods []*domain.Pod) []string {
+	seen := make(map[string]struct{}, len(intents)+len(pods))
+	podIDs := make([]string, 0, len(intents)+len(pods))
+	add := func(podID string) {
+		if _, ok := seen[podID]; !ok {
+			seen[podID] = struct{}{}
+			podIDs = append(podIDs, podID)
+		}
+	}
+	for _, intent := range intents {
+		add(intent.PodID)
+	}
+	for _, pod := range pods {
+		add(pod.PodID)
+	}
+	return podIDs
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Gthulhu/api/manager/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PreviewScheduleStrategy resolves the pods the strategy would select, probes the decision makers of their nodes and
+// looks up the existing strategies targeting them. Nothing is written to the repository.
+func (svc *Service) PreviewScheduleStrategy(ctx context.Context, strategy *domain.ScheduleStrategy) (*domain.StrategyPreview, error) {
+	err := validateStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := svc.queryStrategyPods(ctx, strategy)
+	if err != nil {
+		return nil, err
+	}
+	preview := &domain.StrategyPreview{
+		MatchedPods: len(pods),
+		Nodes:       []*domain.StrategyPreviewNode{},
+		Strategies:  []*domain.ScheduleStrategy{},
+	}
+	if len(pods) == 0 {
+		return preview, nil
+	}
+
+	strategyIDsByPod, err := svc.strategyIDsByPod(ctx, pods)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[string]map[string][]*domain.StrategyPreviewPod)
+	targeting := make(map[bson.ObjectID]struct{})
+	for _, pod := range pods {
+		namespaces, ok := nodes[pod.NodeID]
+		if !ok {
+			namespaces = make(map[string][]*domain.StrategyPreviewPod)
+			nodes[pod.NodeID] = namespaces
+		}
+		strategyIDs := strategyIDsByPod[pod.PodID]
+		namespaces[pod.K8SNamespace] = append(namespaces[pod.K8SNamespace], &domain.StrategyPreviewPod{Pod: pod, StrategyIDs: strategyIDs})
+		for _, id := range strategyIDs {
+			targeting[id] = struct{}{}
+		}
+	}
+
+	for nodeID, namespaces := range nodes {
+		node := &domain.StrategyPreviewNode{NodeID: nodeID}
+		for namespace, previewPods := range namespaces {
+			slices.SortFunc(previewPods, func(a, b *domain.StrategyPreviewPod) int {
+				return strings.Compare(a.Pod.Name, b.Pod.Name)
+			})
+			node.Namespaces = append(node.Namespaces, &domain.StrategyPreviewNamespace{K8sNamespace: namespace, Pods: previewPods})
+		}
+		slices.SortFunc(node.Namespaces, func(a, b *domain.StrategyPreviewNamespace) int {
+			return strings.Compare(a.K8sNamespace, b.K8sNamespace)
+		})
+		preview.Nodes = append(preview.Nodes, node)
+	}
+	slices.SortFunc(preview.Nodes, func(a, b *domain.StrategyPreviewNode) int {
+		return strings.Compare(a.NodeID, b.NodeID)
+	})
+
+	err = svc.probePreviewNodes(ctx, preview.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targeting) > 0 {
+		strategyOpt := &domain.QueryStrategyOptions{}
+		for id := range targeting {
+			strategyOpt.IDs = append(strategyOpt.IDs, id)
+		}
+		err = svc.Repo.QueryStrategies(ctx, strategyOpt)
+		if err != nil {
+			return nil, fmt.Errorf("query strategies targeting the matched pods: %w", err)
+		}
+		preview.Strategies = strategyOpt.Result
+	}
+	return preview, nil
+}
+
+// strategyIDsByPod returns the strategies with an intent for each of the pods, intents being retracted are ignored
+func (svc *Service) strategyIDsByPod(ctx context.Context, pods []*domain.Pod) (map[string][]bson.ObjectID, error) {
+	intentOpt := &domain.QueryIntentOptions{
+		PodIDs: make([]string, 0, len(pods)),
+	}
+	for _, pod := range pods {
+		intentOpt.PodIDs = append(intentOpt.PodIDs, pod.PodID)
+	}
+	err := svc.Repo.QueryIntents(ctx, intentOpt)
+	if err != nil {
+		return nil, fmt.Errorf("query intents of the matched pods: %w", err)
+	}
+	strategyIDsByPod := make(map[string][]bson.ObjectID, len(intentOpt.Result))
+	for _, intent := range intentOpt.Result {
+		if intent.State == domain.IntentStateRetracting || slices.Contains(strategyIDsByPod[intent.PodID], intent.StrategyID) {
+			continue
+		}
+		strategyIDsByPod[intent.PodID] = append(strategyIDsByPod[intent.PodID], intent.StrategyID)
+	}
+	return strategyIDsByPod, nil
+}
+
+// probePreviewNodes records for every node whether its decision maker answers a probe
+func (svc *Service) probePreviewNodes(ctx context.Context, nodes []*domain.StrategyPreviewNode) error {
+	nodeIDs := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeIDs = append(nodeIDs, node.NodeID)
+	}
+	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, svc.dmDiscovery.queryOptions(nodeIDs))
+	if err != nil {
+		return fmt.Errorf("query decision maker pods: %w", err)
+	}
+	dmByNode := decisionMakersByNode(dms)
+
+	_, timeout := svc.nodeRegistrySettings()
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		dmPod, ok := dmByNode[node.NodeID]
+		switch {
+		case !ok:
+			node.DecisionMakerError = "no decision maker pod runs on the node"
+			continue
+		case dmPod.State != domain.NodeStateOnline:
+			node.DecisionMakerError = "decision maker pod is not running"
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			_, err := svc.DMAdapter.ProbeDecisionMaker(probeCtx, dmPod)
+			if err != nil {
+				node.DecisionMakerError = err.Error()
+				return
+			}
+			node.DecisionMakerReachable = true
+		}()
+	}
+	wg.Wait()
+	return nil
+}
@@ -3,7 +3,11 @@ package service
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/Gthulhu/api/manager/domain"
 	"github.com/Gthulhu/api/manager/errs"
@@ -12,88 +16,196 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain.Claims, strategy *domain.ScheduleStrategy) error {
+// CreateScheduleStrategy stores the strategy with an intent for every pod it selects and pushes the winning intents
+// to the decision makers. It returns the existing strategies selecting the same pods, which are rejected with a
+// conflict when the strategy config asks for it.
+func (svc *Service) CreateScheduleStrategy(ctx context.Context, operator *domain.Claims, strategy *domain.ScheduleStrategy) ([]*domain.StrategyConflict, error) {
 	operatorID, err := operator.GetBsonObjectUID()
 	if err != nil {
-		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
+		return nil, errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
-	queryOpt := &domain.QueryPodsOptions{
-		K8SNamespace:   strategy.K8sNamespace,
-		LabelSelectors: strategy.LabelSelectors,
-		CommandRegex:   strategy.CommandRegex,
+	err = validateStrategy(strategy)
+	if err != nil {
+		return nil, err
 	}
-	pods, err := svc.K8SAdapter.QueryPods(ctx, queryOpt)
+	svc.strategyMu.Lock()
+	defer svc.strategyMu.Unlock()
+
+	queryOpt := strategyPodsQueryOptions(strategy)
+	pods, err := svc.queryStrategyPods(ctx, strategy)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(pods) == 0 {
-		return errs.NewHTTPStatusError(http.StatusNotFound, "no pods match the strategy criteria", fmt.Errorf("no pods found for the given namespaces and label selectors, opts:%+v", queryOpt))
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, "no pods match the strategy criteria", fmt.Errorf("no pods found for the given namespaces and label selectors, opts:%+v", queryOpt))
 	}
 
 	logger.Logger(ctx).Debug().Msgf("found %d pods matching the strategy criteria", len(pods))
 
 	strategy.BaseEntity = domain.NewBaseEntity(&operatorID, &operatorID)
 
+	conflicts, err := svc.strategyConflicts(ctx, strategy, pods)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		strategyIDs := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			strategyIDs = append(strategyIDs, conflict.StrategyID.Hex())
+		}
+		if svc.strategyConfig.RejectConflicts {
+			msg := fmt.Sprintf("pods are already selected by strategies %s", strings.Join(strategyIDs, ","))
+			return conflicts, errs.NewHTTPStatusError(http.StatusConflict, msg, errors.New(msg))
+		}
+		logger.Logger(ctx).Warn().Msgf("new strategy selects pods already selected by strategies %s", strings.Join(strategyIDs, ","))
+	}
+
 	intents := make([]*domain.ScheduleIntent, 0, len(pods))
-	nodeIDsMap := make(map[string]struct{})
-	nodeIDs := make([]string, 0)
 	for _, pod := range pods {
 		intent := domain.NewScheduleIntent(strategy, pod)
 		intents = append(intents, &intent)
-		if _, exists := nodeIDsMap[pod.NodeID]; !exists {
-			nodeIDsMap[pod.NodeID] = struct{}{}
-			nodeIDs = append(nodeIDs, pod.NodeID)
-		}
 	}
 
 	err = svc.Repo.InsertStrategyAndIntents(ctx, strategy, intents)
 	if err != nil {
-		return fmt.Errorf("insert strategy and intents into repository: %w", err)
+		return nil, fmt.Errorf("insert strategy and intents into repository: %w", err)
 	}
 
-	dmLabel := domain.LabelSelector{
-		Key:   "app",
-		Value: "decisionmaker",
+	pending, err := svc.resolveIntentConflicts(ctx, intentPodIDs(intents, nil), intents)
+	if err != nil {
+		return nil, err
 	}
+	return conflicts, svc.deliverIntents(ctx, pending)
+}
 
-	dmQueryOpt := &domain.QueryDecisionMakerPodsOptions{
-		DecisionMakerLabel: dmLabel,
-		NodeIDs:            nodeIDs,
+// UpdateScheduleStrategy replaces the criteria of an existing strategy, re-resolves the pods it selects
+// and pushes the resulting intent delta to the affected decision makers.
+func (svc *Service) UpdateScheduleStrategy(ctx context.Context, operator *domain.Claims, strategyID string, strategy *domain.ScheduleStrategy) error {
+	operatorID, err := operator.GetBsonObjectUID()
+	if err != nil {
+		return errors.WithMessagef(err, "invalid operator ID %s", operator.UID)
 	}
-	dms, err := svc.K8SAdapter.QueryDecisionMakerPods(ctx, dmQueryOpt)
+	err = validateStrategy(strategy)
 	if err != nil {
 		return err
 	}
-	if len(dms) == 0 {
-		logger.Logger(ctx).Warn().Msgf("no decision maker pods found for scheduling intents, opts:%+v", dmQueryOpt)
+	svc.strategyMu.Lock()
+	defer svc.strategyMu.Unlock()
+
+	current, err := svc.getStrategyByID(ctx, strategyID)
+	if err != nil {
+		return err
+	}
+
+	pods, err := svc.queryStrategyPods(ctx, strategy)
+	if err != nil {
+		return err
+	}
+	logger.Logger(ctx).Debug().Msgf("found %d pods matching the updated strategy criteria", len(pods))
+
+	strategy.BaseEntity = current.BaseEntity
+	strategy.UpdaterID = operatorID
+	err = svc.Repo.UpdateStrategy(ctx, strategy)
+	if err != nil {
+		return fmt.Errorf("update strategy in repository: %w", err)
+	}
+
+	// the weight or namespaces may have changed, the precedence on every selected pod is resolved again
+	return svc.syncStrategyIntents(ctx, strategy, pods, true)
+}
+
+// syncStrategyIntents brings the stored intents of the strategy in line with the given selected pods
+// and pushes the delta to the affected decision makers. The caller holds strategyMu. Conflicts with other strategies are resolved on the pods
+// of the delta, or on every selected pod with resolveAll.
+func (svc *Service) syncStrategyIntents(ctx context.Context, strategy *domain.ScheduleStrategy, pods []*domain.Pod, resolveAll bool) error {
+	intentOpt := &domain.QueryIntentOptions{
+		StrategyIDs: []bson.ObjectID{strategy.ID},
+	}
+	err := svc.Repo.QueryIntents(ctx, intentOpt)
+	if err != nil {
+		return fmt.Errorf("query intents of strategy %s: %w", strategy.ID.Hex(), err)
+	}
+
+	diff := diffScheduleIntents(strategy, pods, intentOpt.Result)
+	if len(diff.Added) == 0 && len(diff.Changed) == 0 && len(diff.Removed) == 0 && !resolveAll {
 		return nil
 	}
+	err = svc.Repo.InsertIntents(ctx, diff.Added)
+	if err != nil {
+		return fmt.Errorf("insert intents into repository: %w", err)
+	}
+	err = svc.Repo.UpdateIntents(ctx, diff.Changed)
+	if err != nil {
+		return fmt.Errorf("update intents in repository: %w", err)
+	}
+	err = svc.markIntentsRetracting(ctx, diff.Removed)
+	if err != nil {
+		return err
+	}
+	logger.Logger(ctx).Info().Msgf("strategy %s synced, %d intents added, %d changed, %d removed", strategy.ID.Hex(), len(diff.Added), len(diff.Changed), len(diff.Removed))
 
-	logger.Logger(ctx).Debug().Msgf("found %d decision maker pods for scheduling intents", len(dms))
+	pending := make([]*domain.ScheduleIntent, 0, len(diff.Added)+len(diff.Changed)+len(diff.Removed))
+	pending = append(pending, diff.Added...)
+	pending = append(pending, diff.Changed...)
+	pending = append(pending, diff.Removed...)
+	var selected []*domain.Pod
+	if resolveAll {
+		selected = pods
+	}
+	pending, err = svc.resolveIntentConflicts(ctx, intentPodIDs(pending, selected), pending)
+	if err != nil {
+		return err
+	}
+	return svc.deliverIntents(ctx, pending)
+}
 
-	nodeIDIntentsMap := make(map[string][]*domain.ScheduleIntent)
-	nodeIDIntentIDsMap := make(map[string][]bson.ObjectID)
-	nodeIDDMap := make(map[string]*domain.DecisionMakerPod)
-	for _, dmPod := range dms {
-		for _, intent := range intents {
-			if intent.NodeID == dmPod.NodeID {
-				nodeIDIntentIDsMap[dmPod.Host] = append(nodeIDIntentIDsMap[dmPod.Host], intent.ID)
-				nodeIDIntentsMap[dmPod.Host] = append(nodeIDIntentsMap[dmPod.Host], intent)
-				nodeIDDMap[dmPod.Host] = dmPod
-			}
-		}
+// DeleteScheduleStrategy removes the strategy from the repository and retracts every intent of it
+// from the decision makers.
+func (svc *Service) DeleteScheduleStrategy(ctx context.Context, operator *domain.Claims, strategyID string) error {
+	svc.strategyMu.Lock()
+	defer svc.strategyMu.Unlock()
+
+	strategy, err := svc.getStrategyByID(ctx, strategyID)
+	if err != nil {
+		return err
 	}
-	for host, intents := range nodeIDIntentsMap {
-		dmPod := nodeIDDMap[host]
-		err = svc.DMAdapter.SendSchedulingIntent(ctx, dmPod, intents)
-		if err != nil {
-			return fmt.Errorf("send scheduling intents to decision maker %s: %w", host, err)
-		}
-		err = svc.Repo.BatchUpdateIntentsState(ctx, nodeIDIntentIDsMap[host], domain.IntentStateSent)
-		if err != nil {
-			return fmt.Errorf("insert strategy and intents into repository: %w", err)
-		}
-		logger.Logger(ctx).Info().Msgf("sent %d scheduling intents to decision maker %s", len(intents), host)
+	intentOpt := &domain.QueryIntentOptions{
+		StrategyIDs: []bson.ObjectID{strategy.ID},
+	}
+	err = svc.Repo.QueryIntents(ctx, intentOpt)
+	if err != nil {
+		return fmt.Errorf("query intents of strategy %s: %w", strategyID, err)
+	}
+
+	err = svc.markIntentsRetracting(ctx, intentOpt.Result)
+	if err != nil {
+		return err
+	}
+	err = svc.Repo.DeleteStrategy(ctx, strategy.ID)
+	if err != nil {
+		return fmt.Errorf("delete strategy from repository: %w", err)
+	}
+	logger.Logger(ctx).Info().Msgf("strategy %s deleted by %s, retracting %d intents", strategyID, operator.UID, len(intentOpt.Result))
+
+	pending, err := svc.resolveIntentConflicts(ctx, intentPodIDs(intentOpt.Result, nil), intentOpt.Result)
+	if err != nil {
+		return err
+	}
+	return svc.deliverIntents(ctx, pending)
+}
+
+// markIntentsRetracting flags the intents to be retracted from their decision makers,
+// they are removed from the repository once the retraction is delivered.
+func (svc *Service) markIntentsRetracting(ctx context.Context, intents []*domain.ScheduleIntent) error {
+	if len(intents) == 0 {
+		return nil
+	}
+	err := svc.Repo.BatchUpdateIntentsState(ctx, intentIDs(intents), domain.IntentStateRetracting)
+	if err != nil {
+		return fmt.Errorf("mark intents retracting in repository: %w", err)
+	}
+	for _, intent := range intents {
+		intent.State = domain.IntentStateRetracting
 	}
 	return nil
 }
@@ -105,3 +217,157 @@ func (svc *Service) ListScheduleStrategies(ctx context.Context, filterOpts *doma
 func (svc *Service) ListScheduleIntents(ctx context.Context, filterOpts *domain.QueryIntentOptions) error {
 	return svc.Repo.QueryIntents(ctx, filterOpts)
 }
+
+func (svc *Service) getStrategyByID(ctx context.Context, strategyID string) (*domain.ScheduleStrategy, error) {
+	id, err := bson.ObjectIDFromHex(strategyID)
+	if err != nil {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "invalid strategy ID", errors.WithMessagef(err, "invalid strategy ID %s", strategyID))
+	}
+	opt := &domain.QueryStrategyOptions{
+		IDs: []bson.ObjectID{id},
+	}
+	err = svc.Repo.QueryStrategies(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+	if len(opt.Result) == 0 {
+		return nil, errs.NewHTTPStatusError(http.StatusNotFound, "strategy not found", fmt.Errorf("strategy with ID %s not found", strategyID))
+	}
+	return opt.Result[0], nil
+}
+
+// strategyPodsQueryOptions selects pods by namespace, labels and pod fields only. The command regex is matched by the
+// decision makers against the real processes of each pod, container specs often omit the command entirely.
+func strategyPodsQueryOptions(strategy *domain.ScheduleStrategy) *domain.QueryPodsOptions {
+	return &domain.QueryPodsOptions{
+		K8SNamespace:     strategy.K8sNamespace,
+		LabelSelectors:   strategy.LabelSelectors,
+		MatchExpressions: strategy.MatchExpressions,
+		FieldFilter:      strategy.FieldFilter,
+	}
+}
+
+// queryStrategyPods returns the pods the strategy selects, selectors rejected by kubernetes are a bad request
+func (svc *Service) queryStrategyPods(ctx context.Context, strategy *domain.ScheduleStrategy) ([]*domain.Pod, error) {
+	pods, err := svc.K8SAdapter.QueryPods(ctx, strategyPodsQueryOptions(strategy))
+	if errors.Is(err, domain.ErrInvalidSelector) {
+		return nil, errs.NewHTTPStatusError(http.StatusBadRequest, "invalid pod selector", err)
+	}
+	return pods, err
+}
+
+var (
+	podQOSClasses = []string{"Guaranteed", "Burstable", "BestEffort"}
+	podPhases     = []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"}
+)
+
+// validateStrategy rejects command regexes that do not compile, malformed match expressions and unknown pod fields
+func validateStrategy(strategy *domain.ScheduleStrategy) error {
+	err := validateCommandRegex(strategy.CommandRegex)
+	if err != nil {
+		return err
+	}
+	for _, expression := range strategy.MatchExpressions {
+		if expression.Key == "" {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, "match expression without key", nil)
+		}
+		switch expression.Operator {
+		case domain.MatchOperatorIn, domain.MatchOperatorNotIn:
+			if len(expression.Values) == 0 {
+				return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("match expression %s %s requires values", expression.Key, expression.Operator), nil)
+			}
+		case domain.MatchOperatorExists, domain.MatchOperatorDoesNotExist:
+			if len(expression.Values) > 0 {
+				return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("match expression %s %s takes no values", expression.Key, expression.Operator), nil)
+			}
+		default:
+			return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("unknown operator %q of match expression %s", expression.Operator, expression.Key), nil)
+		}
+	}
+	if strategy.FieldFilter == nil {
+		return nil
+	}
+	for _, qosClass := range strategy.FieldFilter.QOSClasses {
+		if !slices.Contains(podQOSClasses, qosClass) {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("unknown QoS class %q, expected one of %s", qosClass, strings.Join(podQOSClasses, ",")), nil)
+		}
+	}
+	for _, phase := range strategy.FieldFilter.Phases {
+		if !slices.Contains(podPhases, phase) {
+			return errs.NewHTTPStatusError(http.StatusBadRequest, fmt.Sprintf("unknown pod phase %q, expected one of %s", phase, strings.Join(podPhases, ",")), nil)
+		}
+	}
+	return nil
+}
+
+func validateCommandRegex(commandRegex string) error {
+	if commandRegex == "" {
+		return nil
+	}
+	_, err := regexp.Compile(commandRegex)
+	if err != nil {
+		return errs.NewHTTPStatusError(http.StatusBadRequest, "invalid command regex", errors.WithMessagef(err, "compile command regex %q", commandRegex))
+	}
+	return nil
+}
+
+// intentDiff describes how the stored intents of a strategy have to change to match the pods it currently selects.
+type intentDiff struct {
+	Added   []*domain.ScheduleIntent
+	Changed []*domain.ScheduleIntent
+	Removed []*domain.ScheduleIntent
+}
+
+// diffScheduleIntents compares the stored intents of a strategy with the pods it currently selects.
+// Stored intents whose content changed, or which are being retracted while their pod is selected again,
+// are updated in place and reset to the initialized state.
+func diffScheduleIntents(strategy *domain.ScheduleStrategy, pods []*domain.Pod, stored []*domain.ScheduleIntent) intentDiff {
+	storedByPodID := make(map[string]*domain.ScheduleIntent, len(stored))
+	for _, intent := range stored {
+		storedByPodID[intent.PodID] = intent
+	}
+
+	diff := intentDiff{}
+	for _, pod := range pods {
+		desired := domain.NewScheduleIntent(strategy, pod)
+		current, exists := storedByPodID[pod.PodID]
+		if !exists {
+			diff.Added = append(diff.Added, &desired)
+			continue
+		}
+		delete(storedByPodID, pod.PodID)
+		if current.State != domain.IntentStateRetracting && scheduleIntentEqual(current, &desired) {
+			continue
+		}
+		desired.BaseEntity = current.BaseEntity
+		desired.UpdaterID = strategy.UpdaterID
+		diff.Changed = append(diff.Changed, &desired)
+	}
+	for _, intent := range stored {
+		if intent.State == domain.IntentStateRetracting {
+			continue
+		}
+		if _, exists := storedByPodID[intent.PodID]; exists {
+			diff.Removed = append(diff.Removed, intent)
+		}
+	}
+	return diff
+}
+
+func scheduleIntentEqual(a, b *domain.ScheduleIntent) bool {
+	return a.NodeID == b.NodeID &&
+		a.PodName == b.PodName &&
+		a.K8sNamespace == b.K8sNamespace &&
+		a.CommandRegex == b.CommandRegex &&
+		a.Priority == b.Priority &&
+		a.ExecutionTime == b.ExecutionTime &&
+		maps.Equal(a.PodLabels, b.PodLabels)
+}
+
+func intentIDs(intents []*domain.ScheduleIntent) []bson.ObjectID {
+	ids := make([]bson.ObjectID, 0, len(intents))
+	for _, intent := range intents {
+		ids = append(ids, intent.ID)
+	}
+	return ids
+}
@@ -7,10 +7,12 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Gthulhu/api/config"
 	"github.com/Gthulhu/api/manager/domain"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/fx"
 )
 
@@ -21,36 +23,76 @@ type Params struct {
 	AccountConfig config.AccountConfig
 	K8SAdapter    domain.K8SAdapter
 	DMAdapter     domain.DecisionMakerAdapter
+	Reconciler    config.ReconcilerConfig
+	Outbox        config.OutboxConfig
+	NodeMetrics   config.NodeMetricsConfig
+	NodeRegistry  config.NodeRegistryConfig
+	DecisionMaker config.DecisionMakerDiscoveryConfig
+	Strategy      config.StrategyConfig
 }
 
-func NewService(params Params) (domain.Service, error) {
+// Result is provided by NewService. NodeMetricsCollector exports the scraped node metrics, it is left to the app to
+// register it so that a service can be built more than once in a process.
+type Result struct {
+	fx.Out
+	Service              domain.Service
+	NodeMetricsCollector prometheus.Collector `name:"node_metrics_collector"`
+}
+
+func NewService(params Params) (Result, error) {
 	jwtPrivateKey, err := initRSAPrivateKey(string(params.KeyConfig.RsaPrivateKeyPem))
 	if err != nil {
-		return nil, fmt.Errorf("initialize RSA private key: %w", err)
+		return Result{}, fmt.Errorf("initialize RSA private key: %w", err)
+	}
+
+	dmDiscovery, err := newDecisionMakerDiscovery(params.DecisionMaker)
+	if err != nil {
+		return Result{}, fmt.Errorf("initialize decision maker discovery: %w", err)
 	}
 
 	svc := &Service{
-		K8SAdapter:    params.K8SAdapter,
-		DMAdapter:     params.DMAdapter,
-		Repo:          params.Repo,
-		jwtPrivateKey: jwtPrivateKey,
+		K8SAdapter:         params.K8SAdapter,
+		DMAdapter:          params.DMAdapter,
+		Repo:               params.Repo,
+		jwtPrivateKey:      jwtPrivateKey,
+		reconcilerConfig:   params.Reconciler,
+		outboxConfig:       params.Outbox,
+		nodeMetricsConfig:  params.NodeMetrics,
+		nodeMetrics:        newNodeMetricsStore(),
+		nodeRegistryConfig: params.NodeRegistry,
+		dmDiscovery:        dmDiscovery,
+		strategyConfig:     params.Strategy,
+		strategyMu:         &sync.Mutex{},
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	err = svc.CreateAdminUserIfNotExists(ctx, params.AccountConfig.AdminEmail, params.AccountConfig.AdminPassword.Value())
 	if err != nil {
-		return nil, fmt.Errorf("create admin user if not exists: %w", err)
+		return Result{}, fmt.Errorf("create admin user if not exists: %w", err)
 	}
 
-	return svc, nil
+	return Result{
+		Service:              svc,
+		NodeMetricsCollector: newNodeMetricsCollector(svc.nodeMetrics),
+	}, nil
 }
 
 type Service struct {
-	K8SAdapter    domain.K8SAdapter
-	DMAdapter     domain.DecisionMakerAdapter
-	Repo          domain.Repository
-	jwtPrivateKey *rsa.PrivateKey
+	K8SAdapter         domain.K8SAdapter
+	DMAdapter          domain.DecisionMakerAdapter
+	Repo               domain.Repository
+	jwtPrivateKey      *rsa.PrivateKey
+	reconcilerConfig   config.ReconcilerConfig
+	outboxConfig       config.OutboxConfig
+	nodeMetricsConfig  config.NodeMetricsConfig
+	nodeMetrics        *nodeMetricsStore
+	nodeRegistryConfig config.NodeRegistryConfig
+	dmDiscovery        decisionMakerDiscovery
+	strategyConfig     config.StrategyConfig
+	// strategyMu serializes the strategy mutations with each other and with the reconciler, so that no intents are
+	// stored for a strategy deleted meanwhile
+	strategyMu *sync.Mutex
 }
 
 func initRSAPrivateKey(pemStr string) (*rsa.PrivateKey, error) {
@@ -73,7 +73,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Write only keeps the body of error responses for logging, so long-lived streams are not buffered
 func (rw *responseWriter) Write(b []byte) (int, error) {
-	rw.responseBody.Write(b)
+	if rw.statusCode >= 400 {
+		rw.responseBody.Write(b)
+	}
 	return rw.ResponseWriter.Write(b)
 }
+
+// Unwrap exposes the underlying writer to http.ResponseController, e.g. to flush streamed responses
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}